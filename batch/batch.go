@@ -0,0 +1,236 @@
+// Package batch 대기열에 등록된 항목을 주기적으로 묶어서 전송
+// gateway 배포 기록 전송 대상(sink)과 server Datadog 전송이 대기열, 묶음 전송 및 재시도를 공유한다.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"sync"
+	"time"
+)
+
+const (
+	defaultQueueSize     = 1000
+	defaultBatchSize     = 100
+	defaultFlushInterval = 5 * time.Second
+	defaultMaxAttempts   = 3
+	// InitialBackoff 첫 재시도 대기 시간. 이후 재시도마다 2배로 늘어난다.
+	InitialBackoff = time.Second
+)
+
+// 전송 결과 (Options.Observe)
+const (
+	Sent    = "sent"
+	Failed  = "failed"
+	Dropped = "dropped"
+)
+
+// Options 대기열 및 재시도 설정
+type Options struct {
+	// 대기열 크기, 한 번에 전송할 최대 항목 수 및 전송 주기
+	QueueSize     int
+	BatchSize     int
+	FlushInterval time.Duration
+	// 재시도 포함 최대 전송 시도 횟수 (backoff 1s, 2s, 4s ...)
+	MaxAttempts int
+	// 전송 결과(Sent, Failed, Dropped)별 항목 수 기록 (메트릭 등, 선택)
+	Observe func(result string, n int)
+}
+
+// SendFunc items를 한 번에 전송. 재시도해도 성공할 수 없는 오류는 Permanent로 감싸 반환한다.
+type SendFunc[T any] func(ctx context.Context, items []T) error
+
+// Sender 대기열에 등록된 항목을 주기적으로 묶어서 전송
+// 전송 대상 장애가 호출자를 지연시키지 않도록 등록은 대기하지 않는다.
+type Sender[T any] struct {
+	name          string
+	send          SendFunc[T]
+	batchSize     int
+	flushInterval time.Duration
+	maxAttempts   int
+	observe       func(result string, n int)
+
+	queue chan T
+	// 종료 요청 및 전송 루프 종료
+	stop chan struct{}
+	done chan struct{}
+	// 종료 대기 시간 초과 시 진행 중인 전송 취소
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	closeOnce sync.Once
+	dropMu    sync.Mutex
+	dropped   int
+}
+
+// New 전송 루프를 시작한 Sender 생성. 종료 시 Close로 남은 항목을 전송한다.
+func New[T any](name string, send SendFunc[T], opts Options) *Sender[T] {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushInterval
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.Observe == nil {
+		opts.Observe = func(string, int) {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Sender[T]{
+		name:          name,
+		send:          send,
+		batchSize:     opts.BatchSize,
+		flushInterval: opts.FlushInterval,
+		maxAttempts:   opts.MaxAttempts,
+		observe:       opts.Observe,
+		queue:         make(chan T, opts.QueueSize),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+	go s.run()
+	return s
+}
+
+// Add 전송 대기열 등록. 대기열이 가득 찬 경우 버린다.
+func (s *Sender[T]) Add(item T) {
+	select {
+	case <-s.stop:
+		return
+	default:
+	}
+
+	select {
+	case s.queue <- item:
+	default:
+		s.dropMu.Lock()
+		s.dropped++
+		s.dropMu.Unlock()
+		s.observe(Dropped, 1)
+	}
+}
+
+// Close 대기열에 남은 항목을 전송한 뒤 종료. ctx가 만료되면 전송을 중단한다.
+func (s *Sender[T]) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+		return fmt.Errorf("%s: pending events dropped: %w", s.name, ctx.Err())
+	}
+}
+
+// run 전송 주기 혹은 batchSize마다 대기열의 항목을 전송
+func (s *Sender[T]) run() {
+	defer close(s.done)
+	defer s.cancel()
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	var items []T
+	flush := func() {
+		s.flush(items)
+		items = nil
+	}
+
+	for {
+		select {
+		case item := <-s.queue:
+			items = append(items, item)
+			if len(items) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.stop:
+			for {
+				select {
+				case item := <-s.queue:
+					items = append(items, item)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *Sender[T]) flush(items []T) {
+	s.dropMu.Lock()
+	dropped := s.dropped
+	s.dropped = 0
+	s.dropMu.Unlock()
+	if dropped > 0 {
+		log.Warn().Msgf("batch | %s queue full, %d events dropped", s.name, dropped)
+	}
+
+	for start := 0; start < len(items); start += s.batchSize {
+		end := min(start+s.batchSize, len(items))
+		if err := s.sendBatch(items[start:end]); err != nil {
+			s.observe(Failed, end-start)
+			log.Warn().Err(err).Msgf("batch | failed to send %d events to %s", end-start, s.name)
+			continue
+		}
+		s.observe(Sent, end-start)
+	}
+}
+
+// sendBatch 재시도 대상 오류 시 backoff 후 최대 maxAttempts회 시도
+func (s *Sender[T]) sendBatch(items []T) error {
+	backoff := InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := s.send(s.ctx, items)
+		if err == nil || !Retryable(err) || attempt == s.maxAttempts {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-s.ctx.Done():
+			return err
+		}
+	}
+}
+
+// permanentError 재시도하지 않는 전송 오류 (잘못된 요청, 인증 실패 등)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 재시도하지 않는 오류로 표시
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent Permanent로 표시된 오류 여부
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Retryable 연결 오류, 429, 5xx 등 재시도 대상 여부
+func Retryable(err error) bool {
+	return !IsPermanent(err) && !errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,72 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSenderObserve(t *testing.T) {
+	var mu sync.Mutex
+	results := map[string]int{}
+	observe := func(result string, n int) {
+		mu.Lock()
+		results[result] += n
+		mu.Unlock()
+	}
+
+	var batches [][]int
+	send := func(_ context.Context, items []int) error {
+		batches = append(batches, append([]int(nil), items...))
+		if items[0] == 3 {
+			return Permanent(errors.New("400 Bad Request"))
+		}
+		return nil
+	}
+
+	s := New("test", send, Options{BatchSize: 2, FlushInterval: time.Hour, Observe: observe})
+	for i := 1; i <= 5; i++ {
+		s.Add(i)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if want := [][]int{{1, 2}, {3, 4}, {5}}; !reflect.DeepEqual(batches, want) {
+		t.Errorf("batches = %v, want %v", batches, want)
+	}
+	// 재시도하지 않는 오류는 1회만 전송한다.
+	if want := map[string]int{Sent: 3, Failed: 2}; !reflect.DeepEqual(results, want) {
+		t.Errorf("results = %v, want %v", results, want)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "connection error", err: errors.New("connection refused"), want: true},
+		{name: "permanent", err: Permanent(errors.New("400 Bad Request"))},
+		{name: "wrapped permanent", err: fmt.Errorf("send: %w", Permanent(errors.New("401 Unauthorized")))},
+		{name: "canceled", err: fmt.Errorf("send: %w", context.Canceled)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Retryable(tc.err); got != tc.want {
+				t.Errorf("Retryable(%v) = %t, want %t", tc.err, got, tc.want)
+			}
+		})
+	}
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) != nil")
+	}
+}
@@ -9,7 +9,10 @@ import (
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/rs/zerolog/log"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -17,6 +20,87 @@ import (
 type Config struct {
 	ServerPort string
 	Timezone   string
+	// /metrics 전용 리스너 포트 (미설정 시 서버 포트에서 제공) 및 인증 토큰
+	MetricsPort  string
+	MetricsToken string
+	// OpenTelemetry trace export 설정
+	Tracing TracingConfig
+	// 배포 기록 전송 대상 설정
+	EventSinks EventSinkConfig
+}
+
+// TracingConfig OpenTelemetry trace export 설정 (OTEL_* 환경 변수)
+// endpoint, header, sampler는 OpenTelemetry SDK가 OTEL_EXPORTER_OTLP_*, OTEL_TRACES_SAMPLER 환경 변수에서 직접 읽는다.
+type TracingConfig struct {
+	ServiceName string
+	// otlp, none (기본값 none)
+	Exporter string
+	// grpc, http/protobuf (기본값 http/protobuf)
+	Protocol string
+}
+
+// EventSinkConfig 배포 기록 전송 대상 설정 (EVENT_SINKS 및 대상별 환경 변수)
+type EventSinkConfig struct {
+	// datadog, splunk, elasticsearch, otlp, file (미설정 시 Datadog API Key가 있으면 datadog)
+	Sinks []string
+	// 대상별 대기열 크기, 묶음 크기, 전송 주기 및 최대 시도 횟수 (0이면 기본값)
+	QueueSize     int
+	BatchSize     int
+	FlushInterval time.Duration
+	MaxAttempts   int
+
+	Datadog       DatadogSinkConfig
+	Splunk        SplunkSinkConfig
+	Elasticsearch ElasticsearchSinkConfig
+	OTLP          OTLPSinkConfig
+	// JSON Lines 파일 경로
+	FilePath string
+}
+
+// DatadogSinkConfig Datadog Logs 전송 설정 (API Key, Site는 Secrets Manager에서 로드)
+type DatadogSinkConfig struct {
+	APIKey string
+	Site   string
+}
+
+// SplunkSinkConfig Splunk HEC 전송 설정 (token은 Secrets Manager에서 로드)
+type SplunkSinkConfig struct {
+	URL        string
+	Token      string
+	Index      string
+	SourceType string
+}
+
+// ElasticsearchSinkConfig Elasticsearch 전송 설정 (API Key, password는 Secrets Manager에서 로드)
+type ElasticsearchSinkConfig struct {
+	URL      string
+	Index    string
+	APIKey   string
+	Username string
+	Password string
+}
+
+// OTLPSinkConfig OTLP logs 전송 설정 (OTEL_EXPORTER_OTLP_LOGS_*, 미설정 시 OTEL_EXPORTER_OTLP_*)
+type OTLPSinkConfig struct {
+	Endpoint string
+	Headers  map[string]string
+}
+
+// LoggingConfig 로그 출력 설정 (LOG_* 환경 변수). Secrets Manager 조회 전 로그에도 적용되도록 별도로 읽는다.
+type LoggingConfig struct {
+	// trace, debug, info, warn, error (기본값 debug)
+	Level string
+	// json, console (기본값 json)
+	Format string
+	// debug, info 로그 샘플링 비율 (N건 중 1건 기록)
+	SampleRate int
+	// 로그 파일 경로 및 로테이션 설정 (크기 MB, 보관 파일 수, 보관 기간 일)
+	File           string
+	FileMaxSize    int
+	FileMaxBackups int
+	FileMaxAge     int
+	// 기본 마스킹 대상 외 추가 필드 이름
+	RedactKeys []string
 }
 
 type Secrets struct {
@@ -25,6 +109,10 @@ type Secrets struct {
 	DatadogSite           string `json:"DATADOG_SITE"`
 	AuthToken             string `json:"AUTH_TOKEN"`
 	RequestToken          string `json:"REQUEST_TOKEN"`
+	MetricsToken          string `json:"METRICS_TOKEN"`
+	SplunkHECToken        string `json:"SPLUNK_HEC_TOKEN"`
+	ElasticsearchAPIKey   string `json:"ELASTICSEARCH_API_KEY"`
+	ElasticsearchPassword string `json:"ELASTICSEARCH_PASSWORD"`
 }
 
 type SecretLoader struct {
@@ -43,6 +131,12 @@ var (
 	defaultPort          = "8080"
 )
 
+const (
+	defaultLogFileMaxSize    = 100
+	defaultLogFileMaxBackups = 5
+	defaultLogFileMaxAge     = 30
+)
+
 func getSecretLoader(region, secretName string) (*SecretLoader, error) {
 	log.Debug().Msg("=====> Getting Secret Loader")
 	var initErr error
@@ -109,6 +203,26 @@ func (sl *SecretLoader) loadSecrets() error {
 		sl.config.ServerPort = port
 	}
 
+	if port := os.Getenv("METRICS_PORT"); port != "" {
+		sl.config.MetricsPort = port
+	}
+	sl.config.MetricsToken = sl.secrets.MetricsToken
+
+	sl.config.Tracing = TracingConfig{
+		ServiceName: "devops-relay-gateway",
+		Exporter:    os.Getenv("OTEL_TRACES_EXPORTER"),
+		Protocol:    os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"),
+	}
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		sl.config.Tracing.ServiceName = name
+	}
+
+	eventSinks, err := loadEventSinks(sl.secrets)
+	if err != nil {
+		return err
+	}
+	sl.config.EventSinks = eventSinks
+
 	// 환경변수에서 타임존 설정 가져오기 (설정되어 있지 않으면 기본값 사용)
 	if tz := os.Getenv("TIMEZONE"); tz != "" {
 		sl.config.Timezone = tz
@@ -173,6 +287,128 @@ func (sl *SecretLoader) GetConfig() (*Config, error) {
 	return sl.config, nil
 }
 
+// loadEventSinks EVENT_SINKS 및 대상별 환경 변수에서 배포 기록 전송 대상 설정 조회
+func loadEventSinks(secrets *Secrets) (EventSinkConfig, error) {
+	cfg := EventSinkConfig{
+		Datadog: DatadogSinkConfig{
+			APIKey: secrets.DatadogAPIKey,
+			Site:   secrets.DatadogSite,
+		},
+		Splunk: SplunkSinkConfig{
+			URL:        os.Getenv("SPLUNK_HEC_URL"),
+			Token:      secrets.SplunkHECToken,
+			Index:      os.Getenv("SPLUNK_HEC_INDEX"),
+			SourceType: os.Getenv("SPLUNK_HEC_SOURCETYPE"),
+		},
+		Elasticsearch: ElasticsearchSinkConfig{
+			URL:      os.Getenv("ELASTICSEARCH_URL"),
+			Index:    os.Getenv("ELASTICSEARCH_INDEX"),
+			APIKey:   secrets.ElasticsearchAPIKey,
+			Username: os.Getenv("ELASTICSEARCH_USERNAME"),
+			Password: secrets.ElasticsearchPassword,
+		},
+		OTLP: OTLPSinkConfig{
+			Endpoint: os.Getenv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"),
+			Headers:  map[string]string{},
+		},
+		FilePath: os.Getenv("EVENT_SINK_FILE"),
+	}
+
+	if sinks := os.Getenv("EVENT_SINKS"); sinks != "" {
+		for _, name := range strings.Split(sinks, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" && name != "none" {
+				cfg.Sinks = append(cfg.Sinks, name)
+			}
+		}
+	} else if secrets.DatadogAPIKey != "" {
+		// 기존 설정 호환: Datadog API Key가 있으면 Datadog Logs로 전송
+		cfg.Sinks = []string{"datadog"}
+	}
+
+	for env, target := range map[string]*int{
+		"EVENT_SINK_QUEUE_SIZE":   &cfg.QueueSize,
+		"EVENT_SINK_BATCH_SIZE":   &cfg.BatchSize,
+		"EVENT_SINK_MAX_ATTEMPTS": &cfg.MaxAttempts,
+	} {
+		if value := os.Getenv(env); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return cfg, fmt.Errorf("LoadSecrets | invalid %s %q", env, value)
+			}
+			*target = n
+		}
+	}
+	if value := os.Getenv("EVENT_SINK_FLUSH_INTERVAL"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("LoadSecrets | invalid EVENT_SINK_FLUSH_INTERVAL %q", value)
+		}
+		cfg.FlushInterval = d
+	}
+
+	// OTLP logs 주소 미설정 시 trace와 같은 Collector의 /v1/logs 사용
+	if cfg.OTLP.Endpoint == "" {
+		if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+			cfg.OTLP.Endpoint = strings.TrimSuffix(endpoint, "/") + "/v1/logs"
+		}
+	}
+	headers := os.Getenv("OTEL_EXPORTER_OTLP_LOGS_HEADERS")
+	if headers == "" {
+		headers = os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")
+	}
+	for _, pair := range strings.Split(headers, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		// OTEL_EXPORTER_OTLP_HEADERS 값은 URL 인코딩 형식
+		if decoded, err := url.PathUnescape(strings.TrimSpace(value)); err == nil {
+			value = decoded
+		}
+		if key != "" {
+			cfg.OTLP.Headers[key] = value
+		}
+	}
+	return cfg, nil
+}
+
+// Logging LOG_* 환경 변수에서 로그 출력 설정 조회
+func Logging() (LoggingConfig, error) {
+	cfg := LoggingConfig{
+		Level:          strings.ToLower(os.Getenv("LOG_LEVEL")),
+		Format:         strings.ToLower(os.Getenv("LOG_FORMAT")),
+		File:           os.Getenv("LOG_FILE"),
+		FileMaxSize:    defaultLogFileMaxSize,
+		FileMaxBackups: defaultLogFileMaxBackups,
+		FileMaxAge:     defaultLogFileMaxAge,
+	}
+
+	for env, target := range map[string]*int{
+		"LOG_SAMPLE_RATE":      &cfg.SampleRate,
+		"LOG_FILE_MAX_SIZE":    &cfg.FileMaxSize,
+		"LOG_FILE_MAX_BACKUPS": &cfg.FileMaxBackups,
+		"LOG_FILE_MAX_AGE":     &cfg.FileMaxAge,
+	} {
+		if value := os.Getenv(env); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return cfg, fmt.Errorf("Logging | invalid %s %q", env, value)
+			}
+			*target = n
+		}
+	}
+
+	if keys := os.Getenv("LOG_REDACT_KEYS"); keys != "" {
+		for _, key := range strings.Split(keys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				cfg.RedactKeys = append(cfg.RedactKeys, key)
+			}
+		}
+	}
+	return cfg, nil
+}
+
 func Setting() *Config {
 	log.Debug().Msg("=====> Initialize Secret Loader")
 	// Instance 생성
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/gateway/config"
+	"github.com/antonio-kim-1994/devops-relay/gateway/logging"
+	"github.com/antonio-kim-1994/devops-relay/gateway/requestid"
+	"github.com/antonio-kim-1994/devops-relay/gateway/sink"
+	"github.com/antonio-kim-1994/devops-relay/gateway/tracing"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"os"
+	"time"
+)
+
+// eventSinks 배포 기록 전송 대상 (EVENT_SINKS 미설정 시 비어 있음)
+var eventSinks sink.Sinks
+
+// SetupEventSinks 설정된 배포 기록 전송 대상 생성. 대상별로 대기열과 재시도를 둔다.
+func SetupEventSinks(cfg config.EventSinkConfig, service string) error {
+	// host 이름 (DD_HOSTNAME 미설정 시 Pod 이름)
+	hostname := os.Getenv("DD_HOSTNAME")
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+	httpClient := func(name string) *http.Client {
+		return &http.Client{Timeout: 10 * time.Second, Transport: tracing.Transport(name, nil)}
+	}
+
+	opts := sink.Options{
+		QueueSize:     cfg.QueueSize,
+		BatchSize:     cfg.BatchSize,
+		FlushInterval: cfg.FlushInterval,
+		MaxAttempts:   cfg.MaxAttempts,
+	}
+
+	sinks := make(sink.Sinks, 0, len(cfg.Sinks))
+	for _, name := range cfg.Sinks {
+		var s sink.EventSink
+		switch name {
+		case "datadog":
+			if cfg.Datadog.APIKey == "" {
+				return fmt.Errorf("SetupEventSinks | datadog sink requires DATADOG_API_KEY")
+			}
+			s = sink.NewDatadog(sink.DatadogOptions{
+				Site:       cfg.Datadog.Site,
+				APIKey:     cfg.Datadog.APIKey,
+				Host:       hostname,
+				Service:    service,
+				HTTPClient: httpClient("datadog"),
+			})
+		case "splunk":
+			if cfg.Splunk.URL == "" || cfg.Splunk.Token == "" {
+				return fmt.Errorf("SetupEventSinks | splunk sink requires SPLUNK_HEC_URL and SPLUNK_HEC_TOKEN")
+			}
+			s = sink.NewSplunk(sink.SplunkOptions{
+				URL:        cfg.Splunk.URL,
+				Token:      cfg.Splunk.Token,
+				Index:      cfg.Splunk.Index,
+				SourceType: cfg.Splunk.SourceType,
+				Host:       hostname,
+				HTTPClient: httpClient("splunk"),
+			})
+		case "elasticsearch":
+			if cfg.Elasticsearch.URL == "" {
+				return fmt.Errorf("SetupEventSinks | elasticsearch sink requires ELASTICSEARCH_URL")
+			}
+			s = sink.NewElasticsearch(sink.ElasticsearchOptions{
+				URL:        cfg.Elasticsearch.URL,
+				Index:      cfg.Elasticsearch.Index,
+				APIKey:     cfg.Elasticsearch.APIKey,
+				Username:   cfg.Elasticsearch.Username,
+				Password:   cfg.Elasticsearch.Password,
+				HTTPClient: httpClient("elasticsearch"),
+			})
+		case "otlp":
+			if cfg.OTLP.Endpoint == "" {
+				return fmt.Errorf("SetupEventSinks | otlp sink requires OTEL_EXPORTER_OTLP_LOGS_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT")
+			}
+			s = sink.NewOTLP(sink.OTLPOptions{
+				Endpoint:    cfg.OTLP.Endpoint,
+				Headers:     cfg.OTLP.Headers,
+				ServiceName: service,
+				Host:        hostname,
+				// OTLP 전송 요청은 trace에 기록하지 않는다.
+				HTTPClient: &http.Client{Timeout: 10 * time.Second},
+			})
+		case "file":
+			if cfg.FilePath == "" {
+				return fmt.Errorf("SetupEventSinks | file sink requires EVENT_SINK_FILE")
+			}
+			f, err := sink.NewFile(cfg.FilePath)
+			if err != nil {
+				return fmt.Errorf("SetupEventSinks | %w", err)
+			}
+			s = f
+		default:
+			return fmt.Errorf("SetupEventSinks | unknown event sink %q", name)
+		}
+		sinks = append(sinks, sink.NewBuffered(s, opts))
+		log.Info().Msgf("SetupEventSinks | deploy event sink enabled: %s", name)
+	}
+
+	eventSinks = sinks
+	return nil
+}
+
+// CloseEventSinks 대기열에 남은 배포 기록을 전송한 뒤 종료
+func CloseEventSinks(ctx context.Context) error {
+	return eventSinks.Close(ctx)
+}
+
+// sendDeployEvent 배포 요청 기록을 전송 대상 대기열에 등록
+// 전송 대상 장애가 배포 요청 응답을 지연시키지 않도록 대기열 등록 이후 별도로 전송한다.
+func sendDeployEvent(ctx context.Context, s *ServiceInfo) {
+	if len(eventSinks) == 0 {
+		return
+	}
+
+	// Datadog Unified Service Tagging (env, service, version)
+	tags := map[string]string{
+		"env":     s.Branch,
+		"service": s.ApplicationName,
+		"version": s.DockerTag,
+		"org":     s.Org,
+		"repo":    s.Repo,
+	}
+	if s.Team != "" {
+		tags["team"] = s.Team
+	}
+
+	span := trace.SpanContextFromContext(ctx)
+	e := sink.Event{
+		ID:      s.DeploymentID,
+		Time:    time.Now(),
+		Message: logging.Redact(fmt.Sprintf("[%s] %s service deployed.", s.Branch, s.ApplicationName)),
+		Tags:    tags,
+		// 커밋 메시지 등 사용자 입력에 포함된 URL 비밀 값 및 토큰은 마스킹해 전송
+		Fields: logging.RedactFields(map[string]interface{}{
+			"date":                  s.Date,
+			"org":                   s.Org,
+			"repository":            s.Repo,
+			"branch":                s.Branch,
+			"application_name":      s.ApplicationName,
+			"application_namespace": s.ApplicationNamespace,
+			"docker_tag":            s.DockerTag,
+			"team":                  s.Team,
+			"operator":              s.Operator,
+			"commit":                s.CommitMessage,
+			"deployment_id":         s.DeploymentID,
+			"request_id":            requestid.From(ctx),
+		}),
+	}
+	if span.IsValid() {
+		e.TraceID = span.TraceID().String()
+		e.SpanID = span.SpanID().String()
+	}
+	eventSinks.Emit(e)
+}
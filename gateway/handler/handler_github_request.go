@@ -2,6 +2,9 @@ package handler
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +13,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"regexp"
 	"time"
 )
 
@@ -18,10 +22,27 @@ type addresses struct {
 	prod string
 }
 
+// breakGlassHeader 배포 동결 기간 긴급 배포(break-glass) 인증 토큰 헤더 (Server BREAK_GLASS_TOKEN)
+const breakGlassHeader = "Break-Glass-Auth"
+
+// 배포 ID는 Slack 버튼 값 및 API 경로에 포함되므로 허용 문자와 길이를 제한한다. (Server와 동일)
+var deploymentIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// newDeploymentID 배포 ID 발급 (Server의 배포 ID와 같은 형식)
+func newDeploymentID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("dep-%d", time.Now().UnixNano())
+	}
+	return fmt.Sprintf("dep-%s-%s", time.Now().Format("20060102"), hex.EncodeToString(b))
+}
+
 func GithubRequestHandler(c *gin.Context) {
+	// 요청 취소와 무관하게 Server 전달을 마치도록 요청 context는 trace 연결에만 사용
+	ctx := context.WithoutCancel(c.Request.Context())
 	var s ServiceInfo
 	if err := c.ShouldBindJSON(&s); err != nil {
-		log.Error().Err(err).Msgf("failed to bind service info")
+		log.Ctx(ctx).Error().Err(err).Msgf("failed to bind service info")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "failed to get service info",
 			"status":  "failed",
@@ -29,9 +50,22 @@ func GithubRequestHandler(c *gin.Context) {
 		return
 	}
 
+	// 배포 ID 발급. GitHub Actions 재시도 시 같은 배포 ID를 전달하면 중복 배포되지 않는다.
+	if s.DeploymentID == "" {
+		s.DeploymentID = newDeploymentID()
+	} else if !deploymentIDPattern.MatchString(s.DeploymentID) {
+		log.Ctx(ctx).Error().Msgf("GithubRequestHandler | invalid deployment id: %q", s.DeploymentID)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "invalid deployment id",
+			"status":  "failed",
+		})
+		return
+	}
+	ctx = log.Ctx(ctx).With().Str("deployment_id", s.DeploymentID).Logger().WithContext(ctx)
+
 	url, err := getTargetServerURL(s.ApplicationName, s.Org, s.Branch)
 	if err != nil {
-		log.Error().Err(err).Msgf("failed to get target server")
+		log.Ctx(ctx).Error().Err(err).Msgf("failed to get target server")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "failed to get target server endpoint",
 			"error":   fmt.Sprintf("%v", err),
@@ -40,11 +74,12 @@ func GithubRequestHandler(c *gin.Context) {
 		return
 	}
 
-	log.Info().Msgf("GithubRequestHandler | target url: %s", url)
+	log.Ctx(ctx).Info().Msgf("GithubRequestHandler | target url: %s", url)
 
-	err = sendGithubRequestInfo(&s, url)
+	// 배포 동결 기간 긴급 배포(break-glass) 인증 토큰은 Server에서 확인
+	body, status, err := sendGithubRequestInfo(ctx, &s, url, c.GetHeader(breakGlassHeader))
 	if err != nil {
-		log.Error().Err(err).Msgf("failed to send service info")
+		log.Ctx(ctx).Error().Err(err).Msgf("failed to send service info")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "failed to send service info",
 			"status":  "failed",
@@ -52,54 +87,93 @@ func GithubRequestHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("%s | Sync success.", s.ApplicationName),
-		"status":  "success",
-	})
+	// 배포 동결 등 Server에서 배포를 거부한 경우 사유를 GitHub Actions로 전달
+	if status >= http.StatusBadRequest {
+		var r struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+		}
+		if err := json.Unmarshal(body, &r); err != nil || r.Message == "" {
+			r.Message = string(body)
+		}
+		if r.Status == "" {
+			r.Status = "failed"
+		}
+
+		log.Ctx(ctx).Warn().Msgf("GithubRequestHandler | request rejected by server (%d): %s", status, r.Message)
+		c.JSON(status, gin.H{
+			"message":       fmt.Sprintf("%s | %s", s.ApplicationName, r.Message),
+			"deployment_id": s.DeploymentID,
+			"status":        r.Status,
+		})
+		return
+	}
 
-	// Datadog Deploy Histry 저장
-	err = sendDeployInfoToDatadog(&s)
-	if err != nil {
-		log.Error().Err(err).Msgf("failed to send service info")
+	// Server는 배포 파이프라인을 비동기로 실행하고 배포 ID를 반환 (202 Accepted)
+	var r struct {
+		DeploymentID string `json:"deployment_id"`
+		Status       string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("GithubRequestHandler | failed to parse server response")
+	}
+	if r.DeploymentID == "" {
+		r.DeploymentID = s.DeploymentID
+	}
+
+	message := fmt.Sprintf("%s | Sync success.", s.ApplicationName)
+	if status == http.StatusAccepted {
+		message = fmt.Sprintf("%s | Deployment accepted.", s.ApplicationName)
+	}
+	if r.Status == "" {
+		r.Status = "success"
 	}
+
+	c.JSON(status, gin.H{
+		"message":       message,
+		"deployment_id": r.DeploymentID,
+		"status":        r.Status,
+	})
+
+	// 배포 기록 전송 (Datadog, Splunk, Elasticsearch 등. 비동기)
+	sendDeployEvent(ctx, &s)
 	return
 }
 
-func sendGithubRequestInfo(s *ServiceInfo, url string) error {
+func sendGithubRequestInfo(ctx context.Context, s *ServiceInfo, url, breakGlassToken string) ([]byte, int, error) {
 	path := "update/github"
 	data, err := json.Marshal(s)
 	if err != nil {
-		return err
+		return nil, http.StatusInternalServerError, err
 	}
 
-	client := &http.Client{
-		Timeout: time.Second * 10,
-	}
-
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", url, path), bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s", url, path), bytes.NewBuffer(data))
 	if err != nil {
-		return err
+		return nil, http.StatusInternalServerError, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Request-Auth", os.Getenv("REQUEST_TOKEN"))
+	if breakGlassToken != "" {
+		req.Header.Set(breakGlassHeader, breakGlassToken)
+	}
 
-	log.Info().
+	log.Ctx(ctx).Info().
 		Str("url", url).
 		Interface("request", s).
 		Msgf("Sending POST request to %s", url)
 
-	resp, err := client.Do(req)
+	resp, err := doRelayRequest(relayClient, req, url, path)
 	if err != nil {
-		return errors.New(fmt.Sprintf("sendSlackResponse | failed to send request to %s", url))
+		return nil, http.StatusInternalServerError, errors.New(fmt.Sprintf("sendSlackResponse | failed to send request to %s", url))
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return errors.New(fmt.Sprintf("sendGithubRequestInfo | failed to read response from %s", url))
+		return nil, http.StatusInternalServerError, errors.New(fmt.Sprintf("sendGithubRequestInfo | failed to read response from %s", url))
 	}
 
-	log.Info().Msgf("sendGithubRequestInfo | response: %s", string(body))
-	return nil
+	log.Ctx(ctx).Info().Msgf("sendGithubRequestInfo | response: %s", string(body))
+	return body, resp.StatusCode, nil
 }
@@ -1,6 +1,19 @@
 package handler
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/gateway/metrics"
+	"github.com/antonio-kim-1994/devops-relay/gateway/requestid"
+	"github.com/antonio-kim-1994/devops-relay/gateway/tracing"
+	"net/http"
+	"time"
+)
+
+// relayClient Relay Server 요청 client. 요청마다 client span을 생성하고 traceparent 헤더를 전달한다.
+var relayClient = &http.Client{
+	Timeout:   time.Second * 10,
+	Transport: tracing.Transport("relay", nil),
+}
 
 var relayServers = map[string]addresses{
 	"aws": {
@@ -46,3 +59,20 @@ func getTargetServerURL(appName, org, branch string) (string, error) {
 
 	return target, nil
 }
+
+// doRelayRequest Relay Server 요청 전송 및 대상 서버별 결과 메트릭 기록
+// 요청 ID(X-Request-ID)를 함께 전달해 Gateway, Server 로그를 같은 요청 ID로 연결한다.
+func doRelayRequest(client *http.Client, req *http.Request, target, path string) (*http.Response, error) {
+	if id := requestid.From(req.Context()); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		metrics.ObserveRelay(target, path, 0, time.Since(start))
+		return nil, err
+	}
+	metrics.ObserveRelay(target, path, resp.StatusCode, time.Since(start))
+	return resp, nil
+}
@@ -2,9 +2,11 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/gateway/tracing"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 	"github.com/slack-go/slack"
@@ -12,7 +14,6 @@ import (
 	"net/http"
 	"os"
 	"strings"
-	"time"
 )
 
 type slackResponseForm struct {
@@ -22,10 +23,13 @@ type slackResponseForm struct {
 }
 
 func SlackResponseHandler(c *gin.Context) {
+	// Slack은 3초 이후 요청을 끊으므로 요청 context는 trace 연결에만 사용
+	ctx := context.WithoutCancel(c.Request.Context())
+
 	// Payload Parsing
 	payload, err := parsePayload(c)
 	if err != nil {
-		log.Err(err)
+		log.Ctx(ctx).Err(err)
 		// Slack Callback을 위해 200 응답. 200 응답 외의 응답은 서비스 장애로 인식한다.
 		c.JSON(http.StatusOK, gin.H{
 			"message": "failed to parse payload",
@@ -35,8 +39,33 @@ func SlackResponseHandler(c *gin.Context) {
 	}
 
 	// Button Value parsing
-	splitPayload := strings.Split(payload.ActionCallback.BlockActions[0].Value, "/")
-	log.Debug().Msgf("Slack Button Value: %+v", splitPayload)
+	if len(payload.ActionCallback.BlockActions) == 0 {
+		log.Ctx(ctx).Error().Msg("SlackResponseHandler | no block action received")
+		c.JSON(http.StatusOK, gin.H{
+			"message": "no block action received",
+			"status":  "failed",
+		})
+		return
+	}
+
+	// 버튼은 Value, 선택 메뉴(Canary 가중치)는 선택된 옵션의 Value를 사용
+	action := payload.ActionCallback.BlockActions[0]
+	value := action.Value
+	if action.Type == slack.ActionType(slack.OptTypeStatic) {
+		value = action.SelectedOption.Value
+	}
+
+	splitPayload := strings.Split(value, "/")
+	log.Ctx(ctx).Debug().Msgf("Slack Button Value: %+v", splitPayload)
+
+	if len(splitPayload) < 6 {
+		log.Ctx(ctx).Error().Msgf("SlackResponseHandler | invalid button value: %s", value)
+		c.JSON(http.StatusOK, gin.H{
+			"message": "invalid button value",
+			"status":  "failed",
+		})
+		return
+	}
 
 	r := SlackResponse{
 		ResponseURL: payload.ResponseURL,
@@ -54,11 +83,33 @@ func SlackResponseHandler(c *gin.Context) {
 		},
 	}
 
+	// 배포 ID가 포함된 승인 요청 (이전 형식의 버튼은 배포 ID가 없음)
+	if len(splitPayload) > 6 {
+		r.Button.DeploymentID = splitPayload[6]
+	}
+
+	// Rollout 제어 인자 (Canary 가중치)
+	if len(splitPayload) > 7 {
+		r.Button.Argument = splitPayload[7]
+	}
+
+	// 배포를 시작한 요청 ID (이전 형식의 버튼은 요청 ID가 없음)
+	if len(splitPayload) > 8 {
+		r.Button.RequestID = splitPayload[8]
+	}
+
+	// 버튼의 배포 ID, 원본 요청 ID를 요청 로거에 추가해 GitHub 배포 요청 로그와 연결
+	ctx = log.Ctx(ctx).With().
+		Str("deployment_id", r.Button.DeploymentID).
+		Str("origin_request_id", r.Button.RequestID).
+		Logger().
+		WithContext(ctx)
+
 	// Get Target Server URL
 	url, err := getTargetServerURL(r.Button.ApplicationName, r.Button.Org, r.Button.Branch)
 	if err != nil {
 		// Slack Callback을 위해 200 응답. 200 응답 외의 응답은 서비스 장애로 인식한다.
-		log.Error().Err(err).Msgf("failed to get target server")
+		log.Ctx(ctx).Error().Err(err).Msgf("failed to get target server")
 		c.JSON(http.StatusOK, gin.H{
 			"message": "failed to get target server endpoint",
 			"status":  "failed",
@@ -66,23 +117,23 @@ func SlackResponseHandler(c *gin.Context) {
 		return
 	}
 
-	log.Info().Msgf("SlackResponseHandler | target server: %s", url)
+	log.Ctx(ctx).Info().Msgf("SlackResponseHandler | target server: %s", url)
 
 	// Slack Response
 	// Server에서 처리 후 Slack 응답을 전송하기에는 환경이 분리되어 있어 처리에 시간 소요.
 	// Gateway에서 선제적으로 응답 후 server에서 처리
 	var reply slackResponseForm
 	switch r.Button.Result {
-	case "approve":
+	case "approve", "approve-full":
 		reply = slackResponseForm{
 			url:           r.ResponseURL,
 			msg:           generateSlackTextBlock(fmt.Sprintf(":white_check_mark: *운영 배포 승인* | *%s* 사용자에 의해 *%s* 배포가 승인되었습니다.", r.User.Name, r.Button.ApplicationName)),
 			replaceOption: true,
 		}
 
-		err = reply.sendResponseToSlack()
+		err = reply.sendResponseToSlack(ctx)
 		if err != nil {
-			log.Err(err)
+			log.Ctx(ctx).Err(err)
 			return
 		}
 	case "reject":
@@ -91,17 +142,28 @@ func SlackResponseHandler(c *gin.Context) {
 			msg: generateSlackTextBlock(fmt.Sprintf(":no_entry: *운영 배포 반려* | *%s* 사용자에 의해 *%s* 배포가 반려되었습니다.", r.User.Name, r.Button.ApplicationName)),
 		}
 
-		err = reply.sendResponseToSlack()
+		err = reply.sendResponseToSlack(ctx)
 		if err != nil {
-			log.Err(err)
+			log.Ctx(ctx).Err(err)
+			return
+		}
+	case "rollback":
+		reply = slackResponseForm{
+			url: r.ResponseURL,
+			msg: generateSlackTextBlock(fmt.Sprintf(":rewind: *롤백 요청* | *%s* 사용자에 의해 *%s* 롤백이 요청되었습니다.", r.User.Name, r.Button.ApplicationName)),
+		}
+
+		err = reply.sendResponseToSlack(ctx)
+		if err != nil {
+			log.Ctx(ctx).Err(err)
 			return
 		}
 	}
 
 	// Relay Server로 데이터 전송
-	err = sendSlackResponseToServer(&r, url)
+	err = sendSlackResponseToServer(ctx, &r, url)
 	if err != nil {
-		log.Error().Err(err).Msgf("failed to send service info")
+		log.Ctx(ctx).Error().Err(err).Msgf("failed to send service info")
 		return
 	}
 	return
@@ -125,18 +187,14 @@ func parsePayload(c *gin.Context) (*slack.InteractionCallback, error) {
 	return &payload, nil
 }
 
-func sendSlackResponseToServer(s *SlackResponse, url string) error {
+func sendSlackResponseToServer(ctx context.Context, s *SlackResponse, url string) error {
 	path := "update/slack"
 	data, err := json.Marshal(s)
 	if err != nil {
 		return err
 	}
 
-	client := &http.Client{
-		Timeout: time.Second * 10,
-	}
-
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", url, path), bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s", url, path), bytes.NewBuffer(data))
 	if err != nil {
 		return err
 	}
@@ -144,24 +202,24 @@ func sendSlackResponseToServer(s *SlackResponse, url string) error {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Request-Auth", os.Getenv("REQUEST_TOKEN"))
 
-	log.Info().
+	log.Ctx(ctx).Info().
 		Str("url", url).
 		Interface("request", s).
 		Msgf("Sending POST request to %s", url)
 
-	resp, err := client.Do(req)
+	resp, err := doRelayRequest(relayClient, req, url, path)
 	if err != nil {
 		return errors.New(fmt.Sprintf("sendSlackResponse | failed to send request to %s", url))
 	}
 	defer resp.Body.Close()
-	log.Info().Msgf("Successfully sent request to %s", url)
+	log.Ctx(ctx).Info().Msgf("Successfully sent request to %s", url)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return errors.New(fmt.Sprintf("sendSlackResponse | failed to read response from %s", url))
 	}
 
-	log.Info().Msgf("sendGithubRequestInfo | response: %s", string(body))
+	log.Ctx(ctx).Info().Msgf("sendGithubRequestInfo | response: %s", string(body))
 	return nil
 }
 
@@ -173,8 +231,11 @@ func generateSlackTextBlock(text string) slack.Blocks {
 	}
 }
 
-func (s slackResponseForm) sendResponseToSlack() error {
-	err := slack.PostWebhook(s.url, &slack.WebhookMessage{Blocks: &s.msg, ReplaceOriginal: s.replaceOption})
+// sendResponseToSlack Slack response_url 응답 전송. URL에 인증 정보가 포함되므로 span에는 URL을 기록하지 않는다.
+func (s slackResponseForm) sendResponseToSlack(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "slack webhook")
+	err := slack.PostWebhookContext(ctx, s.url, &slack.WebhookMessage{Blocks: &s.msg, ReplaceOriginal: s.replaceOption})
+	tracing.End(span, err)
 	if err != nil {
 		return err
 	}
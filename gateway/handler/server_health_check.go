@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
@@ -9,7 +10,6 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"time"
 )
 
 func GatewayHealthCheck(c *gin.Context) {
@@ -19,9 +19,10 @@ func GatewayHealthCheck(c *gin.Context) {
 }
 
 func ServerHealthCheck(c *gin.Context) {
+	ctx := c.Request.Context()
 	var h HealthCheckRequest
 	if err := c.ShouldBindJSON(&h); err != nil {
-		log.Error().Err(err).Msgf("ServerHealthCheck | failed to bind service info")
+		log.Ctx(ctx).Error().Err(err).Msgf("ServerHealthCheck | failed to bind service info")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "failed to get service info",
 			"status":  "failed",
@@ -31,7 +32,7 @@ func ServerHealthCheck(c *gin.Context) {
 
 	url, err := getTargetServerURL(h.ApplicationName, h.Org, h.Branch)
 	if err != nil {
-		log.Error().Err(err).Msgf("ServerHealthCheck | failed to get target server")
+		log.Ctx(ctx).Error().Err(err).Msgf("ServerHealthCheck | failed to get target server")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "failed to get target server endpoint",
 			"status":  "failed",
@@ -39,11 +40,11 @@ func ServerHealthCheck(c *gin.Context) {
 		return
 	}
 
-	log.Info().Msgf("ServerHealthCheck | target url: %s", url)
+	log.Ctx(ctx).Info().Msgf("ServerHealthCheck | target url: %s", url)
 
-	body, status, err := sendHealthcheckRequest(h, url)
+	body, status, err := sendHealthcheckRequest(ctx, h, url)
 	if err != nil {
-		log.Error().Err(err).Msgf("ServerHealthCheck | failed to send healthcheck request")
+		log.Ctx(ctx).Error().Err(err).Msgf("ServerHealthCheck | failed to send healthcheck request")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "failed to send healthcheck request",
 			"status":  "failed",
@@ -57,7 +58,7 @@ func ServerHealthCheck(c *gin.Context) {
 	var r result
 
 	if err := json.Unmarshal(body, &r); err != nil {
-		log.Error().Err(err).Msgf("ServerHealthCheck | failed to unmarshal body")
+		log.Ctx(ctx).Error().Err(err).Msgf("ServerHealthCheck | failed to unmarshal body")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "failed to unmarshal body",
 			"status":  "failed",
@@ -72,18 +73,14 @@ func ServerHealthCheck(c *gin.Context) {
 	return
 }
 
-func sendHealthcheckRequest(h HealthCheckRequest, url string) ([]byte, int, error) {
+func sendHealthcheckRequest(ctx context.Context, h HealthCheckRequest, url string) ([]byte, int, error) {
 	path := "sys/healthcheck"
 	data, err := json.Marshal(h)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
 
-	client := &http.Client{
-		Timeout: time.Second * 10,
-	}
-
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", url, path), bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s", url, path), bytes.NewBuffer(data))
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
@@ -91,22 +88,22 @@ func sendHealthcheckRequest(h HealthCheckRequest, url string) ([]byte, int, erro
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Request-Auth", os.Getenv("REQUEST_TOKEN"))
 
-	log.Info().
+	log.Ctx(ctx).Info().
 		Str("url", url).
 		Interface("request", h).
 		Msgf("Sending POST request to %s", url)
 
-	resp, err := client.Do(req)
+	resp, err := doRelayRequest(relayClient, req, url, path)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Error().Err(err).Msgf("sendHealthcheckRequest | failed to read response body")
+		log.Ctx(ctx).Error().Err(err).Msgf("sendHealthcheckRequest | failed to read response body")
 		return nil, http.StatusInternalServerError, err
 	}
 
-	log.Info().Msgf("sendHealthcheckRequest | Successfully sent request to %s", url)
+	log.Ctx(ctx).Info().Msgf("sendHealthcheckRequest | Successfully sent request to %s", url)
 	return body, resp.StatusCode, nil
 }
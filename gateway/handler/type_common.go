@@ -11,6 +11,15 @@ type ServiceInfo struct {
 	Branch               string `json:"branch" binding:"required"`
 	ApplicationName      string `json:"application_name" binding:"required"`
 	ApplicationNamespace string `json:"application_namespace" binding:"required"`
+	// 배포 동결 기간 중 관리자 긴급 배포(break-glass) 요청
+	BreakGlass       bool   `json:"break_glass,omitempty"`
+	BreakGlassReason string `json:"break_glass_reason,omitempty"`
+	// 배포 ID. 미지정 시 Gateway에서 발급하며, 같은 배포 ID의 재요청은 Server에서 중복 배포로 거부된다.
+	DeploymentID string `json:"deployment_id,omitempty"`
+	// 애플리케이션 담당 팀 (Datadog team 태그. Server 애플리케이션 설정의 team이 우선)
+	Team string `json:"team,omitempty"`
+	// 배포 대상 커밋 시각 (RFC3339, DORA 변경 리드 타임 기준)
+	CommitTimestamp string `json:"commit_timestamp,omitempty"`
 }
 
 type SlackResponse struct {
@@ -20,7 +29,8 @@ type SlackResponse struct {
 }
 
 // Button Value
-// Org/Branch/ApplicationName/ApplicationNamespace/deploy/approve, reject
+// Org/Branch/ApplicationName/ApplicationNamespace/deploy/approve, approve-full, reject, rollback/DeploymentID[//RequestID]
+// Org/Branch/ApplicationName/ApplicationNamespace/rollout/<action>/DeploymentID[/Argument][/RequestID]
 type ButtonValue struct {
 	Org                  string `json:"org"`
 	Branch               string `json:"branch"`
@@ -28,6 +38,11 @@ type ButtonValue struct {
 	ApplicationNamespace string `json:"application_namespace"`
 	RequestType          string `json:"request_type"`
 	Result               string `json:"result"`
+	DeploymentID         string `json:"deployment_id,omitempty"`
+	// Rollout 제어 인자 (set-weight 가중치)
+	Argument string `json:"argument,omitempty"`
+	// 배포를 시작한 요청 ID (승인 요청 메시지를 만든 GitHub 배포 요청)
+	RequestID string `json:"request_id,omitempty"`
 }
 
 type User struct {
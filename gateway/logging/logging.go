@@ -0,0 +1,80 @@
+// Package logging 전역 zerolog 로거 설정 (레벨, 출력 형식, 샘플링, 파일 로테이션, 민감 정보 마스킹)
+package logging
+
+import (
+	"fmt"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
+	"os"
+	"time"
+)
+
+// Options 로그 출력 설정
+type Options struct {
+	// trace, debug, info, warn, error (기본값 debug)
+	Level string
+	// json, console (기본값 json). 파일 출력은 항상 json
+	Format string
+	// debug, info 로그를 N건 중 1건만 기록 (0, 1은 샘플링 없음). warn 이상은 항상 기록한다.
+	SampleRate int
+	// 설정 시 표준 에러와 함께 파일에 기록
+	File string
+	// 파일 로테이션 기준 크기(MB), 보관할 이전 파일 수 및 보관 기간(일)
+	FileMaxSize    int
+	FileMaxBackups int
+	FileMaxAge     int
+	// 기본 마스킹 대상 외 추가 필드 이름
+	RedactKeys []string
+}
+
+// Setup 전역 로거(log.Logger) 설정. 모든 로그 이벤트는 출력 전 민감 정보를 마스킹한다.
+// 반환된 함수로 로그 파일을 닫는다.
+func Setup(opts Options) (func() error, error) {
+	level := zerolog.DebugLevel
+	if opts.Level != "" {
+		l, err := zerolog.ParseLevel(opts.Level)
+		if err != nil || l == zerolog.NoLevel {
+			return nil, fmt.Errorf("logging: unknown level %q (trace, debug, info, warn, error)", opts.Level)
+		}
+		level = l
+	}
+
+	var out io.Writer
+	switch opts.Format {
+	case "", "json":
+		out = os.Stderr
+	case "console":
+		out = zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339}
+	default:
+		return nil, fmt.Errorf("logging: unknown format %q (json, console)", opts.Format)
+	}
+
+	closeFile := func() error { return nil }
+	if opts.File != "" {
+		file := &lumberjack.Logger{
+			Filename:   opts.File,
+			MaxSize:    opts.FileMaxSize,
+			MaxBackups: opts.FileMaxBackups,
+			MaxAge:     opts.FileMaxAge,
+		}
+		out = zerolog.MultiLevelWriter(out, file)
+		closeFile = file.Close
+	}
+
+	AddRedactKeys(opts.RedactKeys...)
+
+	zerolog.SetGlobalLevel(level)
+	logger := zerolog.New(Writer{Out: out}).With().Timestamp().Logger()
+	if opts.SampleRate > 1 {
+		sampler := &zerolog.BasicSampler{N: uint32(opts.SampleRate)}
+		logger = logger.Sample(zerolog.LevelSampler{
+			TraceSampler: sampler,
+			DebugSampler: sampler,
+			InfoSampler:  sampler,
+		})
+	}
+	log.Logger = logger
+	return closeFile, nil
+}
@@ -0,0 +1,93 @@
+// Package metrics Relay Gateway Prometheus 메트릭
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "relay_gateway"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP 요청 수 (route, method, status)",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP 요청 처리 시간 (route, method, status)",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	relayRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "relay",
+		Name:      "requests_total",
+		Help:      "Relay Server 요청 결과 (target, path, code). 요청 실패 시 code는 error",
+	}, []string{"target", "path", "code"})
+
+	relayDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "relay",
+		Name:      "request_duration_seconds",
+		Help:      "Relay Server 요청 시간 (target, path)",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"target", "path"})
+
+	sinkEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sink",
+		Name:      "events_total",
+		Help:      "배포 기록 외부 전송 결과 (sink, result). result는 sent, failed, dropped",
+	}, []string{"sink", "result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		relayRequests,
+		relayDuration,
+		sinkEvents,
+	)
+}
+
+// Handler /metrics 응답 (Prometheus exposition format)
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// ObserveRequest HTTP 요청 처리 결과. route는 등록된 경로 패턴을 사용한다.
+func ObserveRequest(route, method string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(route, method, code).Inc()
+	httpDuration.WithLabelValues(route, method, code).Observe(elapsed.Seconds())
+}
+
+// ObserveRelay Relay Server 요청 결과. status가 0인 경우 요청 실패(연결 오류, 타임아웃 등)로 기록한다.
+func ObserveRelay(target, path string, status int, elapsed time.Duration) {
+	code := "error"
+	if status > 0 {
+		code = strconv.Itoa(status)
+	}
+	relayRequests.WithLabelValues(target, path, code).Inc()
+	relayDuration.WithLabelValues(target, path).Observe(elapsed.Seconds())
+}
+
+// ObserveSinkEvents 배포 기록 전송 결과 (sent, failed: 재시도 후 실패, dropped: 대기열 초과)
+func ObserveSinkEvents(sink, result string, n int) {
+	sinkEvents.WithLabelValues(sink, result).Add(float64(n))
+}
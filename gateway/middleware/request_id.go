@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/antonio-kim-1994/devops-relay/gateway/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// RequestID 요청의 X-Request-ID를 사용하거나 새로 생성해 응답 헤더 및 요청 로거(log.Ctx)에 설정
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		c.Header(requestid.Header, id)
+
+		ctx := requestid.With(c.Request.Context(), id)
+		logger := log.With().Str("request_id", id).Ctx(ctx).Logger()
+		c.Request = c.Request.WithContext(logger.WithContext(ctx))
+		c.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+// ValidateMetricsRequest /metrics 요청 인증 (Authorization: Bearer <METRICS_TOKEN>)
+// 토큰이 설정되지 않은 경우 별도 리스너(METRICS_PORT) 등 네트워크 수준에서 접근을 제한한다.
+func ValidateMetricsRequest(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		if c.Request.Header.Get("Authorization") != "Bearer "+token {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"message": "Unauthorized request. Check your request.",
+			})
+			log.Error().Msgf("Unauthorized metrics request | User-Agent: %s, x-Forwarded-For: %s",
+				c.GetHeader("user-agent"),
+				c.GetHeader("X-Forwarded-For"),
+			)
+			return
+		}
+		c.Next()
+	}
+}
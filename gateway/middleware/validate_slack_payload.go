@@ -39,8 +39,7 @@ func ValidationCheckSlackPayload() gin.HandlerFunc {
 		signingHash := "v0=" + generateHmacHash(signingSecret, baseString)
 
 		if !hmac.Equal([]byte(signingHash), []byte(signature)) {
-			// Debugging Line
-			log.Error().Msgf("[Invalid Header] Signature mismatch: provided=%s, expected=%s", signature, signingHash)
+			log.Error().Msg("[Invalid Header] Signature mismatch.")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
 			return
 		}
@@ -48,8 +47,7 @@ func ValidationCheckSlackPayload() gin.HandlerFunc {
 		c.Request.Body = io.NopCloser(bytes.NewBuffer(reqBody))
 		c.Set("body", string(reqBody))
 
-		// Debugging Line
-		log.Debug().Msgf("[Valid Header] Request signature verified: %s", signature)
+		log.Debug().Msg("[Valid Header] Request signature verified.")
 		c.Next()
 	}
 }
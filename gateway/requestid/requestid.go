@@ -0,0 +1,44 @@
+// Package requestid 서비스 간 요청 상관관계 ID (X-Request-ID)
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Header 요청 ID 헤더. 수신(혹은 생성)한 값을 Relay Server 요청에 그대로 전달한다.
+const Header = "X-Request-ID"
+
+// 외부에서 전달된 요청 ID는 로그 필드 및 Slack 버튼 값에 포함되므로 허용 문자와 길이를 제한한다.
+var pattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+type contextKey struct{}
+
+// New 요청 ID 생성
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("req-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Valid 외부에서 전달된 요청 ID 사용 가능 여부 (영문, 숫자, '.', '_', '-' 최대 128자)
+func Valid(id string) bool {
+	return pattern.MatchString(id)
+}
+
+// With 요청 ID가 포함된 context
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// From ctx의 요청 ID. 요청 ID가 없는 경우 빈 값을 반환한다.
+func From(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -1,18 +1,73 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/antonio-kim-1994/devops-relay/gateway/config"
 	"github.com/antonio-kim-1994/devops-relay/gateway/handler"
+	"github.com/antonio-kim-1994/devops-relay/gateway/logging"
+	"github.com/antonio-kim-1994/devops-relay/gateway/metrics"
 	"github.com/antonio-kim-1994/devops-relay/gateway/middleware"
+	"github.com/antonio-kim-1994/devops-relay/gateway/tracing"
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
+// shutdownTimeout 종료 시 요청 처리 및 배포 기록 전송 대기 시간
+const shutdownTimeout = 10 * time.Second
+
 func main() {
+	// 로그 레벨, 형식, 파일 출력 및 민감 정보 마스킹 설정
+	logCfg, err := config.Logging()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load logging config.")
+	}
+	closeLog, err := logging.Setup(logging.Options{
+		Level:          logCfg.Level,
+		Format:         logCfg.Format,
+		SampleRate:     logCfg.SampleRate,
+		File:           logCfg.File,
+		FileMaxSize:    logCfg.FileMaxSize,
+		FileMaxBackups: logCfg.FileMaxBackups,
+		FileMaxAge:     logCfg.FileMaxAge,
+		RedactKeys:     logCfg.RedactKeys,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to setup logging.")
+	}
+	defer closeLog()
+
+	// ctx가 지정된 로그에 trace_id, span_id 기록
+	log.Logger = log.Hook(tracing.LogHook{})
+	// 요청 로거가 없는 context의 log.Ctx(ctx)는 기본 로거 사용
+	zerolog.DefaultContextLogger = &log.Logger
+
 	// config 설정
 	cfg := config.Setting()
 
+	// OpenTelemetry trace export 설정
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		Protocol:    cfg.Tracing.Protocol,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to setup tracing.")
+	}
+	defer shutdownTracing(context.Background())
+
+	// 배포 기록 전송 대상 (Datadog, Splunk HEC, Elasticsearch, OTLP logs, 파일)
+	if err := handler.SetupEventSinks(cfg.EventSinks, cfg.Tracing.ServiceName); err != nil {
+		log.Fatal().Err(err).Msg("failed to setup event sinks.")
+	}
+
 	// Gin 모드 설정
 	//if os.Getenv("GIN_MODE") != "debug" {
 	//	gin.SetMode(gin.ReleaseMode)
@@ -21,18 +76,65 @@ func main() {
 	g := gin.Default()
 
 	// Route 등록
-	registerMainRoutes(g)
+	registerMainRoutes(g, cfg.Tracing.ServiceName)
 
-	err := g.Run(fmt.Sprintf(":%s", cfg.ServerPort))
-	if err != nil {
-		log.Fatal().Err(err).Msg("failed to run DevOps Relay Gateway.")
+	// METRICS_PORT 설정 시 /metrics는 전용 리스너에서만 제공
+	var metricsSrv *http.Server
+	if cfg.MetricsPort != "" {
+		m := gin.New()
+		m.Use(gin.Recovery())
+		registerMetricsRoute(m, cfg.MetricsToken)
+		metricsSrv = &http.Server{
+			Addr:    fmt.Sprintf(":%s", cfg.MetricsPort),
+			Handler: m,
+		}
+		go func() {
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal().Err(err).Msg("failed to run DevOps Relay Gateway metrics listener.")
+			}
+		}()
+	} else {
+		registerMetricsRoute(g, cfg.MetricsToken)
+	}
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%s", cfg.ServerPort),
+		Handler: g,
 	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("failed to run DevOps Relay Gateway.")
+		}
+	}()
+
+	// 종료 신호 수신 시 처리 중인 요청 완료 후 대기열의 배포 기록 전송
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Info().Msg("shutting down DevOps Relay Gateway.")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
 
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to shutdown http server.")
+	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("failed to shutdown metrics listener.")
+		}
+	}
+	if err := handler.CloseEventSinks(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to flush event sinks.")
+	}
 }
 
-func registerMainRoutes(g *gin.Engine) {
+func registerMainRoutes(g *gin.Engine, service string) {
 	// 500 error 혹은 panic으로 서버 shutdown 시 재기동
 	g.Use(gin.Recovery())
+	g.Use(middleware.RequestTracing(service))
+	g.Use(middleware.RequestID())
+	g.Use(middleware.RequestMetrics())
 	g.GET("/healthz/healthcheck", handler.GatewayHealthCheck)
 
 	v1 := g.Group("/v2")
@@ -52,3 +154,8 @@ func registerMainRoutes(g *gin.Engine) {
 		sys.POST("/healthcheck", handler.ServerHealthCheck)
 	}
 }
+
+// registerMetricsRoute Prometheus /metrics (METRICS_TOKEN 설정 시 Bearer 토큰 인증)
+func registerMetricsRoute(g *gin.Engine, token string) {
+	g.GET("/metrics", middleware.ValidateMetricsRequest(token), gin.WrapH(metrics.Handler()))
+}
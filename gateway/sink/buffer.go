@@ -0,0 +1,67 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/batch"
+	"github.com/antonio-kim-1994/devops-relay/gateway/metrics"
+	"io"
+	"time"
+)
+
+// initialBackoff 첫 재시도 대기 시간
+const initialBackoff = batch.InitialBackoff
+
+// Options 전송 대상별 대기열 및 재시도 설정
+type Options struct {
+	// 대기열 크기, 한 번에 전송할 최대 기록 수 및 전송 주기
+	QueueSize     int
+	BatchSize     int
+	FlushInterval time.Duration
+	// 재시도 포함 최대 전송 시도 횟수 (backoff 1s, 2s, 4s ...)
+	MaxAttempts int
+}
+
+// Buffered 대기열에 등록된 배포 기록을 주기적으로 묶어서 전송하는 EventSink
+// 전송 대상 장애가 배포 요청 응답을 지연시키지 않도록 등록은 대기하지 않는다.
+type Buffered struct {
+	sink   EventSink
+	sender *batch.Sender[Event]
+}
+
+// NewBuffered 전송 루프를 시작한 Buffered 생성. 종료 시 Close로 남은 기록을 전송한다.
+func NewBuffered(sink EventSink, opts Options) *Buffered {
+	return &Buffered{
+		sink: sink,
+		sender: batch.New(sink.Name(), sink.Send, batch.Options{
+			QueueSize:     opts.QueueSize,
+			BatchSize:     opts.BatchSize,
+			FlushInterval: opts.FlushInterval,
+			MaxAttempts:   opts.MaxAttempts,
+			Observe: func(result string, n int) {
+				metrics.ObserveSinkEvents(sink.Name(), result, n)
+			},
+		}),
+	}
+}
+
+// Name 전송 대상 이름
+func (b *Buffered) Name() string {
+	return b.sink.Name()
+}
+
+// Emit 전송 대기열 등록. 대기열이 가득 찬 경우 버린다.
+func (b *Buffered) Emit(e Event) {
+	b.sender.Add(e)
+}
+
+// Close 대기열에 남은 기록을 전송한 뒤 종료. ctx가 만료되면 전송을 중단한다.
+func (b *Buffered) Close(ctx context.Context) error {
+	err := b.sender.Close(ctx)
+	if closer, ok := b.sink.(io.Closer); ok {
+		if cerr := closer.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("%s: failed to close: %w", b.sink.Name(), cerr)
+		}
+	}
+	return err
+}
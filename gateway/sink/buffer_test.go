@@ -0,0 +1,218 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSink 전송 요청을 기록하고 errs 순서대로 오류를 반환하는 EventSink
+type fakeSink struct {
+	mu      sync.Mutex
+	batches [][]Event
+	calls   int
+	errs    []error
+	// 전송 시작 알림 및 전송 완료 대기 (설정된 경우)
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *fakeSink) Name() string { return "fake" }
+
+func (f *fakeSink) Send(ctx context.Context, events []Event) error {
+	if f.started != nil {
+		f.started <- struct{}{}
+		<-f.release
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		if err != nil {
+			return err
+		}
+	}
+	f.batches = append(f.batches, append([]Event(nil), events...))
+	return nil
+}
+
+func (f *fakeSink) sent() (ids []string, batches, calls int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, batch := range f.batches {
+		for _, e := range batch {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids, len(f.batches), f.calls
+}
+
+func closeBuffered(t *testing.T, b *Buffered) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := b.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestBufferedBatching(t *testing.T) {
+	f := &fakeSink{}
+	b := NewBuffered(f, Options{BatchSize: 2, FlushInterval: time.Hour})
+	for _, id := range []string{"dep-1", "dep-2", "dep-3", "dep-4", "dep-5"} {
+		b.Emit(Event{ID: id})
+	}
+	closeBuffered(t, b)
+
+	ids, _, _ := f.sent()
+	if strings.Join(ids, ",") != "dep-1,dep-2,dep-3,dep-4,dep-5" {
+		t.Errorf("sent = %q, want all events in order", ids)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, batch := range f.batches {
+		if len(batch) > 2 {
+			t.Errorf("batch size = %d, want at most 2", len(batch))
+		}
+	}
+}
+
+func TestBufferedFlushInterval(t *testing.T) {
+	f := &fakeSink{}
+	b := NewBuffered(f, Options{FlushInterval: 20 * time.Millisecond})
+	defer closeBuffered(t, b)
+
+	b.Emit(Event{ID: "dep-1"})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if ids, _, _ := f.sent(); len(ids) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("event not flushed before Close")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBufferedRetry(t *testing.T) {
+	f := &fakeSink{errs: []error{errors.New("fake: 503 Service Unavailable")}}
+	b := NewBuffered(f, Options{MaxAttempts: 2})
+	b.Emit(Event{ID: "dep-1"})
+	closeBuffered(t, b)
+
+	// 재시도 대상 오류는 backoff 이후 다시 전송한다.
+	if ids, batches, calls := f.sent(); len(ids) != 1 || batches != 1 || calls != 2 {
+		t.Errorf("sent = %q in %d batches after %d calls, want 1 event after 2 calls", ids, batches, calls)
+	}
+}
+
+func TestBufferedPermanentError(t *testing.T) {
+	f := &fakeSink{errs: []error{Permanent(errors.New("fake: 400 Bad Request")), nil}}
+	b := NewBuffered(f, Options{MaxAttempts: 3})
+	b.Emit(Event{ID: "dep-1"})
+	closeBuffered(t, b)
+
+	if ids, _, calls := f.sent(); len(ids) != 0 || calls != 1 {
+		t.Errorf("sent = %q after %d calls, want dropped after 1 call", ids, calls)
+	}
+}
+
+func TestBufferedMaxAttempts(t *testing.T) {
+	retryErr := errors.New("fake: connection refused")
+	f := &fakeSink{errs: []error{retryErr, retryErr, nil}}
+	b := NewBuffered(f, Options{MaxAttempts: 1})
+	b.Emit(Event{ID: "dep-1"})
+	closeBuffered(t, b)
+
+	if ids, _, calls := f.sent(); len(ids) != 0 || calls != 1 {
+		t.Errorf("sent = %q after %d calls, want dropped after max attempts", ids, calls)
+	}
+}
+
+func TestBufferedQueueFull(t *testing.T) {
+	f := &fakeSink{started: make(chan struct{}), release: make(chan struct{})}
+	b := NewBuffered(f, Options{QueueSize: 1, BatchSize: 1})
+
+	b.Emit(Event{ID: "dep-1"})
+	<-f.started
+	// dep-1 전송 중 대기열(1)이 가득 차 dep-3은 버린다.
+	b.Emit(Event{ID: "dep-2"})
+	b.Emit(Event{ID: "dep-3"})
+
+	go func() {
+		for range f.started {
+			f.release <- struct{}{}
+		}
+	}()
+	f.release <- struct{}{}
+	closeBuffered(t, b)
+	close(f.started)
+
+	if ids, _, _ := f.sent(); strings.Join(ids, ",") != "dep-1,dep-2" {
+		t.Errorf("sent = %q, want dep-3 dropped", ids)
+	}
+	b.Emit(Event{ID: "dep-4"})
+	if ids, _, _ := f.sent(); len(ids) != 2 {
+		t.Errorf("sent = %q, want events after Close ignored", ids)
+	}
+}
+
+func TestBufferedCloseTimeout(t *testing.T) {
+	retryErr := errors.New("fake: 503 Service Unavailable")
+	f := &fakeSink{errs: []error{retryErr, retryErr, retryErr}}
+	b := NewBuffered(f, Options{MaxAttempts: 3})
+	b.Emit(Event{ID: "dep-1"})
+
+	// backoff 대기 중 종료 대기 시간이 지나면 전송을 중단한다.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := b.Close(ctx)
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "fake: pending events dropped") {
+		t.Errorf("err = %v, want pending events dropped", err)
+	}
+	if elapsed := time.Since(start); elapsed > initialBackoff {
+		t.Errorf("Close took %s, want canceled before backoff", elapsed)
+	}
+}
+
+func TestSinksEmit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deployments.jsonl")
+	file, err := NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile: %v", err)
+	}
+	f := &fakeSink{}
+	sinks := Sinks{NewBuffered(f, Options{}), NewBuffered(file, Options{})}
+
+	sinks.Emit(Event{ID: "dep-1", Message: "homepage-front deployed"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sinks.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	f.mu.Lock()
+	if len(f.batches) != 1 || f.batches[0][0].Time.IsZero() {
+		t.Errorf("batches = %+v, want event time set on emit", f.batches)
+	}
+	f.mu.Unlock()
+
+	// Close 시 io.Closer 전송 대상(파일)도 닫는다.
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), `"id":"dep-1"`) {
+		t.Errorf("file = %q (%v)", data, err)
+	}
+	if err := file.Send(context.Background(), []Event{{ID: "dep-2"}}); err == nil {
+		t.Error("file still writable after Close")
+	}
+}
@@ -0,0 +1,85 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DatadogOptions Datadog Logs 전송 설정
+type DatadogOptions struct {
+	// URL 미설정 시 Site(예: datadoghq.com) 기준 Logs intake 주소를 사용한다.
+	URL    string
+	Site   string
+	APIKey string
+	// 로그 hostname 및 service
+	Host       string
+	Service    string
+	HTTPClient *http.Client
+}
+
+// Datadog Datadog Logs API(v2) 전송 대상
+type Datadog struct {
+	url     string
+	apiKey  string
+	host    string
+	service string
+	client  *http.Client
+}
+
+// NewDatadog Datadog Logs 전송 대상 생성
+func NewDatadog(opts DatadogOptions) *Datadog {
+	url := opts.URL
+	if url == "" {
+		site := opts.Site
+		if site == "" {
+			site = "datadoghq.com"
+		}
+		url = fmt.Sprintf("https://http-intake.logs.%s", site)
+	}
+	return &Datadog{
+		url:     strings.TrimSuffix(url, "/") + "/api/v2/logs",
+		apiKey:  opts.APIKey,
+		host:    opts.Host,
+		service: opts.Service,
+		client:  defaultHTTPClient(opts.HTTPClient),
+	}
+}
+
+func (d *Datadog) Name() string { return "datadog" }
+
+// Send 배포 기록을 Datadog Logs로 전송. 태그는 Unified Service Tagging(env, service, version) 형식으로 변환한다.
+func (d *Datadog) Send(ctx context.Context, events []Event) error {
+	items := make([]map[string]any, 0, len(events))
+	for _, e := range events {
+		tags := make([]string, 0, len(e.Tags))
+		for _, k := range sortedKeys(e.Tags) {
+			tags = append(tags, fmt.Sprintf("%s:%s", k, e.Tags[k]))
+		}
+
+		item := make(map[string]any, len(e.Fields)+5)
+		for k, v := range e.Fields {
+			item[k] = v
+		}
+		item["ddsource"] = "devops-relay"
+		item["ddtags"] = strings.Join(tags, ",")
+		item["message"] = e.Message
+		if d.host != "" {
+			item["hostname"] = d.host
+		}
+		if d.service != "" {
+			item["service"] = d.service
+		}
+		items = append(items, item)
+	}
+
+	body, err := json.Marshal(items)
+	if err != nil {
+		return Permanent(fmt.Errorf("datadog: failed to marshal logs: %w", err))
+	}
+	header := http.Header{"DD-API-KEY": {d.apiKey}}
+	_, err = post(ctx, d.client, "datadog", d.url, "application/json", header, body)
+	return err
+}
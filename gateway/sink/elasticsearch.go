@@ -0,0 +1,140 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultElasticsearchIndex = "devops-relay-deployments"
+
+// ElasticsearchOptions Elasticsearch Bulk API 전송 설정
+type ElasticsearchOptions struct {
+	URL string
+	// index 혹은 data stream 이름 (기본값 devops-relay-deployments)
+	Index string
+	// API Key 인증 (미설정 시 Username, Password로 basic 인증)
+	APIKey     string
+	Username   string
+	Password   string
+	HTTPClient *http.Client
+}
+
+// Elasticsearch Elasticsearch Bulk API 전송 대상
+type Elasticsearch struct {
+	url      string
+	index    string
+	apiKey   string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewElasticsearch Elasticsearch 전송 대상 생성
+func NewElasticsearch(opts ElasticsearchOptions) *Elasticsearch {
+	index := opts.Index
+	if index == "" {
+		index = defaultElasticsearchIndex
+	}
+	return &Elasticsearch{
+		url:      strings.TrimSuffix(opts.URL, "/") + "/_bulk",
+		index:    index,
+		apiKey:   opts.APIKey,
+		username: opts.Username,
+		password: opts.Password,
+		client:   defaultHTTPClient(opts.HTTPClient),
+	}
+}
+
+func (es *Elasticsearch) Name() string { return "elasticsearch" }
+
+type bulkAction struct {
+	Create bulkMeta `json:"create"`
+}
+
+type bulkMeta struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id,omitempty"`
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// Send 배포 기록을 Bulk API(create)로 전송
+// 이벤트 ID를 문서 ID로 사용하므로 재전송 시 이미 저장된 문서(409)는 성공으로 처리한다.
+func (es *Elasticsearch) Send(ctx context.Context, events []Event) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, e := range events {
+		doc := record(e)
+		doc["@timestamp"] = e.Time.UTC().Format(time.RFC3339Nano)
+		if e.TraceID != "" {
+			doc["trace_id"] = e.TraceID
+			doc["span_id"] = e.SpanID
+		}
+		if err := enc.Encode(bulkAction{Create: bulkMeta{Index: es.index, ID: e.ID}}); err != nil {
+			return Permanent(fmt.Errorf("elasticsearch: failed to marshal action: %w", err))
+		}
+		if err := enc.Encode(doc); err != nil {
+			return Permanent(fmt.Errorf("elasticsearch: failed to marshal document: %w", err))
+		}
+	}
+
+	header := http.Header{}
+	if es.apiKey != "" {
+		header.Set("Authorization", "ApiKey "+es.apiKey)
+	} else if es.username != "" {
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(es.username+":"+es.password)))
+	}
+
+	data, err := post(ctx, es.client, "elasticsearch", es.url, "application/x-ndjson", header, body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	var resp bulkResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return Permanent(fmt.Errorf("elasticsearch: failed to parse bulk response: %w", err))
+	}
+	if !resp.Errors {
+		return nil
+	}
+
+	// 문서 단위 실패: 429, 5xx는 전체 재전송(저장된 문서는 409), 그 외 실패는 재시도하지 않는다.
+	var retry bool
+	var reasons []string
+	for _, item := range resp.Items {
+		for _, result := range item {
+			switch {
+			case result.Status < http.StatusMultipleChoices, result.Status == http.StatusConflict:
+			case result.Status == http.StatusTooManyRequests, result.Status >= http.StatusInternalServerError:
+				retry = true
+				reasons = append(reasons, fmt.Sprintf("%d %s", result.Status, result.Error.Type))
+			default:
+				reasons = append(reasons, fmt.Sprintf("%d %s: %s", result.Status, result.Error.Type, result.Error.Reason))
+			}
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	err = fmt.Errorf("elasticsearch: %d documents failed: %s", len(reasons), strings.Join(reasons, ", "))
+	if retry {
+		return err
+	}
+	return Permanent(err)
+}
@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// File 로컬 JSON Lines 파일 전송 대상 (한 줄에 배포 기록 1건)
+type File struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// NewFile JSON Lines 파일 전송 대상 생성. 파일이 없으면 생성하고, 있으면 이어서 기록한다.
+func NewFile(path string) (*File, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("file: failed to create directory %s: %w", dir, err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("file: failed to open %s: %w", path, err)
+	}
+	return &File{path: path, file: f}, nil
+}
+
+func (f *File) Name() string { return "file" }
+
+// Send 배포 기록을 한 번에 기록해 재시도 시 일부만 기록되지 않도록 한다.
+func (f *File) Send(_ context.Context, events []Event) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range events {
+		doc := record(e)
+		doc["time"] = e.Time.Format(time.RFC3339Nano)
+		if e.ID != "" {
+			doc["id"] = e.ID
+		}
+		if e.TraceID != "" {
+			doc["trace_id"] = e.TraceID
+			doc["span_id"] = e.SpanID
+		}
+		if err := enc.Encode(doc); err != nil {
+			return Permanent(fmt.Errorf("file: failed to marshal event: %w", err))
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("file: failed to write %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// Close 파일 닫기
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,47 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// defaultHTTPClient HTTPClient 미설정 시 사용하는 클라이언트
+func defaultHTTPClient(client *http.Client) *http.Client {
+	if client == nil {
+		return &http.Client{Timeout: 10 * time.Second}
+	}
+	return client
+}
+
+// post 전송 요청. 응답 상태 코드가 400 이상이면 responseError를 반환하고, 성공 시 응답 본문을 반환한다.
+func post(ctx context.Context, client *http.Client, name, url, contentType string, header http.Header, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, Permanent(fmt.Errorf("%s: failed to create request: %w", name, err))
+	}
+	for k, values := range header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: POST %s: %w", name, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, responseError(name, resp.StatusCode, resp.Status, resp.Body)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read response: %w", name, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,155 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// OTLPOptions OTLP logs(HTTP/JSON) 전송 설정
+type OTLPOptions struct {
+	// logs 수신 주소 (예: http://otel-collector:4318/v1/logs)
+	Endpoint string
+	// Collector 인증 등 요청 헤더
+	Headers map[string]string
+	// resource service.name, host.name
+	ServiceName string
+	Host        string
+	HTTPClient  *http.Client
+}
+
+// OTLP OpenTelemetry Collector logs 전송 대상 (OTLP/HTTP JSON)
+type OTLP struct {
+	endpoint string
+	header   http.Header
+	resource otlpResource
+	client   *http.Client
+}
+
+// NewOTLP OTLP logs 전송 대상 생성
+func NewOTLP(opts OTLPOptions) *OTLP {
+	header := http.Header{}
+	for k, v := range opts.Headers {
+		header.Set(k, v)
+	}
+
+	resource := otlpResource{Attributes: []otlpKeyValue{attribute("service.name", opts.ServiceName)}}
+	if opts.Host != "" {
+		resource.Attributes = append(resource.Attributes, attribute("host.name", opts.Host))
+	}
+	return &OTLP{
+		endpoint: opts.Endpoint,
+		header:   header,
+		resource: resource,
+		client:   defaultHTTPClient(opts.HTTPClient),
+	}
+}
+
+func (o *OTLP) Name() string { return "otlp" }
+
+// OTLP/JSON 인코딩 (https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding)
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber"`
+	SeverityText   string         `json:"severityText"`
+	Body           otlpAnyValue   `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes"`
+	TraceID        string         `json:"traceId,omitempty"`
+	SpanID         string         `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func attribute(key string, value any) otlpKeyValue {
+	var v otlpAnyValue
+	switch value := value.(type) {
+	case string:
+		v.StringValue = &value
+	case bool:
+		v.BoolValue = &value
+	case int:
+		i := strconv.Itoa(value)
+		v.IntValue = &i
+	case int64:
+		i := strconv.FormatInt(value, 10)
+		v.IntValue = &i
+	case float64:
+		v.DoubleValue = &value
+	default:
+		s := fmt.Sprint(value)
+		v.StringValue = &s
+	}
+	return otlpKeyValue{Key: key, Value: v}
+}
+
+// Send 배포 기록을 INFO 로그로 전송. 요청 필드와 태그(tag.<name>)는 로그 attribute로 기록한다.
+func (o *OTLP) Send(ctx context.Context, events []Event) error {
+	records := make([]otlpLogRecord, 0, len(events))
+	for _, e := range events {
+		attrs := make([]otlpKeyValue, 0, len(e.Fields)+len(e.Tags))
+		for _, k := range sortedKeys(e.Fields) {
+			attrs = append(attrs, attribute(k, e.Fields[k]))
+		}
+		for _, k := range sortedKeys(e.Tags) {
+			attrs = append(attrs, attribute("tag."+k, e.Tags[k]))
+		}
+
+		message := e.Message
+		records = append(records, otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(e.Time.UnixNano(), 10),
+			SeverityNumber: 9,
+			SeverityText:   "INFO",
+			Body:           otlpAnyValue{StringValue: &message},
+			Attributes:     attrs,
+			TraceID:        e.TraceID,
+			SpanID:         e.SpanID,
+		})
+	}
+
+	body, err := json.Marshal(otlpLogsRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: o.resource,
+		ScopeLogs: []otlpScopeLogs{{
+			Scope:      otlpScope{Name: "devops-relay"},
+			LogRecords: records,
+		}},
+	}}})
+	if err != nil {
+		return Permanent(fmt.Errorf("otlp: failed to marshal logs: %w", err))
+	}
+	_, err = post(ctx, o.client, "otlp", o.endpoint, "application/json", o.header, body)
+	return err
+}
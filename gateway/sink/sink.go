@@ -0,0 +1,103 @@
+// Package sink 배포 기록 외부 전송 (Datadog Logs, Splunk HEC, Elasticsearch, OTLP logs, JSON Lines 파일)
+// 전송 대상은 EventSink로 구현하고, Buffered가 대기열, 묶음 전송 및 재시도를 담당한다.
+package sink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/batch"
+	"io"
+	"sort"
+	"time"
+)
+
+// Event 외부로 전송할 배포 기록
+type Event struct {
+	// 이벤트 ID (배포 ID). Elasticsearch 문서 ID로 사용해 재전송 시 중복 저장을 막는다.
+	ID      string
+	Time    time.Time
+	Message string
+	// 검색 및 집계용 태그 (env, service, version, org, repo, team)
+	Tags map[string]string
+	// 배포 요청 필드. 민감 정보는 등록 전 마스킹한다.
+	Fields map[string]any
+	// 배포 요청 trace (OTLP logs trace 연결)
+	TraceID string
+	SpanID  string
+}
+
+// EventSink 배포 기록 전송 대상
+// Send는 events를 한 번에 전송하며, 재시도해도 성공할 수 없는 오류는 Permanent로 감싸 반환한다.
+type EventSink interface {
+	Name() string
+	Send(ctx context.Context, events []Event) error
+}
+
+// Permanent 재시도하지 않는 오류로 표시 (잘못된 요청, 인증 실패 등)
+func Permanent(err error) error {
+	return batch.Permanent(err)
+}
+
+// retryable 연결 오류, 429, 5xx 등 재시도 대상 여부
+func retryable(err error) bool {
+	return batch.Retryable(err)
+}
+
+// Sinks 설정된 모든 전송 대상에 배포 기록 전달
+type Sinks []*Buffered
+
+// Emit 모든 전송 대상의 대기열에 등록. 대기열이 가득 찬 경우 버린다.
+func (s Sinks) Emit(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	for _, b := range s {
+		b.Emit(e)
+	}
+}
+
+// Close 모든 전송 대상의 대기열에 남은 기록을 전송한 뒤 종료
+func (s Sinks) Close(ctx context.Context) error {
+	errs := make([]error, 0, len(s))
+	for _, b := range s {
+		errs = append(errs, b.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// record 전송 대상 공통 본문 (요청 필드, message, tags)
+func record(e Event) map[string]any {
+	doc := make(map[string]any, len(e.Fields)+2)
+	for k, v := range e.Fields {
+		doc[k] = v
+	}
+	doc["message"] = e.Message
+	if len(e.Tags) > 0 {
+		doc["tags"] = e.Tags
+	}
+	return doc
+}
+
+// sortedKeys 필드/태그 이름 정렬 (전송 본문 순서 고정)
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// responseError 응답 상태 코드 기준 전송 오류. 429, 5xx 외에는 재시도하지 않는다.
+func responseError(name string, status int, reason string, body io.Reader) error {
+	err := fmt.Errorf("%s: %s", name, reason)
+	if data, _ := io.ReadAll(io.LimitReader(body, 1024)); len(bytes.TrimSpace(data)) > 0 {
+		err = fmt.Errorf("%s: %s: %s", name, reason, bytes.TrimSpace(data))
+	}
+	if status == 429 || status >= 500 {
+		return err
+	}
+	return Permanent(err)
+}
@@ -0,0 +1,413 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/antonio-kim-1994/devops-relay/batch"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var eventTime = time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+
+func testEvent(id string) Event {
+	return Event{
+		ID:      id,
+		Time:    eventTime,
+		Message: "homepage-front v1.2.3 deployed to prod",
+		Tags:    map[string]string{"service": "homepage-front", "env": "prod", "version": "v1.2.3"},
+		Fields:  map[string]any{"deployment_id": id, "operator": "antonio-kim-1994", "attempt": 2},
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:  "00f067aa0ba902b7",
+	}
+}
+
+// request 수신한 전송 요청
+type request struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// receiver 전송 요청을 기록하고 status, body로 응답하는 테스트 서버
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []request
+}
+
+func newReceiver(t *testing.T, status int, body string) *receiver {
+	t.Helper()
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, request{method: req.Method, path: req.URL.Path, header: req.Header.Clone(), body: data})
+		r.mu.Unlock()
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) last(t *testing.T) request {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.requests) == 0 {
+		t.Fatal("no request received")
+	}
+	return r.requests[len(r.requests)-1]
+}
+
+// decodeLines JSON Lines 본문 디코딩
+func decodeLines(t *testing.T, data []byte) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid json line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func isPermanent(err error) bool {
+	return batch.IsPermanent(err)
+}
+
+func TestSplunk(t *testing.T) {
+	r := newReceiver(t, http.StatusOK, `{"text":"Success","code":0}`)
+	s := NewSplunk(SplunkOptions{URL: r.URL + "/", Token: "hec-token", Index: "deployments", Host: "gateway-0"})
+
+	if err := s.Send(context.Background(), []Event{testEvent("dep-1"), testEvent("dep-2")}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	req := r.last(t)
+	if req.path != "/services/collector/event" || req.header.Get("Authorization") != "Splunk hec-token" {
+		t.Errorf("request = %s %s (Authorization %q)", req.method, req.path, req.header.Get("Authorization"))
+	}
+
+	lines := decodeLines(t, req.body)
+	if len(lines) != 2 {
+		t.Fatalf("events = %d, want 2", len(lines))
+	}
+	e := lines[0]
+	if e["time"] != float64(eventTime.Unix()) || e["host"] != "gateway-0" || e["source"] != "devops-relay" || e["sourcetype"] != "_json" || e["index"] != "deployments" {
+		t.Errorf("event metadata = %v", e)
+	}
+	event := e["event"].(map[string]any)
+	if event["message"] != "homepage-front v1.2.3 deployed to prod" || event["deployment_id"] != "dep-1" {
+		t.Errorf("event = %v", event)
+	}
+	// 태그는 event 본문과 indexed field에 함께 기록된다.
+	if fields := e["fields"].(map[string]any); fields["env"] != "prod" || fields["service"] != "homepage-front" {
+		t.Errorf("fields = %v", fields)
+	}
+	if tags := event["tags"].(map[string]any); tags["version"] != "v1.2.3" {
+		t.Errorf("tags = %v", tags)
+	}
+}
+
+func TestSplunkCollectorPath(t *testing.T) {
+	s := NewSplunk(SplunkOptions{URL: "https://splunk.example.com:8088/services/collector"})
+	if s.url != "https://splunk.example.com:8088/services/collector" {
+		t.Errorf("url = %q, want configured collector path", s.url)
+	}
+}
+
+func TestResponseErrors(t *testing.T) {
+	cases := []struct {
+		status    int
+		body      string
+		permanent bool
+	}{
+		{status: http.StatusBadRequest, body: `{"text":"Invalid data format","code":6}`, permanent: true},
+		{status: http.StatusForbidden, body: `{"text":"Invalid token","code":4}`, permanent: true},
+		{status: http.StatusTooManyRequests, permanent: false},
+		{status: http.StatusServiceUnavailable, body: `{"text":"Server is busy","code":9}`, permanent: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			r := newReceiver(t, tc.status, tc.body)
+			err := NewSplunk(SplunkOptions{URL: r.URL}).Send(context.Background(), []Event{testEvent("dep-1")})
+			if err == nil {
+				t.Fatal("Send succeeded, want error")
+			}
+			if isPermanent(err) != tc.permanent || retryable(err) == tc.permanent {
+				t.Errorf("err = %v, permanent = %t, want %t", err, isPermanent(err), tc.permanent)
+			}
+			if tc.body != "" && !strings.Contains(err.Error(), tc.body) {
+				t.Errorf("err = %v, want response body", err)
+			}
+		})
+	}
+}
+
+func TestElasticsearch(t *testing.T) {
+	r := newReceiver(t, http.StatusOK, `{"errors":false,"items":[{"create":{"status":201}}]}`)
+	es := NewElasticsearch(ElasticsearchOptions{URL: r.URL, APIKey: "es-key"})
+
+	if err := es.Send(context.Background(), []Event{testEvent("dep-1")}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	req := r.last(t)
+	if req.path != "/_bulk" || req.header.Get("Content-Type") != "application/x-ndjson" || req.header.Get("Authorization") != "ApiKey es-key" {
+		t.Errorf("request = %s %s %v", req.method, req.path, req.header)
+	}
+
+	lines := decodeLines(t, req.body)
+	if len(lines) != 2 {
+		t.Fatalf("lines = %d, want action and document", len(lines))
+	}
+	action := lines[0]["create"].(map[string]any)
+	if action["_index"] != defaultElasticsearchIndex || action["_id"] != "dep-1" {
+		t.Errorf("action = %v", action)
+	}
+	doc := lines[1]
+	if doc["@timestamp"] != "2026-03-02T10:30:00Z" || doc["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || doc["message"] == nil {
+		t.Errorf("document = %v", doc)
+	}
+}
+
+func TestElasticsearchBasicAuth(t *testing.T) {
+	r := newReceiver(t, http.StatusOK, `{"errors":false,"items":[]}`)
+	es := NewElasticsearch(ElasticsearchOptions{URL: r.URL + "/", Index: "deployments", Username: "relay", Password: "secret"})
+
+	if err := es.Send(context.Background(), []Event{testEvent("dep-1")}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	req := r.last(t)
+	if user, password, ok := (&http.Request{Header: req.header}).BasicAuth(); !ok || user != "relay" || password != "secret" {
+		t.Errorf("basic auth = %q, %q, %t", user, password, ok)
+	}
+	if action := decodeLines(t, req.body)[0]["create"].(map[string]any); action["_index"] != "deployments" {
+		t.Errorf("action = %v", action)
+	}
+}
+
+func TestElasticsearchPartialFailure(t *testing.T) {
+	cases := []struct {
+		name      string
+		items     string
+		wantErr   bool
+		permanent bool
+		reason    string
+	}{
+		{
+			name:  "already stored",
+			items: `[{"create":{"status":201}},{"create":{"status":409,"error":{"type":"version_conflict_engine_exception"}}}]`,
+		},
+		{
+			name:    "rejected by bulk queue",
+			items:   `[{"create":{"status":201}},{"create":{"status":429,"error":{"type":"es_rejected_execution_exception"}}}]`,
+			wantErr: true,
+			reason:  "1 documents failed: 429 es_rejected_execution_exception",
+		},
+		{
+			name:      "mapping error",
+			items:     `[{"create":{"status":201}},{"create":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [attempt]"}}}]`,
+			wantErr:   true,
+			permanent: true,
+			reason:    "400 mapper_parsing_exception: failed to parse field [attempt]",
+		},
+		{
+			name:    "mapping error with retryable item",
+			items:   `[{"create":{"status":503,"error":{"type":"unavailable_shards_exception"}}},{"create":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]`,
+			wantErr: true,
+			reason:  "2 documents failed",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newReceiver(t, http.StatusOK, `{"errors":true,"items":`+tc.items+`}`)
+			err := NewElasticsearch(ElasticsearchOptions{URL: r.URL}).Send(context.Background(), []Event{testEvent("dep-1"), testEvent("dep-2")})
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, want error %t", err, tc.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if isPermanent(err) != tc.permanent {
+				t.Errorf("err = %v, permanent = %t, want %t", err, isPermanent(err), tc.permanent)
+			}
+			if !strings.Contains(err.Error(), tc.reason) {
+				t.Errorf("err = %v, want %q", err, tc.reason)
+			}
+		})
+	}
+}
+
+func TestElasticsearchInvalidResponse(t *testing.T) {
+	r := newReceiver(t, http.StatusOK, `<html>proxy error</html>`)
+	err := NewElasticsearch(ElasticsearchOptions{URL: r.URL}).Send(context.Background(), []Event{testEvent("dep-1")})
+	if err == nil || !isPermanent(err) || !strings.Contains(err.Error(), "failed to parse bulk response") {
+		t.Errorf("err = %v, want permanent parse error", err)
+	}
+}
+
+func TestOTLP(t *testing.T) {
+	r := newReceiver(t, http.StatusOK, `{}`)
+	o := NewOTLP(OTLPOptions{
+		Endpoint:    r.URL + "/v1/logs",
+		Headers:     map[string]string{"X-Scope-OrgID": "devops"},
+		ServiceName: "devops-relay-gateway",
+		Host:        "gateway-0",
+	})
+
+	if err := o.Send(context.Background(), []Event{testEvent("dep-1")}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	req := r.last(t)
+	if req.path != "/v1/logs" || req.header.Get("X-Scope-OrgID") != "devops" || req.header.Get("Content-Type") != "application/json" {
+		t.Errorf("request = %s %s %v", req.method, req.path, req.header)
+	}
+
+	var body otlpLogsRequest
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	if len(body.ResourceLogs) != 1 || len(body.ResourceLogs[0].ScopeLogs) != 1 {
+		t.Fatalf("body = %s", req.body)
+	}
+	resource := body.ResourceLogs[0].Resource.Attributes
+	if len(resource) != 2 || *resource[0].Value.StringValue != "devops-relay-gateway" || *resource[1].Value.StringValue != "gateway-0" {
+		t.Errorf("resource = %s", req.body)
+	}
+
+	scope := body.ResourceLogs[0].ScopeLogs[0]
+	if scope.Scope.Name != "devops-relay" || len(scope.LogRecords) != 1 {
+		t.Fatalf("scope logs = %+v", scope)
+	}
+	record := scope.LogRecords[0]
+	if record.TimeUnixNano != "1772447400000000000" || record.SeverityNumber != 9 || record.SeverityText != "INFO" {
+		t.Errorf("record = %+v", record)
+	}
+	if *record.Body.StringValue != "homepage-front v1.2.3 deployed to prod" || record.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || record.SpanID != "00f067aa0ba902b7" {
+		t.Errorf("record = %+v", record)
+	}
+
+	// 요청 필드(이름순) 이후 태그(tag.<name>, 이름순)
+	var keys []string
+	for _, kv := range record.Attributes {
+		keys = append(keys, kv.Key)
+	}
+	want := []string{"attempt", "deployment_id", "operator", "tag.env", "tag.service", "tag.version"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("attributes = %q, want %q", keys, want)
+	}
+	if attempt := record.Attributes[0].Value; attempt.IntValue == nil || *attempt.IntValue != "2" {
+		t.Errorf("attempt = %+v, want intValue 2", attempt)
+	}
+}
+
+func TestDatadog(t *testing.T) {
+	r := newReceiver(t, http.StatusAccepted, `{}`)
+	d := NewDatadog(DatadogOptions{URL: r.URL, APIKey: "dd-key", Host: "gateway-0", Service: "devops-relay"})
+
+	if err := d.Send(context.Background(), []Event{testEvent("dep-1")}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	req := r.last(t)
+	if req.path != "/api/v2/logs" || req.header.Get("DD-API-KEY") != "dd-key" {
+		t.Errorf("request = %s %s %v", req.method, req.path, req.header)
+	}
+
+	var items []map[string]any
+	if err := json.Unmarshal(req.body, &items); err != nil || len(items) != 1 {
+		t.Fatalf("body = %s (%v)", req.body, err)
+	}
+	item := items[0]
+	if item["ddtags"] != "env:prod,service:homepage-front,version:v1.2.3" || item["ddsource"] != "devops-relay" {
+		t.Errorf("tags = %v, source = %v", item["ddtags"], item["ddsource"])
+	}
+	if item["hostname"] != "gateway-0" || item["service"] != "devops-relay" || item["deployment_id"] != "dep-1" || item["message"] == nil {
+		t.Errorf("item = %v", item)
+	}
+}
+
+func TestDatadogSite(t *testing.T) {
+	if got := NewDatadog(DatadogOptions{}).url; got != "https://http-intake.logs.datadoghq.com/api/v2/logs" {
+		t.Errorf("default url = %q", got)
+	}
+	if got := NewDatadog(DatadogOptions{Site: "datadoghq.eu"}).url; got != "https://http-intake.logs.datadoghq.eu/api/v2/logs" {
+		t.Errorf("eu url = %q", got)
+	}
+}
+
+func TestConnectionErrorRetryable(t *testing.T) {
+	r := newReceiver(t, http.StatusOK, `{}`)
+	url := r.URL
+	r.Close()
+
+	err := NewDatadog(DatadogOptions{URL: url}).Send(context.Background(), []Event{testEvent("dep-1")})
+	if err == nil || !retryable(err) {
+		t.Errorf("err = %v, want retryable connection error", err)
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events", "deployments.jsonl")
+
+	f, err := NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile: %v", err)
+	}
+	if err := f.Send(context.Background(), []Event{testEvent("dep-1"), testEvent("dep-2")}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// 기존 파일에 이어서 기록
+	f, err = NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile: %v", err)
+	}
+	e := testEvent("dep-3")
+	e.TraceID, e.SpanID = "", ""
+	if err := f.Send(context.Background(), []Event{e}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := decodeLines(t, data)
+	if len(lines) != 3 {
+		t.Fatalf("lines = %d, want 3", len(lines))
+	}
+	if l := lines[0]; l["id"] != "dep-1" || l["time"] != "2026-03-02T10:30:00Z" || l["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || l["attempt"] != float64(2) {
+		t.Errorf("line = %v", l)
+	}
+	if _, exist := lines[2]["trace_id"]; exist || lines[2]["id"] != "dep-3" {
+		t.Errorf("line = %v, want no trace_id", lines[2])
+	}
+}
@@ -0,0 +1,87 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SplunkOptions Splunk HTTP Event Collector 전송 설정
+type SplunkOptions struct {
+	// HEC 주소 (예: https://splunk.example.com:8088). 경로 미지정 시 /services/collector/event를 사용한다.
+	URL   string
+	Token string
+	// 저장할 index 및 sourcetype (미설정 시 HEC token 기본값, sourcetype은 _json)
+	Index      string
+	SourceType string
+	Host       string
+	HTTPClient *http.Client
+}
+
+// Splunk Splunk HEC 전송 대상
+type Splunk struct {
+	url        string
+	token      string
+	index      string
+	sourceType string
+	host       string
+	client     *http.Client
+}
+
+// NewSplunk Splunk HEC 전송 대상 생성
+func NewSplunk(opts SplunkOptions) *Splunk {
+	url := strings.TrimSuffix(opts.URL, "/")
+	if !strings.Contains(url, "/services/collector") {
+		url += "/services/collector/event"
+	}
+	sourceType := opts.SourceType
+	if sourceType == "" {
+		sourceType = "_json"
+	}
+	return &Splunk{
+		url:        url,
+		token:      opts.Token,
+		index:      opts.Index,
+		sourceType: sourceType,
+		host:       opts.Host,
+		client:     defaultHTTPClient(opts.HTTPClient),
+	}
+}
+
+func (s *Splunk) Name() string { return "splunk" }
+
+type splunkEvent struct {
+	Time       float64           `json:"time"`
+	Host       string            `json:"host,omitempty"`
+	Source     string            `json:"source"`
+	SourceType string            `json:"sourcetype"`
+	Index      string            `json:"index,omitempty"`
+	Event      map[string]any    `json:"event"`
+	Fields     map[string]string `json:"fields,omitempty"`
+}
+
+// Send 배포 기록을 HEC event 형식으로 묶어서 전송. 태그는 검색용 indexed field로도 등록한다.
+func (s *Splunk) Send(ctx context.Context, events []Event) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, e := range events {
+		if err := enc.Encode(splunkEvent{
+			Time:       float64(e.Time.UnixMilli()) / 1000,
+			Host:       s.host,
+			Source:     "devops-relay",
+			SourceType: s.sourceType,
+			Index:      s.index,
+			Event:      record(e),
+			Fields:     e.Tags,
+		}); err != nil {
+			return Permanent(fmt.Errorf("splunk: failed to marshal event: %w", err))
+		}
+	}
+
+	header := http.Header{"Authorization": {"Splunk " + s.token}}
+	_, err := post(ctx, s.client, "splunk", s.url, "application/json", header, body.Bytes())
+	return err
+}
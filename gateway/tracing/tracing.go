@@ -0,0 +1,113 @@
+// Package tracing OpenTelemetry 분산 추적 (OTLP export, W3C trace context 전파)
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+const instrumentationName = "github.com/antonio-kim-1994/devops-relay/gateway"
+
+// Options 추적 데이터 export 설정
+type Options struct {
+	ServiceName string
+	// otlp 설정 시 export, 그 외(none)에는 no-op
+	Exporter string
+	// OTLP 전송 방식 (grpc, http/protobuf)
+	Protocol string
+}
+
+// Setup 전역 TracerProvider 및 W3C trace context propagator 설정. 반환된 함수로 남은 span을 flush한다.
+// endpoint, header, sampler 등은 OTEL_EXPORTER_OTLP_*, OTEL_TRACES_SAMPLER 환경 변수를 따른다.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	switch opts.Exporter {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q (otlp, none)", opts.Exporter)
+	}
+
+	var client otlptrace.Client
+	switch opts.Protocol {
+	case "", "http/protobuf":
+		client = otlptracehttp.NewClient()
+	case "grpc":
+		client = otlptracegrpc.NewClient()
+	default:
+		return nil, fmt.Errorf("tracing: unknown otlp protocol %q (grpc, http/protobuf)", opts.Protocol)
+	}
+
+	exporter, err := otlptrace.New(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to create otlp exporter: %w", err)
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(opts.ServiceName)),
+	)
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) && !errors.Is(err, resource.ErrSchemaURLConflict) {
+		return nil, fmt.Errorf("tracing: failed to create resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start 하위 span 시작
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End span 종료. err가 있는 경우 오류 상태로 기록한다.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Transport 요청별 client span 생성 및 trace context 헤더 전파
+// name은 span 이름 접두어로 사용한다. (예: relay POST /update/github)
+func Transport(name string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base, otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return fmt.Sprintf("%s %s %s", name, r.Method, r.URL.Path)
+	}))
+}
+
+// LogHook ctx가 지정된 로그(log.Ctx(ctx), log.Info().Ctx(ctx))에 trace_id, span_id 기록
+type LogHook struct{}
+
+func (LogHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	span := trace.SpanContextFromContext(e.GetCtx())
+	if !span.IsValid() {
+		return
+	}
+	e.Str("trace_id", span.TraceID().String()).Str("span_id", span.SpanID().String())
+}
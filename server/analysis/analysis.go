@@ -0,0 +1,132 @@
+// Package analysis 메트릭 기반 Canary 분석
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"text/template"
+	"time"
+)
+
+// Provider 메트릭 조회 백엔드
+type Provider interface {
+	// Query window 구간의 메트릭 값 조회
+	Query(ctx context.Context, query string, window time.Duration) (float64, error)
+}
+
+// Vars 쿼리 템플릿 변수
+type Vars struct {
+	Application string
+	Namespace   string
+	Environment string
+	DockerTag   string
+}
+
+// Check 쿼리별 분석 결과
+type Check struct {
+	Name      string   `json:"name"`
+	Value     float64  `json:"value"`
+	Baseline  *float64 `json:"baseline,omitempty"`
+	Threshold string   `json:"threshold"`
+	Passed    bool     `json:"passed"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Result Canary 분석 결과
+type Result struct {
+	Provider string  `json:"provider"`
+	Checks   []Check `json:"checks"`
+}
+
+// Passed 모든 쿼리가 기준을 통과했는지 여부
+func (r Result) Passed() bool {
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Abort block 설정 시 분석 기준을 통과하지 못한(조회 실패 포함) 배포를 승인 요청 없이 중단할지 여부
+func (r Result) Abort(cfg config.AnalysisConfig) bool {
+	return cfg.Block && !r.Passed()
+}
+
+// Run 설정된 쿼리를 조회해 기준값과 비교한다. 조회에 실패한 쿼리는 실패로 처리한다.
+func Run(ctx context.Context, p Provider, cfg config.AnalysisConfig, vars Vars) Result {
+	result := Result{Provider: cfg.Provider}
+	for _, q := range cfg.Queries {
+		result.Checks = append(result.Checks, runQuery(ctx, p, q, cfg.Window, vars))
+	}
+	return result
+}
+
+func runQuery(ctx context.Context, p Provider, q config.AnalysisQuery, window time.Duration, vars Vars) Check {
+	check := Check{Name: q.Name, Threshold: threshold(q)}
+
+	value, err := query(ctx, p, q.Query, window, vars)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	check.Value = value
+
+	check.Passed = true
+	if q.Max != nil && value > *q.Max {
+		check.Passed = false
+	}
+	if q.Min != nil && value < *q.Min {
+		check.Passed = false
+	}
+
+	if q.Baseline != "" {
+		baseline, err := query(ctx, p, q.Baseline, window, vars)
+		if err != nil {
+			check.Passed = false
+			check.Error = fmt.Sprintf("baseline: %v", err)
+			return check
+		}
+		check.Baseline = &baseline
+		if q.MaxRatio != nil && value > baseline**q.MaxRatio {
+			check.Passed = false
+		}
+	}
+	return check
+}
+
+func query(ctx context.Context, p Provider, text string, window time.Duration, vars Vars) (float64, error) {
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return 0, fmt.Errorf("analysis: failed to parse query template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return 0, fmt.Errorf("analysis: failed to render query template: %w", err)
+	}
+	return p.Query(ctx, buf.String(), window)
+}
+
+// threshold 기준값 표시 (예: ≤ 0.01, ≤ stable × 1.2)
+func threshold(q config.AnalysisQuery) string {
+	var s string
+	if q.Min != nil {
+		s = fmt.Sprintf("≥ %g", *q.Min)
+	}
+	if q.Max != nil {
+		if s != "" {
+			s += ", "
+		}
+		s += fmt.Sprintf("≤ %g", *q.Max)
+	}
+	if q.MaxRatio != nil {
+		if s != "" {
+			s += ", "
+		}
+		s += fmt.Sprintf("≤ stable × %g", *q.MaxRatio)
+	}
+	return s
+}
@@ -0,0 +1,221 @@
+package analysis_test
+
+import (
+	"context"
+	"github.com/antonio-kim-1994/devops-relay/server/analysis"
+	"github.com/antonio-kim-1994/devops-relay/server/analysis/analysistest"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var vars = analysis.Vars{
+	Application: "homepage-front",
+	Namespace:   "homepage",
+	Environment: "prod",
+	DockerTag:   "v1.2.3",
+}
+
+const (
+	errorRateQuery    = `sum(rate(http_requests_total{service="{{.Application}}-preview",code=~"5.."}[5m])) / sum(rate(http_requests_total{service="{{.Application}}-preview"}[5m]))`
+	errorRateRendered = `sum(rate(http_requests_total{service="homepage-front-preview",code=~"5.."}[5m])) / sum(rate(http_requests_total{service="homepage-front-preview"}[5m]))`
+	latencyQuery      = `histogram_quantile(0.99, rate(latency_bucket{service="{{.Application}}-preview"}[5m]))`
+	latencyRendered   = `histogram_quantile(0.99, rate(latency_bucket{service="homepage-front-preview"}[5m]))`
+	baselineQuery     = `histogram_quantile(0.99, rate(latency_bucket{service="{{.Application}}-stable"}[5m]))`
+	baselineRendered  = `histogram_quantile(0.99, rate(latency_bucket{service="homepage-front-stable"}[5m]))`
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func newPrometheus(t *testing.T) (*analysistest.Server, analysis.Provider) {
+	t.Helper()
+	srv := analysistest.NewPrometheus()
+	t.Cleanup(srv.Close)
+	return srv, analysis.NewPrometheus(srv.URL+"/", &http.Client{Timeout: 5 * time.Second})
+}
+
+func newDatadog(t *testing.T, apiKey, appKey string) (*analysistest.Server, analysis.Provider) {
+	t.Helper()
+	srv := analysistest.NewDatadog()
+	t.Cleanup(srv.Close)
+	return srv, analysis.NewDatadog(srv.URL, "", apiKey, appKey, &http.Client{Timeout: 5 * time.Second})
+}
+
+func TestThreshold(t *testing.T) {
+	srv, provider := newPrometheus(t)
+	srv.SetValue(errorRateRendered, 0.02)
+
+	cfg := config.AnalysisConfig{Provider: "prometheus", Queries: []config.AnalysisQuery{
+		{Name: "error-rate", Query: errorRateQuery, Max: ptr(0.01)},
+		{Name: "error-rate-min", Query: errorRateQuery, Min: ptr(0.001), Max: ptr(0.05)},
+	}}
+	result := analysis.Run(context.Background(), provider, cfg, vars)
+
+	if len(result.Checks) != 2 {
+		t.Fatalf("checks = %+v", result.Checks)
+	}
+	if c := result.Checks[0]; c.Passed || c.Value != 0.02 || c.Threshold != "≤ 0.01" || c.Error != "" {
+		t.Errorf("error-rate check = %+v, want failed with value 0.02", c)
+	}
+	if c := result.Checks[1]; !c.Passed || c.Threshold != "≥ 0.001, ≤ 0.05" {
+		t.Errorf("error-rate-min check = %+v, want passed", c)
+	}
+	if result.Passed() {
+		t.Error("result passed, want failed")
+	}
+	if got := srv.Queries(); !slices.Equal(got, []string{errorRateRendered, errorRateRendered}) {
+		t.Errorf("queries = %q, want rendered templates", got)
+	}
+}
+
+func TestMaxRatio(t *testing.T) {
+	cases := []struct {
+		name       string
+		preview    float64
+		wantPassed bool
+	}{
+		{name: "within ratio", preview: 0.24, wantPassed: true},
+		{name: "exceeds ratio", preview: 0.25, wantPassed: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, provider := newPrometheus(t)
+			srv.SetValue(latencyRendered, tc.preview)
+			srv.SetValue(baselineRendered, 0.2)
+
+			cfg := config.AnalysisConfig{Provider: "prometheus", Queries: []config.AnalysisQuery{
+				{Name: "p99-latency", Query: latencyQuery, Baseline: baselineQuery, MaxRatio: ptr(1.2)},
+			}}
+			c := analysis.Run(context.Background(), provider, cfg, vars).Checks[0]
+
+			if c.Passed != tc.wantPassed {
+				t.Errorf("passed = %t, want %t (check %+v)", c.Passed, tc.wantPassed, c)
+			}
+			if c.Baseline == nil || *c.Baseline != 0.2 || c.Threshold != "≤ stable × 1.2" {
+				t.Errorf("check = %+v, want baseline 0.2", c)
+			}
+		})
+	}
+}
+
+func TestBaselineNoData(t *testing.T) {
+	srv, provider := newPrometheus(t)
+	srv.SetValue(latencyRendered, 0.1)
+
+	cfg := config.AnalysisConfig{Provider: "prometheus", Queries: []config.AnalysisQuery{
+		{Name: "p99-latency", Query: latencyQuery, Baseline: baselineQuery, MaxRatio: ptr(1.2)},
+	}}
+	c := analysis.Run(context.Background(), provider, cfg, vars).Checks[0]
+
+	if c.Passed || !strings.HasPrefix(c.Error, "baseline: ") {
+		t.Errorf("check = %+v, want failed with baseline error", c)
+	}
+}
+
+func TestNoData(t *testing.T) {
+	_, prometheus := newPrometheus(t)
+	_, datadog := newDatadog(t, "api-key", "app-key")
+
+	for name, provider := range map[string]analysis.Provider{"prometheus": prometheus, "datadog": datadog} {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.AnalysisConfig{Provider: name, Window: 5 * time.Minute, Queries: []config.AnalysisQuery{
+				{Name: "error-rate", Query: errorRateQuery, Max: ptr(0.01)},
+			}}
+			result := analysis.Run(context.Background(), provider, cfg, vars)
+
+			// 데이터가 없는 쿼리는 통과로 간주하지 않는다.
+			c := result.Checks[0]
+			if c.Passed || !strings.Contains(c.Error, "0 series") {
+				t.Errorf("check = %+v, want failed with no series error", c)
+			}
+			if result.Passed() {
+				t.Error("result passed, want failed")
+			}
+		})
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	srv, provider := newPrometheus(t)
+	srv.SetError(errorRateRendered, "parse error: unexpected end of input")
+
+	cfg := config.AnalysisConfig{Provider: "prometheus", Queries: []config.AnalysisQuery{
+		{Name: "error-rate", Query: errorRateQuery, Max: ptr(0.01)},
+	}}
+	c := analysis.Run(context.Background(), provider, cfg, vars).Checks[0]
+
+	if c.Passed || !strings.Contains(c.Error, "query failed (400): parse error") {
+		t.Errorf("check = %+v, want failed with server error", c)
+	}
+}
+
+func TestTemplateError(t *testing.T) {
+	srv, provider := newPrometheus(t)
+
+	cfg := config.AnalysisConfig{Provider: "prometheus", Queries: []config.AnalysisQuery{
+		{Name: "unknown-var", Query: `up{service="{{.Service}}"}`, Max: ptr(1)},
+	}}
+	c := analysis.Run(context.Background(), provider, cfg, vars).Checks[0]
+
+	if c.Passed || !strings.Contains(c.Error, "failed to render query template") {
+		t.Errorf("check = %+v, want template error", c)
+	}
+	if got := srv.Queries(); len(got) != 0 {
+		t.Errorf("queries = %q, want none", got)
+	}
+}
+
+func TestDatadog(t *testing.T) {
+	srv, provider := newDatadog(t, "api-key", "app-key")
+	srv.SetValue(errorRateRendered, 0.004)
+
+	cfg := config.AnalysisConfig{Provider: "datadog", Window: 5 * time.Minute, Queries: []config.AnalysisQuery{
+		{Name: "error-rate", Query: errorRateQuery, Max: ptr(0.01)},
+	}}
+	result := analysis.Run(context.Background(), provider, cfg, vars)
+
+	if c := result.Checks[0]; !c.Passed || c.Value != 0.004 || c.Error != "" {
+		t.Errorf("check = %+v, want passed with value 0.004", c)
+	}
+	if result.Provider != "datadog" || !result.Passed() {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestDatadogUnauthorized(t *testing.T) {
+	srv, provider := newDatadog(t, "api-key", "")
+	srv.SetValue(errorRateRendered, 0.004)
+
+	_, err := provider.Query(context.Background(), errorRateRendered, 5*time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "query failed (403)") {
+		t.Errorf("err = %v, want 403 query error", err)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	passed := analysis.Result{Checks: []analysis.Check{{Name: "error-rate", Passed: true}}}
+	failed := analysis.Result{Checks: []analysis.Check{{Name: "error-rate", Passed: true}, {Name: "p99-latency", Error: "no data"}}}
+
+	cases := []struct {
+		name   string
+		result analysis.Result
+		block  bool
+		want   bool
+	}{
+		{name: "failed with block", result: failed, block: true, want: true},
+		{name: "failed without block", result: failed, block: false, want: false},
+		{name: "passed with block", result: passed, block: true, want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.result.Abort(config.AnalysisConfig{Block: tc.block}); got != tc.want {
+				t.Errorf("Abort = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Package analysistest Prometheus/Datadog 메트릭 조회 API를 흉내내는 테스트용 HTTP 서버
+package analysistest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type flavor int
+
+const (
+	prometheus flavor = iota
+	datadog
+)
+
+// Server 쿼리 문자열별로 설정한 값을 반환하는 GET /api/v1/query를 제공한다.
+// 설정되지 않은 쿼리는 빈 결과를 반환한다.
+type Server struct {
+	*httptest.Server
+
+	flavor  flavor
+	mu      sync.Mutex
+	values  map[string]float64
+	errors  map[string]string
+	queries []string
+}
+
+// NewPrometheus Prometheus instant query 응답 형식의 서버
+func NewPrometheus() *Server {
+	return newServer(prometheus)
+}
+
+// NewDatadog Datadog timeseries query 응답 형식의 서버
+func NewDatadog() *Server {
+	return newServer(datadog)
+}
+
+func newServer(f flavor) *Server {
+	s := &Server{
+		flavor: f,
+		values: map[string]float64{},
+		errors: map[string]string{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// SetValue 쿼리 결과 값 설정
+func (s *Server) SetValue(query string, value float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[query] = value
+}
+
+// SetError 쿼리 오류 응답 설정
+func (s *Server) SetError(query, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors[query] = message
+}
+
+// Queries 수신한 쿼리 목록
+func (s *Server) Queries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.queries...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || r.URL.Path != "/api/v1/query" {
+		http.NotFound(w, r)
+		return
+	}
+	if s.flavor == datadog && (r.Header.Get("DD-API-KEY") == "" || r.Header.Get("DD-APPLICATION-KEY") == "") {
+		writeJSON(w, http.StatusForbidden, map[string]any{"status": "error", "error": "Forbidden"})
+		return
+	}
+
+	query := r.URL.Query().Get("query")
+
+	s.mu.Lock()
+	s.queries = append(s.queries, query)
+	value, exist := s.values[query]
+	message, failed := s.errors[query]
+	s.mu.Unlock()
+
+	if failed {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"status": "error", "error": message})
+		return
+	}
+
+	now := time.Now()
+	switch s.flavor {
+	case prometheus:
+		result := []any{}
+		if exist {
+			result = append(result, map[string]any{
+				"metric": map[string]string{},
+				"value":  []any{float64(now.Unix()), strconv.FormatFloat(value, 'f', -1, 64)},
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"status": "success",
+			"data":   map[string]any{"resultType": "vector", "result": result},
+		})
+	case datadog:
+		series := []any{}
+		if exist {
+			series = append(series, map[string]any{
+				"pointlist": [][]any{{float64(now.UnixMilli()), value}},
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"status": "ok", "series": series})
+	default:
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": fmt.Sprintf("unknown flavor %d", s.flavor)})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
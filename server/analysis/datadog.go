@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Datadog Datadog Metrics API(timeseries query) 기반 Provider
+type Datadog struct {
+	baseURL    string
+	apiKey     string
+	appKey     string
+	httpClient *http.Client
+}
+
+// NewDatadog baseURL 미설정 시 site(예: datadoghq.com) 기준 API 주소를 사용한다.
+func NewDatadog(baseURL, site, apiKey, appKey string, httpClient *http.Client) *Datadog {
+	if baseURL == "" {
+		if site == "" {
+			site = "datadoghq.com"
+		}
+		baseURL = fmt.Sprintf("https://api.%s", site)
+	}
+	return &Datadog{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		appKey:     appKey,
+		httpClient: httpClient,
+	}
+}
+
+type datadogResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Series []struct {
+		// [ <unix_ms>, <value|null> ]
+		Pointlist [][]*float64 `json:"pointlist"`
+	} `json:"series"`
+}
+
+// Query GET /api/v1/query. window 구간 내 데이터 포인트의 평균을 반환한다.
+func (d *Datadog) Query(ctx context.Context, query string, window time.Duration) (float64, error) {
+	now := time.Now()
+	params := url.Values{
+		"query": {query},
+		"from":  {strconv.FormatInt(now.Add(-window).Unix(), 10)},
+		"to":    {strconv.FormatInt(now.Unix(), 10)},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/query?%s", d.baseURL, params.Encode()), nil)
+	if err != nil {
+		return 0, fmt.Errorf("datadog: failed to create request: %w", err)
+	}
+	req.Header.Set("DD-API-KEY", d.apiKey)
+	req.Header.Set("DD-APPLICATION-KEY", d.appKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("datadog: failed to query: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("datadog: failed to read response body: %w", err)
+	}
+
+	var r datadogResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return 0, fmt.Errorf("datadog: failed to unmarshal response (%d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest || r.Status == "error" {
+		return 0, fmt.Errorf("datadog: query failed (%d): %s", resp.StatusCode, r.Error)
+	}
+
+	if len(r.Series) != 1 {
+		return 0, fmt.Errorf("datadog: query returned %d series, expected 1", len(r.Series))
+	}
+
+	var sum float64
+	var count int
+	for _, point := range r.Series[0].Pointlist {
+		if len(point) != 2 || point[1] == nil {
+			continue
+		}
+		sum += *point[1]
+		count++
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("datadog: no data points in the last %s", window)
+	}
+	return sum / float64(count), nil
+}
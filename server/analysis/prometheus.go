@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Prometheus Prometheus HTTP API(instant query) 기반 Provider
+type Prometheus struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewPrometheus(baseURL string, httpClient *http.Client) *Prometheus {
+	return &Prometheus{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value []any `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// Query GET /api/v1/query. 조회 구간은 쿼리의 range vector로 지정하므로 window는 사용하지 않는다.
+// 결과는 단일 series(vector) 혹은 scalar여야 한다.
+func (p *Prometheus) Query(ctx context.Context, query string, _ time.Duration) (float64, error) {
+	params := url.Values{"query": {query}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/query?%s", p.baseURL, params.Encode()), nil)
+	if err != nil {
+		return 0, fmt.Errorf("prometheus: failed to create request: %w", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("prometheus: failed to query: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("prometheus: failed to read response body: %w", err)
+	}
+
+	var r prometheusResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return 0, fmt.Errorf("prometheus: failed to unmarshal response (%d): %w", resp.StatusCode, err)
+	}
+	if r.Status != "success" {
+		return 0, fmt.Errorf("prometheus: query failed (%d): %s", resp.StatusCode, r.Error)
+	}
+
+	var sample []any
+	switch r.Data.ResultType {
+	case "vector":
+		if len(r.Data.Result) != 1 {
+			return 0, fmt.Errorf("prometheus: query returned %d series, expected 1", len(r.Data.Result))
+		}
+		sample = r.Data.Result[0].Value
+	case "scalar":
+		var scalar struct {
+			Data struct {
+				Result []any `json:"result"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &scalar); err != nil {
+			return 0, fmt.Errorf("prometheus: failed to unmarshal scalar result: %w", err)
+		}
+		sample = scalar.Data.Result
+	default:
+		return 0, fmt.Errorf("prometheus: unsupported result type %q", r.Data.ResultType)
+	}
+
+	// [ <unix_time>, "<value>" ]
+	if len(sample) != 2 {
+		return 0, errors.New("prometheus: invalid sample")
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, errors.New("prometheus: invalid sample value")
+	}
+	return strconv.ParseFloat(value, 64)
+}
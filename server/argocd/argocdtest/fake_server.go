@@ -0,0 +1,437 @@
+// Package argocdtest ArgoCD API를 흉내내는 테스트용 HTTP 서버
+package argocdtest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyncBehavior Sync 요청 이후 Application 상태
+type SyncBehavior struct {
+	Phase     string
+	Message   string
+	Health    string
+	Resources []argocd.ResourceResult
+}
+
+// Server 세션, Application 조회/수정/Sync/Rollback, resource-tree, managed-resources API를 제공한다.
+type Server struct {
+	*httptest.Server
+
+	Username string
+	Password string
+	TokenTTL time.Duration
+
+	mu        sync.Mutex
+	apps      map[string]*argocd.Application
+	behaviors map[string]SyncBehavior
+	trees     map[string]argocd.ResourceTree
+	managed   map[string]argocd.ManagedResources
+	actions   []string
+	resources map[string]map[string]any
+	tokens    map[string]time.Time
+	sessions  int
+	requests  []string
+}
+
+func NewServer(username, password string) *Server {
+	s := &Server{
+		Username:  username,
+		Password:  password,
+		TokenTTL:  time.Hour,
+		apps:      map[string]*argocd.Application{},
+		behaviors: map[string]SyncBehavior{},
+		trees:     map[string]argocd.ResourceTree{},
+		managed:   map[string]argocd.ManagedResources{},
+		resources: map[string]map[string]any{},
+		tokens:    map[string]time.Time{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// AddApplication Application 등록
+func (s *Server) AddApplication(app argocd.Application) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if app.Status.Health.Status == "" {
+		app.Status.Health.Status = "Healthy"
+	}
+	s.apps[app.Metadata.Name] = &app
+}
+
+// Application 현재 Application 상태
+func (s *Server) Application(name string) (argocd.Application, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app, exist := s.apps[name]
+	if !exist {
+		return argocd.Application{}, false
+	}
+	return *app, true
+}
+
+// SetSyncBehavior 이후 Sync 요청의 Operation 결과 설정 (기본: Succeeded/Healthy)
+func (s *Server) SetSyncBehavior(name string, b SyncBehavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.behaviors[name] = b
+}
+
+func (s *Server) SetResourceTree(name string, tree argocd.ResourceTree) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trees[name] = tree
+}
+
+func (s *Server) SetManagedResources(name string, resources argocd.ManagedResources) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.managed[name] = resources
+}
+
+// SetResource GET resource 요청에 반환할 Live manifest 설정
+func (s *Server) SetResource(name string, key argocd.ResourceKey, manifest map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[resourceID(name, key.Kind, key.Namespace, key.Name)] = manifest
+}
+
+// ExpireTokens 발급된 세션 토큰을 모두 만료시켜 이후 요청이 401을 받도록 한다.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]time.Time{}
+}
+
+// Sessions 세션 발급 횟수
+func (s *Server) Sessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions
+}
+
+// ResourceActions 실행된 리소스 액션 및 패치 목록 ("app/kind/namespace/name: action")
+func (s *Server) ResourceActions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.actions...)
+}
+
+// Requests 수신한 요청 목록 ("METHOD /path")
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+	if r.Method == http.MethodPost && r.URL.Path == "/api/v1/session" {
+		s.createSession(w, r)
+		return
+	}
+
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid session: token is expired")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/applications/"), "/")
+	if !strings.HasPrefix(r.URL.Path, "/api/v1/applications/") || parts[0] == "" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	app, exist := s.apps[parts[0]]
+	if !exist {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("applications.argoproj.io %q not found", parts[0]))
+		return
+	}
+
+	sub := strings.Join(parts[1:], "/")
+
+	switch {
+	case r.Method == http.MethodGet && sub == "":
+		writeJSON(w, app)
+	case r.Method == http.MethodPatch && sub == "":
+		s.patch(w, r, app)
+	case r.Method == http.MethodPost && sub == "sync":
+		s.sync(w, r, app)
+	case r.Method == http.MethodPost && sub == "rollback":
+		s.rollback(w, r, app)
+	case r.Method == http.MethodGet && sub == "resource-tree":
+		writeJSON(w, s.trees[app.Metadata.Name])
+	case r.Method == http.MethodGet && sub == "managed-resources":
+		writeJSON(w, s.managed[app.Metadata.Name])
+	case r.Method == http.MethodGet && sub == "resource":
+		q := r.URL.Query()
+		manifest, exist := s.resources[resourceID(app.Metadata.Name, q.Get("kind"), q.Get("namespace"), q.Get("resourceName"))]
+		if !exist {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", q.Get("kind"), q.Get("resourceName")))
+			return
+		}
+		data, _ := json.Marshal(manifest)
+		writeJSON(w, map[string]string{"manifest": string(data)})
+	case r.Method == http.MethodPost && (sub == "resource/actions" || sub == "resource"):
+		s.resource(w, r, app)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) createSession(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Username != s.Username || body.Password != s.Password {
+		writeError(w, http.StatusUnauthorized, "Invalid username or password")
+		return
+	}
+
+	expiry := time.Now().Add(s.TokenTTL)
+	token := newToken(expiry)
+	s.tokens[token] = expiry
+	s.sessions++
+	writeJSON(w, map[string]string{"token": token})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	expiry, exist := s.tokens[token]
+	return exist && time.Now().Before(expiry)
+}
+
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, app *argocd.Application) {
+	var body struct {
+		Patch     string `json:"patch"`
+		PatchType string `json:"patchType"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PatchType != "merge" {
+		writeError(w, http.StatusBadRequest, "only merge patch is supported")
+		return
+	}
+
+	var patch, current map[string]any
+	data, _ := json.Marshal(app)
+	if err := json.Unmarshal(data, &current); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := json.Unmarshal([]byte(body.Patch), &patch); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, _ = json.Marshal(mergePatch(current, patch))
+	var patched argocd.Application
+	if err := json.Unmarshal(data, &patched); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	*app = patched
+	writeJSON(w, app)
+}
+
+func (s *Server) sync(w http.ResponseWriter, r *http.Request, app *argocd.Application) {
+	var req argocd.SyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	b, exist := s.behaviors[app.Metadata.Name]
+	if !exist {
+		b = SyncBehavior{Phase: "Succeeded", Health: "Healthy", Message: "successfully synced (all tasks run)"}
+	}
+
+	revision := req.Revision
+	if revision == "" && app.Spec.Source != nil {
+		revision = app.Spec.Source.TargetRevision
+	}
+
+	now := time.Now().UTC()
+	app.Status.OperationState = &argocd.OperationState{
+		Phase:      b.Phase,
+		Message:    b.Message,
+		StartedAt:  now,
+		FinishedAt: &now,
+		SyncResult: &argocd.SyncResult{Revision: revision, Resources: b.Resources},
+	}
+
+	if !req.DryRun {
+		app.Status.Health.Status = b.Health
+		app.Status.Sync.Status = "Synced"
+		app.Status.Sync.Revision = revision
+		app.Status.Summary.Images = liveImages(app)
+
+		if b.Phase == "Succeeded" {
+			var source *argocd.ApplicationSource
+			if app.Spec.Source != nil {
+				copied := *app.Spec.Source
+				source = &copied
+			}
+			app.Status.History = append(app.Status.History, argocd.RevisionHistory{
+				ID:         int64(len(app.Status.History)),
+				Revision:   revision,
+				DeployedAt: now,
+				Source:     source,
+			})
+		}
+	}
+
+	writeJSON(w, app)
+}
+
+// resource 리소스 액션/패치 요청 기록 (요청 본문은 JSON 문자열)
+func (s *Server) resource(w http.ResponseWriter, r *http.Request, app *argocd.Application) {
+	var body string
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	q := r.URL.Query()
+	if q.Get("kind") == "" || q.Get("resourceName") == "" {
+		writeError(w, http.StatusBadRequest, "kind and resourceName are required")
+		return
+	}
+
+	s.actions = append(s.actions, fmt.Sprintf("%s/%s/%s/%s: %s", app.Metadata.Name, q.Get("kind"), q.Get("namespace"), q.Get("resourceName"), body))
+	writeJSON(w, map[string]any{})
+}
+
+func (s *Server) rollback(w http.ResponseWriter, r *http.Request, app *argocd.Application) {
+	var req argocd.RollbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var target *argocd.RevisionHistory
+	for i := range app.Status.History {
+		if app.Status.History[i].ID == req.ID {
+			target = &app.Status.History[i]
+		}
+	}
+	if target == nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("application %s does not have deployment with id %d", app.Metadata.Name, req.ID))
+		return
+	}
+
+	now := time.Now().UTC()
+	app.Status.OperationState = &argocd.OperationState{
+		Phase:      "Succeeded",
+		Message:    fmt.Sprintf("rolled back to revision %s", target.Revision),
+		StartedAt:  now,
+		FinishedAt: &now,
+		SyncResult: &argocd.SyncResult{Revision: target.Revision},
+	}
+
+	if !req.DryRun {
+		if target.Source != nil {
+			copied := *target.Source
+			app.Spec.Source = &copied
+		}
+		app.Status.Sync.Revision = target.Revision
+		app.Status.Sync.Status = "OutOfSync"
+		app.Status.Health.Status = "Healthy"
+		app.Status.Summary.Images = liveImages(app)
+		app.Status.History = append(app.Status.History, argocd.RevisionHistory{
+			ID:         int64(len(app.Status.History)),
+			Revision:   target.Revision,
+			DeployedAt: now,
+			Source:     target.Source,
+		})
+	}
+
+	writeJSON(w, app)
+}
+
+func resourceID(app, kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", app, kind, namespace, name)
+}
+
+// liveImages kustomize images 혹은 image.tag Helm parameter를 Live 이미지로 간주
+func liveImages(app *argocd.Application) []string {
+	if app.Spec.Source == nil {
+		return app.Status.Summary.Images
+	}
+
+	if k := app.Spec.Source.Kustomize; k != nil && len(k.Images) > 0 {
+		images := make([]string, 0, len(k.Images))
+		for _, i := range k.Images {
+			if idx := strings.Index(i, "="); idx >= 0 {
+				i = i[idx+1:]
+			}
+			images = append(images, i)
+		}
+		return images
+	}
+
+	if h := app.Spec.Source.Helm; h != nil {
+		for _, p := range h.Parameters {
+			if p.Name == "image.tag" {
+				return []string{fmt.Sprintf("%s:%s", app.Metadata.Name, p.Value)}
+			}
+		}
+	}
+	return app.Status.Summary.Images
+}
+
+// mergePatch JSON Merge Patch (RFC 7386)
+func mergePatch(target, patch map[string]any) map[string]any {
+	if target == nil {
+		target = map[string]any{}
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		if pm, ok := v.(map[string]any); ok {
+			tm, _ := target[k].(map[string]any)
+			target[k] = mergePatch(tm, pm)
+			continue
+		}
+		target[k] = v
+	}
+	return target
+}
+
+func newToken(expiry time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	payload, _ := json.Marshal(map[string]any{"exp": expiry.Unix(), "sub": "admin"})
+
+	sig := make([]byte, 8)
+	_, _ = rand.Read(sig)
+	return fmt.Sprintf("%s.%s.%s", header, base64.RawURLEncoding.EncodeToString(payload), hex.EncodeToString(sig))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"error": message, "message": message, "code": status})
+}
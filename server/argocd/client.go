@@ -0,0 +1,336 @@
+package argocd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/metrics"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	// JWT 만료 시각을 알 수 없는 경우 세션 토큰 캐시 기간
+	defaultSessionTTL = 10 * time.Minute
+	// 만료 직전 토큰 사용을 방지하기 위한 여유 시간
+	expiryLeeway = time.Minute
+)
+
+type Options struct {
+	BaseURL string
+	// 프로젝트 API 토큰. 설정된 경우 세션 로그인 없이 사용한다.
+	APIToken string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+// Client ArgoCD REST API 클라이언트
+// 세션 토큰은 JWT 만료 시각까지 캐시하며, 만료 혹은 401 응답 시 재발급한다.
+type Client struct {
+	baseURL    string
+	apiToken   string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func New(opts Options) *Client {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(opts.BaseURL, "/"),
+		apiToken:   opts.APIToken,
+		username:   opts.Username,
+		password:   opts.Password,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// BaseURL ArgoCD 서버 주소
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// GetApplication GET /api/v1/applications/{name}
+func (c *Client) GetApplication(ctx context.Context, name string) (*Application, error) {
+	var app Application
+	if err := c.do(ctx, http.MethodGet, applicationPath(name, ""), nil, &app); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// PatchApplication PATCH /api/v1/applications/{name} (patchType: merge, json)
+func (c *Client) PatchApplication(ctx context.Context, name string, patch []byte, patchType string) (*Application, error) {
+	payload := map[string]string{
+		"name":      name,
+		"patch":     string(patch),
+		"patchType": patchType,
+	}
+
+	var app Application
+	if err := c.do(ctx, http.MethodPatch, applicationPath(name, ""), payload, &app); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// Sync POST /api/v1/applications/{name}/sync
+func (c *Client) Sync(ctx context.Context, name string, req SyncRequest) (*Application, error) {
+	var app Application
+	if err := c.do(ctx, http.MethodPost, applicationPath(name, "sync"), req, &app); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// Rollback POST /api/v1/applications/{name}/rollback
+func (c *Client) Rollback(ctx context.Context, name string, req RollbackRequest) (*Application, error) {
+	var app Application
+	if err := c.do(ctx, http.MethodPost, applicationPath(name, "rollback"), req, &app); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// ResourceTree GET /api/v1/applications/{name}/resource-tree
+func (c *Client) ResourceTree(ctx context.Context, name string) (*ResourceTree, error) {
+	var tree ResourceTree
+	if err := c.do(ctx, http.MethodGet, applicationPath(name, "resource-tree"), nil, &tree); err != nil {
+		return nil, err
+	}
+	return &tree, nil
+}
+
+// ManagedResources GET /api/v1/applications/{name}/managed-resources
+func (c *Client) ManagedResources(ctx context.Context, name string) (*ManagedResources, error) {
+	var resources ManagedResources
+	if err := c.do(ctx, http.MethodGet, applicationPath(name, "managed-resources"), nil, &resources); err != nil {
+		return nil, err
+	}
+	return &resources, nil
+}
+
+// RunResourceAction POST /api/v1/applications/{name}/resource/actions
+// Rollout의 pause, resume 등 ArgoCD 리소스 액션 실행
+func (c *Client) RunResourceAction(ctx context.Context, name string, key ResourceKey, action string) error {
+	path := fmt.Sprintf("%s?%s", applicationPath(name, "resource/actions"), resourceQuery(key).Encode())
+	return c.do(ctx, http.MethodPost, path, action, nil)
+}
+
+// GetResource GET /api/v1/applications/{name}/resource
+// Application 관리 리소스의 Live manifest 반환
+func (c *Client) GetResource(ctx context.Context, name string, key ResourceKey) (map[string]any, error) {
+	path := fmt.Sprintf("%s?%s", applicationPath(name, "resource"), resourceQuery(key).Encode())
+
+	var resp struct {
+		Manifest string `json:"manifest"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	var manifest map[string]any
+	if err := json.Unmarshal([]byte(resp.Manifest), &manifest); err != nil {
+		return nil, fmt.Errorf("argocd: failed to unmarshal resource manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// PatchResource POST /api/v1/applications/{name}/resource (patchType: application/merge-patch+json, application/json-patch+json)
+func (c *Client) PatchResource(ctx context.Context, name string, key ResourceKey, patch []byte, patchType string) error {
+	query := resourceQuery(key)
+	query.Set("patchType", patchType)
+	path := fmt.Sprintf("%s?%s", applicationPath(name, "resource"), query.Encode())
+	return c.do(ctx, http.MethodPost, path, string(patch), nil)
+}
+
+// Session 세션 토큰 반환. 캐시된 토큰이 만료되었거나 없는 경우 로그인한다.
+func (c *Client) Session(ctx context.Context) (string, error) {
+	if c.apiToken != "" {
+		return c.apiToken, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	payload := map[string]string{
+		"username": c.username,
+		"password": c.password,
+	}
+
+	var session struct {
+		Token string `json:"token"`
+	}
+	if err := c.send(ctx, http.MethodPost, "api/v1/session", "", payload, &session); err != nil {
+		return "", fmt.Errorf("argocd: failed to create session: %w", err)
+	}
+	if session.Token == "" {
+		return "", errors.New("argocd: session response has no token")
+	}
+
+	c.token = session.Token
+	c.tokenExpiry = tokenExpiry(session.Token)
+	log.Debug().Msgf("argocd | session token issued - Server: %s, Expiry: %s", c.baseURL, c.tokenExpiry.Format(time.RFC3339))
+	return c.token, nil
+}
+
+// invalidateSession 401 응답 시 캐시된 세션 토큰 폐기
+func (c *Client) invalidateSession(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == token {
+		c.token = ""
+		c.tokenExpiry = time.Time{}
+	}
+}
+
+// do 세션 토큰을 포함해 요청하며, 401 응답 시 세션을 재발급해 한 번 재시도한다.
+func (c *Client) do(ctx context.Context, method, path string, payload, out any) error {
+	token, err := c.Session(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.send(ctx, method, path, token, payload, out)
+
+	var apiErr *APIError
+	if c.apiToken == "" && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		log.Info().Msgf("argocd | session token rejected, refreshing session - Server: %s", c.baseURL)
+		c.invalidateSession(token)
+
+		if token, err = c.Session(ctx); err != nil {
+			return err
+		}
+		err = c.send(ctx, method, path, token, payload, out)
+	}
+	return err
+}
+
+func (c *Client) send(ctx context.Context, method, path, token string, payload, out any) (err error) {
+	ctx, span := tracing.Start(ctx, fmt.Sprintf("argocd %s %s", method, endpoint(path)), attribute.String("argocd.server", c.baseURL))
+	defer func() { tracing.End(span, err) }()
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("argocd: failed to marshal payload: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.baseURL, path), body)
+	if err != nil {
+		return fmt.Errorf("argocd: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		metrics.ObserveArgoCD(c.baseURL, method, endpoint(path), 0, time.Since(start))
+		return fmt.Errorf("argocd: %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	metrics.ObserveArgoCD(c.baseURL, method, endpoint(path), resp.StatusCode, time.Since(start))
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("argocd: failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: resp.Status}
+		var e struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &e) == nil && e.Message != "" {
+			apiErr.Message = e.Message
+		}
+		return apiErr
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("argocd: failed to unmarshal response body: %w", err)
+		}
+	}
+	return nil
+}
+
+func applicationPath(name, sub string) string {
+	path := fmt.Sprintf("api/v1/applications/%s", url.PathEscape(name))
+	if sub != "" {
+		path = fmt.Sprintf("%s/%s", path, sub)
+	}
+	return path
+}
+
+// endpoint 메트릭 라벨용 API 경로 (Application 이름 및 query 제외)
+// 예: api/v1/applications/homepage-front/sync?x=y → api/v1/applications/{name}/sync
+func endpoint(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	rest, found := strings.CutPrefix(path, "api/v1/applications/")
+	if !found {
+		return path
+	}
+	if _, sub, found := strings.Cut(rest, "/"); found {
+		return "api/v1/applications/{name}/" + sub
+	}
+	return "api/v1/applications/{name}"
+}
+
+func resourceQuery(key ResourceKey) url.Values {
+	query := url.Values{}
+	query.Set("namespace", key.Namespace)
+	query.Set("resourceName", key.Name)
+	query.Set("version", key.Version)
+	query.Set("group", key.Group)
+	query.Set("kind", key.Kind)
+	return query
+}
+
+// tokenExpiry JWT exp claim 기준 만료 시각 (서명 검증 없이 payload만 해석)
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 {
+		if payload, err := base64.RawURLEncoding.DecodeString(parts[1]); err == nil {
+			var claims struct {
+				Exp int64 `json:"exp"`
+			}
+			if json.Unmarshal(payload, &claims) == nil && claims.Exp > 0 {
+				return time.Unix(claims.Exp, 0).Add(-expiryLeeway)
+			}
+		}
+	}
+	return time.Now().Add(defaultSessionTTL)
+}
@@ -0,0 +1,268 @@
+package argocd_test
+
+import (
+	"context"
+	"errors"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd/argocdtest"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T) (*argocdtest.Server, *argocd.Client) {
+	t.Helper()
+
+	srv := argocdtest.NewServer("admin", "secret")
+	t.Cleanup(srv.Close)
+
+	var app argocd.Application
+	app.Metadata.Name = "homepage-front"
+	app.Spec.Source = &argocd.ApplicationSource{
+		RepoURL:        "https://github.com/example/manifests",
+		Path:           "homepage-front",
+		TargetRevision: "main",
+		Kustomize:      &argocd.SourceKustomize{Images: []string{"registry/homepage-front:v1"}},
+	}
+	srv.AddApplication(app)
+
+	client := argocd.New(argocd.Options{BaseURL: srv.URL + "/", Username: "admin", Password: "secret"})
+	return srv, client
+}
+
+func TestSessionCached(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetApplication(ctx, "homepage-front"); err != nil {
+			t.Fatalf("GetApplication: %v", err)
+		}
+	}
+	if got := srv.Sessions(); got != 1 {
+		t.Errorf("sessions = %d, want 1 (token cached until JWT expiry)", got)
+	}
+}
+
+func TestSessionRefreshedBeforeExpiry(t *testing.T) {
+	srv, client := newTestServer(t)
+	// 만료 여유 시간(1분)보다 짧은 토큰은 캐시하지 않고 매번 재발급한다.
+	srv.TokenTTL = 30 * time.Second
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetApplication(ctx, "homepage-front"); err != nil {
+			t.Fatalf("GetApplication: %v", err)
+		}
+	}
+	if got := srv.Sessions(); got != 2 {
+		t.Errorf("sessions = %d, want 2 (token refreshed before JWT expiry)", got)
+	}
+}
+
+func TestRetryOnceOnUnauthorized(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+
+	if _, err := client.GetApplication(ctx, "homepage-front"); err != nil {
+		t.Fatalf("GetApplication: %v", err)
+	}
+	srv.ExpireTokens()
+
+	if _, err := client.GetApplication(ctx, "homepage-front"); err != nil {
+		t.Fatalf("GetApplication after token revoked: %v", err)
+	}
+	if got := srv.Sessions(); got != 2 {
+		t.Errorf("sessions = %d, want 2", got)
+	}
+
+	want := []string{
+		"POST /api/v1/session",
+		"GET /api/v1/applications/homepage-front",
+		"GET /api/v1/applications/homepage-front",
+		"POST /api/v1/session",
+		"GET /api/v1/applications/homepage-front",
+	}
+	if got := srv.Requests(); !slices.Equal(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestAPITokenSkipsSession(t *testing.T) {
+	srv, _ := newTestServer(t)
+	client := argocd.New(argocd.Options{BaseURL: srv.URL, APIToken: "project-token"})
+
+	_, err := client.GetApplication(context.Background(), "homepage-front")
+	var apiErr *argocd.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("err = %v, want 401 APIError", err)
+	}
+	// API 토큰은 재발급할 수 없으므로 401 응답 시 재시도하지 않는다.
+	if got := srv.Requests(); len(got) != 1 {
+		t.Errorf("requests = %q, want a single request", got)
+	}
+	if got := srv.Sessions(); got != 0 {
+		t.Errorf("sessions = %d, want 0", got)
+	}
+}
+
+func TestInvalidCredentials(t *testing.T) {
+	srv, _ := newTestServer(t)
+	client := argocd.New(argocd.Options{BaseURL: srv.URL, Username: "admin", Password: "wrong"})
+
+	_, err := client.GetApplication(context.Background(), "homepage-front")
+	if err == nil || !strings.Contains(err.Error(), "failed to create session") {
+		t.Fatalf("err = %v, want session error", err)
+	}
+	var apiErr *argocd.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "Invalid username or password" {
+		t.Errorf("err = %#v, want decoded 401 APIError", apiErr)
+	}
+}
+
+func TestAPIErrorDecoding(t *testing.T) {
+	_, client := newTestServer(t)
+
+	_, err := client.GetApplication(context.Background(), "missing")
+	var apiErr *argocd.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Method != http.MethodGet || apiErr.Path != "api/v1/applications/missing" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if apiErr.Message != `applications.argoproj.io "missing" not found` {
+		t.Errorf("message = %q, want server message", apiErr.Message)
+	}
+}
+
+func TestSync(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+
+	app, err := client.Sync(ctx, "homepage-front", argocd.SyncRequest{Revision: "abc123", Prune: true})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	op := app.Status.OperationState
+	if op == nil || op.Phase != "Succeeded" || op.SyncResult.Revision != "abc123" {
+		t.Fatalf("operation state = %+v", op)
+	}
+	if len(app.Status.History) != 1 || app.Status.History[0].Revision != "abc123" {
+		t.Errorf("history = %+v", app.Status.History)
+	}
+
+	srv.SetSyncBehavior("homepage-front", argocdtest.SyncBehavior{
+		Phase:     "Failed",
+		Message:   "one or more objects failed to apply",
+		Health:    "Degraded",
+		Resources: []argocd.ResourceResult{{Kind: "Deployment", Name: "homepage-front", Status: "SyncFailed"}},
+	})
+	app, err = client.Sync(ctx, "homepage-front", argocd.SyncRequest{})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	op = app.Status.OperationState
+	if op.Phase != "Failed" || len(op.SyncResult.Resources) != 1 || !op.SyncResult.Resources[0].Failed() {
+		t.Errorf("operation state = %+v", op)
+	}
+	// 실패한 Sync는 배포 이력에 추가되지 않는다.
+	if len(app.Status.History) != 1 {
+		t.Errorf("history = %+v, want 1 entry", app.Status.History)
+	}
+}
+
+func TestPatchApplication(t *testing.T) {
+	_, client := newTestServer(t)
+
+	patch := []byte(`{"spec":{"source":{"kustomize":{"images":["registry/homepage-front:v2"]}}}}`)
+	app, err := client.PatchApplication(context.Background(), "homepage-front", patch, "merge")
+	if err != nil {
+		t.Fatalf("PatchApplication: %v", err)
+	}
+	if got := app.Spec.Source.Kustomize.Images; !slices.Equal(got, []string{"registry/homepage-front:v2"}) {
+		t.Errorf("images = %q", got)
+	}
+	if app.Spec.Source.RepoURL == "" {
+		t.Error("merge patch dropped spec.source.repoURL")
+	}
+}
+
+func TestRollback(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	for _, revision := range []string{"rev-1", "rev-2"} {
+		if _, err := client.Sync(ctx, "homepage-front", argocd.SyncRequest{Revision: revision}); err != nil {
+			t.Fatalf("Sync %s: %v", revision, err)
+		}
+	}
+
+	app, err := client.Rollback(ctx, "homepage-front", argocd.RollbackRequest{ID: 0})
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if app.Status.Sync.Revision != "rev-1" || len(app.Status.History) != 3 {
+		t.Errorf("status = %+v", app.Status)
+	}
+
+	_, err = client.Rollback(ctx, "homepage-front", argocd.RollbackRequest{ID: 42})
+	var apiErr *argocd.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || !strings.Contains(apiErr.Message, "id 42") {
+		t.Errorf("err = %v, want 400 APIError for unknown history id", err)
+	}
+}
+
+func TestResourceTree(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	srv.SetResourceTree("homepage-front", argocd.ResourceTree{Nodes: []argocd.ResourceNode{
+		{Group: "argoproj.io", Kind: "Rollout", Namespace: "homepage", Name: "homepage-front"},
+		{Group: "apps", Kind: "ReplicaSet", Namespace: "homepage", Name: "homepage-front-6b8f"},
+		{Kind: "Pod", Namespace: "homepage", Name: "homepage-front-6b8f-x2x9", Health: &argocd.HealthStatus{Status: "Healthy"}},
+	}})
+
+	tree, err := client.ResourceTree(context.Background(), "homepage-front")
+	if err != nil {
+		t.Fatalf("ResourceTree: %v", err)
+	}
+	rollouts := tree.FindNodes("argoproj.io", "Rollout")
+	if len(rollouts) != 1 || rollouts[0].Name != "homepage-front" {
+		t.Errorf("rollouts = %+v", rollouts)
+	}
+	if pods := tree.FindNodes("", "Pod"); len(pods) != 1 || pods[0].Health.Status != "Healthy" {
+		t.Errorf("pods = %+v", pods)
+	}
+}
+
+func TestResourceActionAndPatch(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+	key := argocd.ResourceKey{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout", Namespace: "homepage", Name: "homepage-front"}
+
+	if err := client.RunResourceAction(ctx, "homepage-front", key, "resume"); err != nil {
+		t.Fatalf("RunResourceAction: %v", err)
+	}
+	if err := client.PatchResource(ctx, "homepage-front", key, []byte(`{"spec":{"paused":false}}`), "application/merge-patch+json"); err != nil {
+		t.Fatalf("PatchResource: %v", err)
+	}
+
+	want := []string{
+		"homepage-front/Rollout/homepage/homepage-front: resume",
+		`homepage-front/Rollout/homepage/homepage-front: {"spec":{"paused":false}}`,
+	}
+	if got := srv.ResourceActions(); !slices.Equal(got, want) {
+		t.Errorf("actions = %q, want %q", got, want)
+	}
+
+	srv.SetResource("homepage-front", key, map[string]any{"kind": "Rollout", "spec": map[string]any{"replicas": 3}})
+	manifest, err := client.GetResource(ctx, "homepage-front", key)
+	if err != nil {
+		t.Fatalf("GetResource: %v", err)
+	}
+	if spec, _ := manifest["spec"].(map[string]any); spec["replicas"] != float64(3) {
+		t.Errorf("manifest = %v", manifest)
+	}
+}
@@ -0,0 +1,81 @@
+package argocd
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// Instance ArgoCD 인스턴스 (리전/클러스터 단위)
+type Instance struct {
+	Name string
+	// ArgoCD API 클라이언트
+	Client *Client
+	// Argo Rollouts Dashboard API 주소 및 클라이언트
+	RolloutsURL    string
+	RolloutsClient *http.Client
+}
+
+// InstanceOptions ArgoCD 인스턴스 설정
+type InstanceOptions struct {
+	Options
+	RolloutsURL string
+}
+
+// Registry 이름으로 ArgoCD 인스턴스를 조회한다. 이름이 비어있으면 기본 인스턴스를 반환한다.
+type Registry struct {
+	instances       map[string]*Instance
+	defaultInstance string
+}
+
+func NewRegistry(defaultInstance string) *Registry {
+	return &Registry{
+		instances:       map[string]*Instance{},
+		defaultInstance: defaultInstance,
+	}
+}
+
+// Add ArgoCD 인스턴스 등록
+func (r *Registry) Add(name string, opts InstanceOptions) *Instance {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	instance := &Instance{
+		Name:           name,
+		Client:         New(opts.Options),
+		RolloutsURL:    opts.RolloutsURL,
+		RolloutsClient: &http.Client{Timeout: timeout},
+	}
+	r.instances[name] = instance
+	return instance
+}
+
+// Get ArgoCD 인스턴스 조회
+func (r *Registry) Get(name string) (*Instance, error) {
+	if name == "" {
+		name = r.defaultInstance
+	}
+
+	instance, exist := r.instances[name]
+	if !exist {
+		return nil, fmt.Errorf("argocd: unknown instance %q", name)
+	}
+	return instance, nil
+}
+
+// Names 등록된 인스턴스 이름 목록
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.instances))
+	for name := range r.instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default 기본 인스턴스 이름
+func (r *Registry) Default() string {
+	return r.defaultInstance
+}
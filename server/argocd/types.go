@@ -0,0 +1,207 @@
+package argocd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Application ArgoCD Application (사용하는 필드만 정의)
+type Application struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		Project     string             `json:"project,omitempty"`
+		Source      *ApplicationSource `json:"source,omitempty"`
+		Destination struct {
+			Server    string `json:"server,omitempty"`
+			Name      string `json:"name,omitempty"`
+			Namespace string `json:"namespace,omitempty"`
+		} `json:"destination"`
+	} `json:"spec"`
+	Status ApplicationStatus `json:"status"`
+}
+
+type ApplicationSource struct {
+	RepoURL        string           `json:"repoURL,omitempty"`
+	Path           string           `json:"path,omitempty"`
+	TargetRevision string           `json:"targetRevision,omitempty"`
+	Kustomize      *SourceKustomize `json:"kustomize,omitempty"`
+	Helm           *SourceHelm      `json:"helm,omitempty"`
+}
+
+type SourceKustomize struct {
+	Images []string `json:"images,omitempty"`
+}
+
+type SourceHelm struct {
+	Parameters []HelmParameter `json:"parameters,omitempty"`
+}
+
+type HelmParameter struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	ForceString bool   `json:"forceString,omitempty"`
+}
+
+type ApplicationStatus struct {
+	Summary struct {
+		Images []string `json:"images,omitempty"`
+	} `json:"summary"`
+	Sync struct {
+		Status   string `json:"status"`
+		Revision string `json:"revision,omitempty"`
+	} `json:"sync"`
+	Health         HealthStatus      `json:"health"`
+	OperationState *OperationState   `json:"operationState,omitempty"`
+	History        []RevisionHistory `json:"history,omitempty"`
+}
+
+type HealthStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type OperationState struct {
+	Phase      string      `json:"phase"`
+	Message    string      `json:"message,omitempty"`
+	StartedAt  time.Time   `json:"startedAt"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+	SyncResult *SyncResult `json:"syncResult,omitempty"`
+}
+
+type SyncResult struct {
+	Revision  string           `json:"revision"`
+	Resources []ResourceResult `json:"resources,omitempty"`
+}
+
+type ResourceResult struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Status    string `json:"status,omitempty"`
+	Message   string `json:"message,omitempty"`
+	HookPhase string `json:"hookPhase,omitempty"`
+}
+
+// Failed Sync 실패 리소스 여부
+func (r ResourceResult) Failed() bool {
+	return r.Status == "SyncFailed" || r.HookPhase == "Failed" || r.HookPhase == "Error"
+}
+
+// RevisionHistory Application 배포 이력 (status.history)
+type RevisionHistory struct {
+	ID         int64              `json:"id"`
+	Revision   string             `json:"revision"`
+	DeployedAt time.Time          `json:"deployedAt"`
+	Source     *ApplicationSource `json:"source,omitempty"`
+}
+
+// SyncRequest POST /api/v1/applications/{name}/sync
+type SyncRequest struct {
+	Revision    string         `json:"revision,omitempty"`
+	Prune       bool           `json:"prune"`
+	DryRun      bool           `json:"dryRun"`
+	Resources   []SyncResource `json:"resources,omitempty"`
+	SyncOptions *SyncOptions   `json:"syncOptions,omitempty"`
+}
+
+type SyncResource struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type SyncOptions struct {
+	Items []string `json:"items"`
+}
+
+// RollbackRequest POST /api/v1/applications/{name}/rollback
+type RollbackRequest struct {
+	ID     int64 `json:"id"`
+	Prune  bool  `json:"prune"`
+	DryRun bool  `json:"dryRun"`
+}
+
+// ResourceTree GET /api/v1/applications/{name}/resource-tree
+type ResourceTree struct {
+	Nodes []ResourceNode `json:"nodes"`
+}
+
+type ResourceNode struct {
+	Group      string         `json:"group,omitempty"`
+	Version    string         `json:"version,omitempty"`
+	Kind       string         `json:"kind"`
+	Namespace  string         `json:"namespace,omitempty"`
+	Name       string         `json:"name"`
+	UID        string         `json:"uid,omitempty"`
+	ParentRefs []ResourceRef  `json:"parentRefs,omitempty"`
+	Health     *HealthStatus  `json:"health,omitempty"`
+	Images     []string       `json:"images,omitempty"`
+	Info       []ResourceInfo `json:"info,omitempty"`
+}
+
+type ResourceRef struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// ResourceKey Application 관리 리소스 식별자 (Resource Action/Patch 요청용)
+type ResourceKey struct {
+	Group     string
+	Version   string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+type ResourceInfo struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// FindNodes kind(group 포함)가 일치하는 리소스 목록
+func (t ResourceTree) FindNodes(group, kind string) []ResourceNode {
+	var nodes []ResourceNode
+	for _, n := range t.Nodes {
+		if n.Kind == kind && (group == "" || n.Group == group) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// ManagedResources GET /api/v1/applications/{name}/managed-resources
+type ManagedResources struct {
+	Items []ResourceDiff `json:"items"`
+}
+
+type ResourceDiff struct {
+	Group       string `json:"group,omitempty"`
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name"`
+	LiveState   string `json:"liveState,omitempty"`
+	TargetState string `json:"targetState,omitempty"`
+	Modified    bool   `json:"modified,omitempty"`
+}
+
+// APIError ArgoCD API 오류 응답
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("argocd: %s %s: %d %s", e.Method, e.Path, e.StatusCode, strings.TrimSpace(e.Message))
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry 감사 로그 한 건. JSON Lines 형식으로 파일에 기록된다.
+type Entry struct {
+	Time        time.Time         `json:"time"`
+	Action      string            `json:"action"`
+	Actor       string            `json:"actor"`
+	Application string            `json:"application,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Reason      string            `json:"reason,omitempty"`
+	RequestID   string            `json:"request_id,omitempty"`
+	Detail      map[string]string `json:"detail,omitempty"`
+}
+
+var (
+	mu      sync.Mutex
+	logPath string
+)
+
+// Setting 감사 로그 파일 경로 설정. 경로가 비어있는 경우 애플리케이션 로그에만 기록한다.
+func Setting(path string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("audit.Setting | failed to create audit log directory: %w", err)
+		}
+	}
+
+	logPath = path
+	return nil
+}
+
+// Record 감사 로그 기록
+func Record(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	log.Info().
+		Str("audit_action", e.Action).
+		Str("actor", e.Actor).
+		Str("application", e.Application).
+		Str("environment", e.Environment).
+		Str("reason", e.Reason).
+		Str("request_id", e.RequestID).
+		Msg("audit | action recorded")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if logPath == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("audit.Record | failed to marshal audit entry: %w", err)
+	}
+
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("audit.Record | failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("audit.Record | failed to write audit entry: %w", err)
+	}
+	return nil
+}
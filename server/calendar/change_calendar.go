@@ -0,0 +1,253 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+	"time"
+)
+
+const clockLayout = "15:04"
+
+// 일회성 Freeze 기간 입력 형식. 타임존 정보가 없으면 설정된 Timezone 기준으로 해석한다.
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Recurrence 반복 Freeze 기간
+// type: daily, weekly(weekdays), monthly(days, -1 = 말일), yearly(dates, MM-DD)
+type Recurrence struct {
+	Type      string   `yaml:"type"`
+	Weekdays  []string `yaml:"weekdays"`
+	Days      []int    `yaml:"days"`
+	Dates     []string `yaml:"dates"`
+	StartTime string   `yaml:"start_time"`
+	Duration  string   `yaml:"duration"`
+
+	startClock time.Duration
+	duration   time.Duration
+}
+
+// Window 배포 동결 기간. Environments, Applications가 비어있으면 전체에 적용된다.
+type Window struct {
+	Name         string      `yaml:"name"`
+	Reason       string      `yaml:"reason"`
+	Environments []string    `yaml:"environments"`
+	Applications []string    `yaml:"applications"`
+	Start        string      `yaml:"start"`
+	End          string      `yaml:"end"`
+	Recurrence   *Recurrence `yaml:"recurrence"`
+
+	start time.Time
+	end   time.Time
+}
+
+type Calendar struct {
+	Windows  []*Window `yaml:"windows"`
+	location *time.Location
+}
+
+// Freeze 현재 적용 중인 동결 기간
+type Freeze struct {
+	Window *Window
+	Start  time.Time
+	End    time.Time
+}
+
+// Load Change Calendar 파일 로드. 경로가 비어있으면 동결 기간이 없는 Calendar를 반환한다.
+func Load(path string, location *time.Location) (*Calendar, error) {
+	c := &Calendar{location: location}
+	if path == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("calendar.Load | failed to read change calendar %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("calendar.Load | failed to unmarshal change calendar: %w", err)
+	}
+
+	for _, w := range c.Windows {
+		if err := w.parse(location); err != nil {
+			return nil, fmt.Errorf("calendar.Load | invalid window %q: %w", w.Name, err)
+		}
+	}
+
+	return c, nil
+}
+
+// ActiveFreeze 배포 환경/애플리케이션에 t 시점 적용 중인 동결 기간 반환. 없으면 nil.
+func (c *Calendar) ActiveFreeze(environment, application string, t time.Time) *Freeze {
+	if c == nil {
+		return nil
+	}
+
+	t = t.In(c.location)
+	for _, w := range c.Windows {
+		if !w.appliesTo(environment, application) {
+			continue
+		}
+
+		if w.Recurrence == nil {
+			if !t.Before(w.start) && t.Before(w.end) {
+				return &Freeze{Window: w, Start: w.start, End: w.end}
+			}
+			continue
+		}
+
+		if start, ok := w.Recurrence.occurrence(t); ok {
+			return &Freeze{Window: w, Start: start, End: start.Add(w.Recurrence.duration)}
+		}
+	}
+
+	return nil
+}
+
+func (w *Window) parse(location *time.Location) error {
+	if w.Recurrence != nil {
+		return w.Recurrence.parse()
+	}
+
+	if w.Start == "" || w.End == "" {
+		return errors.New("one-off window requires start and end")
+	}
+
+	var err error
+	if w.start, err = parseDate(w.Start, location); err != nil {
+		return err
+	}
+	if w.end, err = parseDate(w.End, location); err != nil {
+		return err
+	}
+
+	if !w.end.After(w.start) {
+		return errors.New("end must be after start")
+	}
+	return nil
+}
+
+func (w *Window) appliesTo(environment, application string) bool {
+	return matches(w.Environments, environment) && matches(w.Applications, application)
+}
+
+func (r *Recurrence) parse() error {
+	clock, err := time.Parse(clockLayout, r.StartTime)
+	if err != nil {
+		return fmt.Errorf("invalid start_time %q: %w", r.StartTime, err)
+	}
+	r.startClock = time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute
+
+	if r.duration, err = time.ParseDuration(r.Duration); err != nil || r.duration <= 0 {
+		return fmt.Errorf("invalid duration %q", r.Duration)
+	}
+
+	switch r.Type {
+	case "daily":
+	case "weekly":
+		if len(r.Weekdays) == 0 {
+			return errors.New("weekly recurrence requires weekdays")
+		}
+		for _, d := range r.Weekdays {
+			if _, ok := weekdays[strings.ToLower(d)]; !ok {
+				return fmt.Errorf("unknown weekday %q", d)
+			}
+		}
+	case "monthly":
+		if len(r.Days) == 0 {
+			return errors.New("monthly recurrence requires days")
+		}
+	case "yearly":
+		if len(r.Dates) == 0 {
+			return errors.New("yearly recurrence requires dates")
+		}
+		for _, d := range r.Dates {
+			if _, err := time.Parse("01-02", d); err != nil {
+				return fmt.Errorf("invalid date %q: %w", d, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown recurrence type %q", r.Type)
+	}
+	return nil
+}
+
+// occurrence t 시점을 포함하는 반복 기간의 시작 시각 반환
+func (r *Recurrence) occurrence(t time.Time) (time.Time, bool) {
+	// duration이 하루를 넘는 경우를 고려해 이전 날짜의 시작 시각부터 확인
+	lookback := int(r.duration/(24*time.Hour)) + 1
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	for i := 0; i <= lookback; i++ {
+		day := today.AddDate(0, 0, -i)
+		if !r.matchesDay(day) {
+			continue
+		}
+
+		start := day.Add(r.startClock)
+		if !t.Before(start) && t.Before(start.Add(r.duration)) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *Recurrence) matchesDay(day time.Time) bool {
+	switch r.Type {
+	case "daily":
+		return true
+	case "weekly":
+		for _, d := range r.Weekdays {
+			if weekdays[strings.ToLower(d)] == day.Weekday() {
+				return true
+			}
+		}
+	case "monthly":
+		lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		for _, d := range r.Days {
+			if d == day.Day() || (d < 0 && lastDay+d+1 == day.Day()) {
+				return true
+			}
+		}
+	case "yearly":
+		for _, d := range r.Dates {
+			if d == day.Format("01-02") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseDate(value string, location *time.Location) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+func matches(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, v := range list {
+		if v == value || v == "*" {
+			return true
+		}
+	}
+	return false
+}
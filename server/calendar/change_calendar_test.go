@@ -0,0 +1,204 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var seoul = time.FixedZone("KST", 9*60*60)
+
+func loadCalendar(t *testing.T, content string) *Calendar {
+	t.Helper()
+	c, err := Load(writeCalendar(t, content), seoul)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return c
+}
+
+func writeCalendar(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "change_calendar.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func kst(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, seoul)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestOneOffWindow(t *testing.T) {
+	c := loadCalendar(t, `
+windows:
+  - name: year-end
+    reason: 연말 정산 기간
+    environments: [prod]
+    start: "2026-12-24 18:00"
+    end: 2026-12-26T09:00:00+09:00
+`)
+
+	cases := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{name: "before start", at: kst("2026-12-24 17:59"), want: false},
+		{name: "at start", at: kst("2026-12-24 18:00"), want: true},
+		{name: "inside", at: kst("2026-12-25 12:00"), want: true},
+		{name: "at end", at: kst("2026-12-26 09:00"), want: false},
+		// 09:00 UTC = 18:00 KST
+		{name: "start in UTC", at: time.Date(2026, 12, 24, 9, 0, 0, 0, time.UTC), want: true},
+		{name: "before start in UTC", at: time.Date(2026, 12, 24, 8, 59, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			freeze := c.ActiveFreeze("prod", "homepage-front", tc.at)
+			if (freeze != nil) != tc.want {
+				t.Fatalf("freeze = %+v, want active %t", freeze, tc.want)
+			}
+			if freeze != nil && (!freeze.Start.Equal(kst("2026-12-24 18:00")) || !freeze.End.Equal(kst("2026-12-26 09:00"))) {
+				t.Errorf("freeze = %s ~ %s", freeze.Start, freeze.End)
+			}
+		})
+	}
+}
+
+func TestRecurringWindows(t *testing.T) {
+	c := loadCalendar(t, `
+windows:
+  - name: friday-afternoon
+    recurrence: {type: weekly, weekdays: [Fri], start_time: "15:00", duration: 65h}
+  - name: nightly
+    recurrence: {type: daily, start_time: "23:00", duration: 2h}
+  - name: month-end
+    recurrence: {type: monthly, days: [-1], start_time: "00:00", duration: 24h}
+  - name: new-year
+    recurrence: {type: yearly, dates: [01-01], start_time: "00:00", duration: 24h}
+`)
+
+	cases := []struct {
+		name   string
+		at     time.Time
+		window string
+		start  time.Time
+	}{
+		// 2026-03-06 (금)
+		{name: "weekly start", at: kst("2026-03-06 15:00"), window: "friday-afternoon", start: kst("2026-03-06 15:00")},
+		{name: "weekly across days", at: kst("2026-03-09 07:59"), window: "friday-afternoon", start: kst("2026-03-06 15:00")},
+		{name: "weekly end", at: kst("2026-03-09 08:00")},
+		{name: "weekly before start", at: kst("2026-03-06 14:59")},
+		{name: "daily across midnight", at: kst("2026-03-04 00:30"), window: "nightly", start: kst("2026-03-03 23:00")},
+		{name: "daily end", at: kst("2026-03-04 01:00")},
+		{name: "last day of april", at: kst("2026-04-30 12:00"), window: "month-end", start: kst("2026-04-30 00:00")},
+		{name: "not last day", at: kst("2026-03-30 12:00")},
+		{name: "last day of march", at: kst("2026-03-31 12:00"), window: "month-end", start: kst("2026-03-31 00:00")},
+		{name: "yearly", at: kst("2027-01-01 10:00"), window: "new-year", start: kst("2027-01-01 00:00")},
+		// 2026-12-31 16:00 UTC = 2027-01-01 01:00 KST
+		{name: "yearly in UTC", at: time.Date(2026, 12, 31, 16, 0, 0, 0, time.UTC), window: "new-year", start: kst("2027-01-01 00:00")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			freeze := c.ActiveFreeze("prod", "homepage-front", tc.at)
+			if tc.window == "" {
+				if freeze != nil {
+					t.Errorf("freeze = %s, want none", freeze.Window.Name)
+				}
+				return
+			}
+			if freeze == nil || freeze.Window.Name != tc.window {
+				t.Fatalf("freeze = %+v, want %s", freeze, tc.window)
+			}
+			if !freeze.Start.Equal(tc.start) || !freeze.End.Equal(tc.start.Add(freeze.Window.Recurrence.duration)) {
+				t.Errorf("freeze = %s ~ %s, want start %s", freeze.Start, freeze.End, tc.start)
+			}
+		})
+	}
+}
+
+func TestWindowFilters(t *testing.T) {
+	c := loadCalendar(t, `
+windows:
+  - name: prod-front
+    environments: [prod]
+    applications: [homepage-front, cms-front]
+    recurrence: {type: daily, start_time: "00:00", duration: 24h}
+  - name: all-stage
+    environments: [stage]
+    applications: ["*"]
+    recurrence: {type: daily, start_time: "00:00", duration: 24h}
+`)
+	at := kst("2026-03-04 12:00")
+
+	cases := []struct {
+		environment string
+		application string
+		want        string
+	}{
+		{environment: "prod", application: "homepage-front", want: "prod-front"},
+		{environment: "prod", application: "cms-front", want: "prod-front"},
+		{environment: "prod", application: "mydata-api"},
+		{environment: "dev", application: "homepage-front"},
+		{environment: "stage", application: "mydata-api", want: "all-stage"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.environment+"/"+tc.application, func(t *testing.T) {
+			freeze := c.ActiveFreeze(tc.environment, tc.application, at)
+			got := ""
+			if freeze != nil {
+				got = freeze.Window.Name
+			}
+			if got != tc.want {
+				t.Errorf("freeze = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadInvalidWindows(t *testing.T) {
+	cases := []struct {
+		name   string
+		window string
+		want   string
+	}{
+		{name: "missing end", window: `{name: w, start: "2026-01-01"}`, want: "requires start and end"},
+		{name: "end before start", window: `{name: w, start: "2026-01-02", end: "2026-01-01"}`, want: "end must be after start"},
+		{name: "invalid date", window: `{name: w, start: "2026/01/01", end: "2026-01-02"}`, want: "invalid date"},
+		{name: "invalid start_time", window: `{name: w, recurrence: {type: daily, start_time: "25:00", duration: 1h}}`, want: "invalid start_time"},
+		{name: "invalid duration", window: `{name: w, recurrence: {type: daily, start_time: "00:00", duration: 0s}}`, want: "invalid duration"},
+		{name: "unknown weekday", window: `{name: w, recurrence: {type: weekly, weekdays: [fry], start_time: "00:00", duration: 1h}}`, want: "unknown weekday"},
+		{name: "weekly without weekdays", window: `{name: w, recurrence: {type: weekly, start_time: "00:00", duration: 1h}}`, want: "requires weekdays"},
+		{name: "invalid yearly date", window: `{name: w, recurrence: {type: yearly, dates: [13-01], start_time: "00:00", duration: 1h}}`, want: "invalid date"},
+		{name: "unknown type", window: `{name: w, recurrence: {type: hourly, start_time: "00:00", duration: 1h}}`, want: "unknown recurrence type"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(writeCalendar(t, "windows:\n  - "+tc.window+"\n"), seoul)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestEmptyCalendar(t *testing.T) {
+	c, err := Load("", seoul)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if freeze := c.ActiveFreeze("prod", "homepage-front", time.Now()); freeze != nil {
+		t.Errorf("freeze = %+v, want none", freeze)
+	}
+}
@@ -0,0 +1,438 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ApplicationConfig 애플리케이션별 배포 설정
+type ApplicationConfig struct {
+	// 애플리케이션 담당 팀 (Datadog 이벤트 및 메트릭 team 태그)
+	Team string `yaml:"team"`
+	// 동일 애플리케이션/환경 배포 중복 시 처리 방식 (queue, supersede)
+	DeployLock   string        `yaml:"deploy_lock"`
+	QueueTimeout time.Duration `yaml:"queue_timeout"`
+	// Slack Bot으로 승인 요청 메시지를 전송할 채널 (미설정 시 Webhook 사용)
+	SlackChannel string `yaml:"slack_channel"`
+	// 애플리케이션을 배포할 ArgoCD 인스턴스 (미설정 시 기본 인스턴스)
+	ArgoCD  string        `yaml:"argocd"`
+	Sync    SyncConfig    `yaml:"sync"`
+	Rollout RolloutConfig `yaml:"rollout"`
+	// 승인 요청 전 메트릭 기반 Canary 분석
+	Analysis AnalysisConfig `yaml:"analysis"`
+	// Preview 서비스 Health Probe
+	Probe ProbeConfig `yaml:"probe"`
+	// Health Check 실패 시 Kubernetes 진단 정보 수집
+	Diagnostics DiagnosticsConfig `yaml:"diagnostics"`
+}
+
+// DiagnosticsConfig Health Check 실패 시 Preview ReplicaSet의 Pod 상태, 이벤트, 컨테이너 로그 수집 설정
+type DiagnosticsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// 실패한 컨테이너별 로그 라인 수
+	LogLines int64 `yaml:"log_lines"`
+	// 최근 이벤트 수
+	Events int `yaml:"events"`
+	// Rollout을 조회할 수 없는 경우 사용할 Pod label selector ({{.Application}} 치환)
+	Selector string `yaml:"selector"`
+}
+
+// ProbeConfig 서비스 Health Probe 설정
+// url, address는 text/template으로 {{.Application}}, {{.Namespace}}를 치환한다.
+type ProbeConfig struct {
+	// Probe 방식 (http, tcp, grpc)
+	Type string `yaml:"type"`
+	// http 요청 주소
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method"`
+	Headers map[string]string `yaml:"headers"`
+	// 정상으로 판단할 HTTP 상태 코드
+	ExpectedStatus []int `yaml:"expected_status"`
+	// Redirect 응답을 따라갈지 여부 (false인 경우 Redirect 응답 코드 자체를 검사)
+	FollowRedirects bool `yaml:"follow_redirects"`
+	// 응답 본문 정규식 검사
+	BodyRegex string `yaml:"body_regex"`
+	// 응답 JSON 필드 검사 (예: data.status, checks.0.healthy)
+	JSONPath  string `yaml:"json_path"`
+	JSONValue string `yaml:"json_value"`
+	// tcp, grpc 접속 주소 (host:port)
+	Address string `yaml:"address"`
+	// gRPC Health Checking Protocol 서비스 이름 (빈 값은 서버 전체)
+	GRPCService string `yaml:"grpc_service"`
+	// 요청 1회 제한 시간
+	Timeout time.Duration  `yaml:"timeout"`
+	TLS     ProbeTLSConfig `yaml:"tls"`
+
+	// 최대 시도 횟수 및 정상 판단 연속 성공 횟수
+	Attempts         int `yaml:"attempts"`
+	SuccessThreshold int `yaml:"success_threshold"`
+	// 시도 간격. 연속 실패 시 backoff_multiplier 배로 늘어나며 max_interval을 넘지 않는다.
+	Interval          time.Duration `yaml:"interval"`
+	MaxInterval       time.Duration `yaml:"max_interval"`
+	BackoffMultiplier float64       `yaml:"backoff_multiplier"`
+}
+
+// ProbeTLSConfig Probe TLS 설정. http는 https URL인 경우, tcp/grpc는 enabled인 경우 사용한다.
+type ProbeTLSConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+	ServerName         string `yaml:"server_name"`
+	CAFile             string `yaml:"ca_file"`
+}
+
+// AnalysisConfig 메트릭 기반 Canary 분석 설정
+// 승인 요청 메시지에 분석 결과를 표시하고, block 설정 시 실패한 배포는 승인 요청 없이 중단한다.
+type AnalysisConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// 메트릭 조회 대상 (prometheus, datadog)
+	Provider string `yaml:"provider"`
+	Block    bool   `yaml:"block"`
+	// Datadog 메트릭 조회 구간 (Prometheus는 쿼리의 range로 지정)
+	Window  time.Duration   `yaml:"window"`
+	Queries []AnalysisQuery `yaml:"queries"`
+}
+
+// AnalysisQuery Canary 분석 메트릭 쿼리
+// 쿼리는 text/template으로 {{.Application}}, {{.Namespace}}, {{.Environment}}, {{.DockerTag}}를 치환한다.
+type AnalysisQuery struct {
+	Name string `yaml:"name"`
+	// preview(canary) 메트릭 쿼리
+	Query string `yaml:"query"`
+	// 비교 기준 stable 메트릭 쿼리 (max_ratio 사용 시 필수)
+	Baseline string `yaml:"baseline"`
+	// preview 값 허용 범위
+	Max *float64 `yaml:"max"`
+	Min *float64 `yaml:"min"`
+	// stable 대비 허용 비율 (preview <= stable * max_ratio)
+	MaxRatio *float64 `yaml:"max_ratio"`
+}
+
+// RolloutConfig 승인 이후 Rollout 진행 상황 추적 설정
+type RolloutConfig struct {
+	// 진행 상황 조회 간격
+	WatchInterval time.Duration `yaml:"watch_interval"`
+	// Rollout 완료 대기 시간. 초과 시 추적을 중단하고 시간 초과로 알린다.
+	WatchTimeout time.Duration `yaml:"watch_timeout"`
+	Bake         BakeConfig    `yaml:"bake"`
+}
+
+// BakeConfig 승인 이후 안정화(bake) 기간 설정
+// 활성화 시 승인부터 Rollout 완료 후 duration까지 Rollout/ArgoCD 상태 및 Health Probe를 확인하고,
+// 문제가 감지되면 action에 따라 자동 조치한다.
+type BakeConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Rollout 완료 이후 관찰 기간
+	Duration time.Duration `yaml:"duration"`
+	Interval time.Duration `yaml:"interval"`
+	// 서비스 Health Probe 실행 여부
+	Probe bool `yaml:"probe"`
+	// 연속 실패 허용 횟수. Rollout이 Degraded 상태가 된 경우 즉시 조치한다.
+	FailureThreshold int `yaml:"failure_threshold"`
+	// 자동 조치 방식 (rollback, alert)
+	// rollback: 완료 전 Rollout은 중단(abort), 완료 후에는 ArgoCD 이전 배포 이력으로 롤백
+	Action string `yaml:"action"`
+}
+
+// SyncConfig ArgoCD Sync 설정
+type SyncConfig struct {
+	// 배포 요청 DockerTag 반영 방식 (kustomize, helm). 미설정 시 Git에 정의된 이미지로 배포된다.
+	ImageOverride string `yaml:"image_override"`
+	// kustomize images에 설정할 이미지 이름 (예: 123456789012.dkr.ecr.ap-northeast-2.amazonaws.com/homepage-front)
+	Image string `yaml:"image"`
+	// DockerTag를 설정할 Helm parameter (기본: image.tag)
+	HelmParameter string         `yaml:"helm_parameter"`
+	Revision      string         `yaml:"revision"`
+	Prune         bool           `yaml:"prune"`
+	DryRun        bool           `yaml:"dry_run"`
+	Resources     []SyncResource `yaml:"resources"`
+	SyncOptions   []string       `yaml:"sync_options"`
+	// Sync Operation 종료 및 Application Health 안정화 대기 시간
+	WaitTimeout time.Duration `yaml:"wait_timeout"`
+	// Sync 이후 Live 이미지 태그 확인 대기 시간
+	VerifyTimeout time.Duration `yaml:"verify_timeout"`
+}
+
+// SyncResource 선택 Sync 대상 리소스
+type SyncResource struct {
+	Group     string `yaml:"group" json:"group,omitempty"`
+	Kind      string `yaml:"kind" json:"kind"`
+	Name      string `yaml:"name" json:"name"`
+	Namespace string `yaml:"namespace" json:"namespace,omitempty"`
+}
+
+// Applications 애플리케이션 설정 파일
+// defaults에 정의된 값을 기본으로 applications.<name>에 정의된 값을 덮어쓴다.
+type Applications struct {
+	defaults     ApplicationConfig
+	applications map[string]ApplicationConfig
+}
+
+type applicationsFile struct {
+	Defaults     yaml.Node            `yaml:"defaults"`
+	Applications map[string]yaml.Node `yaml:"applications"`
+}
+
+var defaultApplicationConfig = ApplicationConfig{
+	DeployLock:   "queue",
+	QueueTimeout: 30 * time.Minute,
+	Sync: SyncConfig{
+		WaitTimeout:   5 * time.Minute,
+		VerifyTimeout: 3 * time.Minute,
+	},
+	Rollout: RolloutConfig{
+		WatchInterval: 10 * time.Second,
+		WatchTimeout:  30 * time.Minute,
+		Bake: BakeConfig{
+			Duration:         10 * time.Minute,
+			Interval:         30 * time.Second,
+			Probe:            true,
+			FailureThreshold: 3,
+			Action:           "rollback",
+		},
+	},
+	Analysis: AnalysisConfig{
+		Window: 5 * time.Minute,
+	},
+	Probe: ProbeConfig{
+		Type:            "http",
+		URL:             "http://{{.Application}}-preview.{{.Namespace}}.svc.cluster.local/healthz/healthcheck",
+		Method:          "GET",
+		ExpectedStatus:  []int{200, 204},
+		FollowRedirects: true,
+		Timeout:         5 * time.Second,
+
+		Attempts:          25,
+		SuccessThreshold:  2,
+		Interval:          5 * time.Second,
+		MaxInterval:       15 * time.Second,
+		BackoffMultiplier: 1.5,
+	},
+	Diagnostics: DiagnosticsConfig{
+		Enabled:  true,
+		LogLines: 50,
+		Events:   20,
+		Selector: "app.kubernetes.io/instance={{.Application}}",
+	},
+}
+
+// baseApplicationConfig 기본 애플리케이션 설정
+// HEALTH_CHECK_LIMITS(횟수), HEALTH_CHECK_INTERVAL(초)이 설정된 경우 probe.attempts, probe.interval 기본값으로 사용한다.
+// 기본 success_threshold, max_interval을 넘지 않도록 함께 조정한다.
+func baseApplicationConfig() ApplicationConfig {
+	cfg := defaultApplicationConfig
+	if limits, err := strconv.Atoi(os.Getenv("HEALTH_CHECK_LIMITS")); err == nil && limits > 0 {
+		cfg.Probe.Attempts = limits
+		cfg.Probe.SuccessThreshold = min(cfg.Probe.SuccessThreshold, limits)
+	}
+	if interval, err := strconv.Atoi(os.Getenv("HEALTH_CHECK_INTERVAL")); err == nil && interval > 0 {
+		cfg.Probe.Interval = time.Duration(interval) * time.Second
+		cfg.Probe.MaxInterval = max(cfg.Probe.MaxInterval, cfg.Probe.Interval)
+	}
+	return cfg
+}
+
+// LoadApplications 애플리케이션 설정 파일 로드. 경로가 비어있으면 기본 설정만 사용한다.
+// 설정 파일이 없더라도 기본 설정(환경 변수 반영)을 검증한다.
+func LoadApplications(path string) (*Applications, error) {
+	var f applicationsFile
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("LoadApplications | failed to read applications config %s: %w", path, err)
+		}
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("LoadApplications | failed to unmarshal applications config: %w", err)
+		}
+	}
+
+	a := &Applications{
+		defaults:     baseApplicationConfig(),
+		applications: map[string]ApplicationConfig{},
+	}
+	if err := decodeApplicationConfig(&f.Defaults, &a.defaults); err != nil {
+		return nil, fmt.Errorf("LoadApplications | invalid defaults: %w", err)
+	}
+
+	for name, node := range f.Applications {
+		// defaults 노드를 다시 decode해 애플리케이션 간 slice/map 공유를 방지
+		cfg := baseApplicationConfig()
+		if err := decodeApplicationConfig(&f.Defaults, &cfg); err != nil {
+			return nil, fmt.Errorf("LoadApplications | invalid defaults: %w", err)
+		}
+		if err := decodeApplicationConfig(&node, &cfg); err != nil {
+			return nil, fmt.Errorf("LoadApplications | invalid application %s: %w", name, err)
+		}
+		a.applications[name] = cfg
+	}
+
+	return a, nil
+}
+
+// ArgoCDInstances defaults 및 애플리케이션에 지정된 ArgoCD 인스턴스 이름 목록 (애플리케이션 이름 기준)
+func (a *Applications) ArgoCDInstances() map[string]string {
+	instances := map[string]string{"defaults": a.defaults.ArgoCD}
+	for name, cfg := range a.applications {
+		instances[name] = cfg.ArgoCD
+	}
+	return instances
+}
+
+// AnalysisProviders Canary 분석이 활성화된 defaults 및 애플리케이션의 메트릭 조회 대상 (애플리케이션 이름 기준)
+func (a *Applications) AnalysisProviders() map[string]string {
+	providers := map[string]string{}
+	if a.defaults.Analysis.Enabled {
+		providers["defaults"] = a.defaults.Analysis.Provider
+	}
+	for name, cfg := range a.applications {
+		if cfg.Analysis.Enabled {
+			providers[name] = cfg.Analysis.Provider
+		}
+	}
+	return providers
+}
+
+// Get 애플리케이션 설정 조회. 등록되지 않은 애플리케이션은 defaults를 반환한다.
+func (a *Applications) Get(name string) ApplicationConfig {
+	if cfg, exist := a.applications[name]; exist {
+		return cfg
+	}
+	return a.defaults
+}
+
+// decodeApplicationConfig node 값을 cfg에 덮어쓴 뒤 검증. node가 비어있으면 cfg만 검증한다.
+func decodeApplicationConfig(node *yaml.Node, cfg *ApplicationConfig) error {
+	if node.Kind != 0 {
+		if err := node.Decode(cfg); err != nil {
+			return err
+		}
+	}
+
+	switch cfg.DeployLock {
+	case "queue", "supersede":
+	default:
+		return fmt.Errorf("unknown deploy_lock %q (queue, supersede)", cfg.DeployLock)
+	}
+
+	if cfg.QueueTimeout <= 0 {
+		return fmt.Errorf("queue_timeout must be positive: %s", cfg.QueueTimeout)
+	}
+
+	switch cfg.Sync.ImageOverride {
+	case "", "helm":
+	case "kustomize":
+		if cfg.Sync.Image == "" {
+			return errors.New("sync.image is required for kustomize image override")
+		}
+	default:
+		return fmt.Errorf("unknown sync.image_override %q (kustomize, helm)", cfg.Sync.ImageOverride)
+	}
+
+	if cfg.Sync.WaitTimeout <= 0 {
+		return fmt.Errorf("sync.wait_timeout must be positive: %s", cfg.Sync.WaitTimeout)
+	}
+
+	if cfg.Sync.VerifyTimeout <= 0 {
+		return fmt.Errorf("sync.verify_timeout must be positive: %s", cfg.Sync.VerifyTimeout)
+	}
+
+	if cfg.Rollout.WatchInterval <= 0 {
+		return fmt.Errorf("rollout.watch_interval must be positive: %s", cfg.Rollout.WatchInterval)
+	}
+
+	if cfg.Rollout.WatchTimeout <= 0 {
+		return fmt.Errorf("rollout.watch_timeout must be positive: %s", cfg.Rollout.WatchTimeout)
+	}
+
+	if bake := cfg.Rollout.Bake; bake.Enabled {
+		if bake.Duration <= 0 || bake.Interval <= 0 {
+			return fmt.Errorf("rollout.bake.duration and rollout.bake.interval must be positive: %s, %s", bake.Duration, bake.Interval)
+		}
+		if bake.FailureThreshold <= 0 {
+			return fmt.Errorf("rollout.bake.failure_threshold must be positive: %d", bake.FailureThreshold)
+		}
+		switch bake.Action {
+		case "rollback", "alert":
+		default:
+			return fmt.Errorf("unknown rollout.bake.action %q (rollback, alert)", bake.Action)
+		}
+	}
+
+	if err := validateProbe(cfg.Probe); err != nil {
+		return err
+	}
+
+	if diagnostics := cfg.Diagnostics; diagnostics.Enabled && (diagnostics.LogLines < 0 || diagnostics.Events < 0) {
+		return fmt.Errorf("diagnostics.log_lines and diagnostics.events must not be negative: %d, %d", diagnostics.LogLines, diagnostics.Events)
+	}
+
+	if analysis := cfg.Analysis; analysis.Enabled {
+		switch analysis.Provider {
+		case "prometheus", "datadog":
+		default:
+			return fmt.Errorf("unknown analysis.provider %q (prometheus, datadog)", analysis.Provider)
+		}
+		if analysis.Window <= 0 {
+			return fmt.Errorf("analysis.window must be positive: %s", analysis.Window)
+		}
+		if len(analysis.Queries) == 0 {
+			return errors.New("analysis.queries is required when analysis is enabled")
+		}
+		for _, q := range analysis.Queries {
+			if q.Name == "" || q.Query == "" {
+				return errors.New("analysis.queries requires name and query")
+			}
+			if q.Max == nil && q.Min == nil && q.MaxRatio == nil {
+				return fmt.Errorf("analysis query %s has no threshold (max, min, max_ratio)", q.Name)
+			}
+			if q.MaxRatio != nil && q.Baseline == "" {
+				return fmt.Errorf("analysis query %s requires baseline for max_ratio", q.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateProbe(p ProbeConfig) error {
+	switch p.Type {
+	case "http":
+		if p.URL == "" {
+			return errors.New("probe.url is required for http probe")
+		}
+		if len(p.ExpectedStatus) == 0 {
+			return errors.New("probe.expected_status is required for http probe")
+		}
+		if p.BodyRegex != "" {
+			if _, err := regexp.Compile(p.BodyRegex); err != nil {
+				return fmt.Errorf("invalid probe.body_regex: %w", err)
+			}
+		}
+	case "tcp", "grpc":
+		if p.Address == "" {
+			return fmt.Errorf("probe.address is required for %s probe", p.Type)
+		}
+	default:
+		return fmt.Errorf("unknown probe.type %q (http, tcp, grpc)", p.Type)
+	}
+
+	if p.Timeout <= 0 {
+		return fmt.Errorf("probe.timeout must be positive: %s", p.Timeout)
+	}
+
+	if p.Attempts <= 0 || p.SuccessThreshold <= 0 || p.SuccessThreshold > p.Attempts {
+		return fmt.Errorf("probe.success_threshold must be between 1 and probe.attempts: %d, %d", p.SuccessThreshold, p.Attempts)
+	}
+
+	if p.Interval <= 0 || p.MaxInterval < p.Interval {
+		return fmt.Errorf("probe.interval must be positive and not exceed probe.max_interval: %s, %s", p.Interval, p.MaxInterval)
+	}
+
+	if p.BackoffMultiplier < 1 {
+		return fmt.Errorf("probe.backoff_multiplier must be at least 1: %g", p.BackoffMultiplier)
+	}
+	return nil
+}
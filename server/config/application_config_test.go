@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeApplications(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "applications.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRepositoryApplications(t *testing.T) {
+	// 저장소의 애플리케이션 설정 파일에서 Web Front 애플리케이션의 / 경로 Health Probe를 지정한다.
+	a, err := LoadApplications("../applications.yaml")
+	if err != nil {
+		t.Fatalf("LoadApplications: %v", err)
+	}
+
+	const frontProbeURL = "http://{{.Application}}-preview.{{.Namespace}}.svc.cluster.local/"
+	for _, name := range []string{"homepage-front", "cms-front", "mydata-front", "pms-front", "mydata-cms-front"} {
+		if got := a.Get(name).Probe.URL; got != frontProbeURL {
+			t.Errorf("%s probe url = %q, want %q", name, got, frontProbeURL)
+		}
+		// probe.url 이외의 항목은 기본값을 유지한다.
+		if p := a.Get(name).Probe; p.Attempts != defaultApplicationConfig.Probe.Attempts || !p.FollowRedirects {
+			t.Errorf("%s probe = %+v, want defaults", name, p)
+		}
+	}
+	if got := a.Get("cms-api").Probe.URL; got != defaultApplicationConfig.Probe.URL {
+		t.Errorf("cms-api probe url = %q, want default", got)
+	}
+}
+
+func TestProbeURLOverride(t *testing.T) {
+	path := writeApplications(t, `
+defaults:
+  probe:
+    url: http://{{.Application}}.{{.Namespace}}.svc.cluster.local/ping
+applications:
+  cms-front:
+    deploy_lock: supersede
+  pms-front:
+    probe:
+      url: http://{{.Application}}-preview.{{.Namespace}}.svc.cluster.local/status
+`)
+
+	a, err := LoadApplications(path)
+	if err != nil {
+		t.Fatalf("LoadApplications: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		suffix string
+	}{
+		// 설정 파일에 없는 애플리케이션도 defaults를 사용한다.
+		{name: "homepage-front", suffix: "/ping"},
+		{name: "cms-front", suffix: "/ping"},
+		{name: "pms-front", suffix: "/status"},
+	}
+	for _, tc := range cases {
+		if got := a.Get(tc.name).Probe.URL; !strings.HasSuffix(got, tc.suffix) {
+			t.Errorf("%s probe url = %q, want suffix %q", tc.name, got, tc.suffix)
+		}
+	}
+	if got := a.Get("cms-front").DeployLock; got != "supersede" {
+		t.Errorf("cms-front deploy_lock = %q, want supersede", got)
+	}
+}
+
+func TestWithoutApplicationsFile(t *testing.T) {
+	a, err := LoadApplications("")
+	if err != nil {
+		t.Fatalf("LoadApplications: %v", err)
+	}
+	// 설정 파일이 없으면 모든 애플리케이션이 기본 Health Probe를 사용한다.
+	if got := a.Get("homepage-front").Probe.URL; got != defaultApplicationConfig.Probe.URL {
+		t.Errorf("homepage-front probe url = %q, want default", got)
+	}
+}
+
+func TestHealthCheckEnvironment(t *testing.T) {
+	t.Setenv("HEALTH_CHECK_LIMITS", "1")
+	t.Setenv("HEALTH_CHECK_INTERVAL", "30")
+
+	a, err := LoadApplications("")
+	if err != nil {
+		t.Fatalf("LoadApplications: %v", err)
+	}
+	p := a.Get("cms-api").Probe
+	if p.Attempts != 1 || p.SuccessThreshold != 1 {
+		t.Errorf("attempts, success_threshold = %d, %d, want 1, 1", p.Attempts, p.SuccessThreshold)
+	}
+	if p.Interval != 30*time.Second || p.MaxInterval != 30*time.Second {
+		t.Errorf("interval, max_interval = %s, %s, want 30s, 30s", p.Interval, p.MaxInterval)
+	}
+}
+
+func TestDefaultsValidated(t *testing.T) {
+	original := defaultApplicationConfig
+	t.Cleanup(func() { defaultApplicationConfig = original })
+	defaultApplicationConfig.Probe.MaxInterval = time.Second
+
+	if _, err := LoadApplications(""); err == nil || !strings.Contains(err.Error(), "invalid defaults") {
+		t.Errorf("err = %v, want invalid defaults error", err)
+	}
+}
+
+func TestInvalidApplication(t *testing.T) {
+	path := writeApplications(t, `
+applications:
+  cms-api:
+    probe:
+      interval: 30s
+`)
+	_, err := LoadApplications(path)
+	if err == nil || !strings.Contains(err.Error(), "invalid application cms-api") {
+		t.Errorf("err = %v, want invalid application error", err)
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"time"
+)
+
+// DefaultArgoCDInstance ARGOCD_INSTANCES_PATH 미설정 시 사용하는 단일 인스턴스 이름
+const DefaultArgoCDInstance = "default"
+
+// ArgoCDInstance ArgoCD 인스턴스 설정
+// 인증 정보는 Secrets Manager(/secret/devops)의 Key 이름으로 지정한다.
+type ArgoCDInstance struct {
+	URL            string        `yaml:"url"`
+	RolloutsURL    string        `yaml:"rollouts_url"`
+	UsernameSecret string        `yaml:"username_secret"`
+	PasswordSecret string        `yaml:"password_secret"`
+	APITokenSecret string        `yaml:"api_token_secret"`
+	Timeout        time.Duration `yaml:"timeout"`
+	// Argo Rollouts 제어 방식 (dashboard, kubernetes). 기본: dashboard
+	RolloutsBackend string `yaml:"rollouts_backend"`
+	// kubernetes 방식에서 사용할 kubeconfig 경로 및 context (미설정 시 in-cluster 인증 정보 사용)
+	Kubeconfig  string `yaml:"kubeconfig"`
+	KubeContext string `yaml:"kube_context"`
+
+	// Secrets Manager에서 로드한 인증 정보
+	Username string `yaml:"-"`
+	Password string `yaml:"-"`
+	APIToken string `yaml:"-"`
+}
+
+type argoCDInstancesFile struct {
+	Default   string                    `yaml:"default"`
+	Instances map[string]ArgoCDInstance `yaml:"instances"`
+}
+
+// loadArgoCDInstances ArgoCD 인스턴스 설정 파일 로드 및 Secret Key로 인증 정보 설정
+func loadArgoCDInstances(path string, secrets map[string]string, defaultTimeout time.Duration) (map[string]ArgoCDInstance, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("loadArgoCDInstances | failed to read argocd instances config %s: %w", path, err)
+	}
+
+	var f argoCDInstancesFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, "", fmt.Errorf("loadArgoCDInstances | failed to unmarshal argocd instances config: %w", err)
+	}
+
+	if len(f.Instances) == 0 {
+		return nil, "", errors.New("loadArgoCDInstances | no argocd instances defined")
+	}
+
+	if f.Default == "" && len(f.Instances) == 1 {
+		for name := range f.Instances {
+			f.Default = name
+		}
+	}
+	if _, exist := f.Instances[f.Default]; !exist {
+		return nil, "", fmt.Errorf("loadArgoCDInstances | default instance %q is not defined", f.Default)
+	}
+
+	for name, instance := range f.Instances {
+		if instance.URL == "" {
+			return nil, "", fmt.Errorf("loadArgoCDInstances | url is required for instance %s", name)
+		}
+		if err := validateRolloutsBackend(instance.RolloutsBackend); err != nil {
+			return nil, "", fmt.Errorf("loadArgoCDInstances | instance %s: %w", name, err)
+		}
+		if instance.Timeout <= 0 {
+			instance.Timeout = defaultTimeout
+		}
+
+		instance.Username = secrets[instance.UsernameSecret]
+		instance.Password = secrets[instance.PasswordSecret]
+		instance.APIToken = secrets[instance.APITokenSecret]
+		if instance.APIToken == "" && (instance.Username == "" || instance.Password == "") {
+			return nil, "", fmt.Errorf("loadArgoCDInstances | instance %s has no api token or username/password in secrets", name)
+		}
+
+		f.Instances[name] = instance
+	}
+
+	return f.Instances, f.Default, nil
+}
+
+func validateRolloutsBackend(backend string) error {
+	switch backend {
+	case "", "dashboard", "kubernetes":
+		return nil
+	}
+	return fmt.Errorf("unknown rollouts_backend %q (dashboard, kubernetes)", backend)
+}
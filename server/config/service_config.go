@@ -10,13 +10,114 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/rs/zerolog/log"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
 type Config struct {
-	ServerPort string
-	Timezone   string
+	ServerPort         string
+	Timezone           string
+	Admins             []string
+	ChangeCalendarPath string
+	AuditLogPath       string
+	PolicyDir          string
+	ApplicationsPath   string
+	ArgoCDURL          string
+	ArgoRolloutsURL    string
+	RolloutsBackend    string
+	// ArgoCD/Argo Rollouts API 요청 타임아웃
+	ArgoCDTimeout time.Duration
+	// 리전/클러스터별 ArgoCD 인스턴스 (애플리케이션 설정의 argocd 항목으로 지정)
+	ArgoCDInstances       map[string]ArgoCDInstance
+	DefaultArgoCDInstance string
+	// Canary 분석 메트릭 조회 대상
+	PrometheusURL string
+	Datadog       DatadogConfig
+	// 배포 파이프라인 워커 수 및 대기열 크기
+	PipelineWorkers   int
+	PipelineQueueSize int
+	// 배포 기록 상태 파일 경로 (서버 재기동 시 진행 중인 배포 재개)
+	DeploymentStatePath string
+	// Graceful shutdown 대기 시간
+	ShutdownTimeout time.Duration
+	// /metrics 전용 리스너 포트 (미설정 시 서버 포트에서 제공) 및 인증 토큰
+	MetricsPort  string
+	MetricsToken string
+	// 배포 동결 기간 긴급 배포(break-glass) 인증 토큰 (미설정 시 break-glass 비활성화)
+	BreakGlassToken string
+	// OpenTelemetry trace export 설정
+	Tracing TracingConfig
+	// DORA 지표 배포 기록 및 주간 보고 설정
+	DORA DORAConfig
+	// 배포 lifecycle 이벤트 외부 webhook 전송 설정
+	Webhook WebhookConfig
+}
+
+// WebhookConfig 외부 webhook 전송 설정 (WEBHOOK_* 환경 변수)
+type WebhookConfig struct {
+	// 구독 및 전송 기록 상태 파일 경로 (미설정 시 메모리에만 보관, 재기동 시 구독 삭제)
+	StatePath string
+	// 동시 전송 수 및 재시도 포함 최대 시도 횟수 (0이면 기본값 4, 5)
+	Workers     int
+	MaxAttempts int
+	// 전송 요청 타임아웃 (기본값 10s)
+	Timeout time.Duration
+	// 루프백, 링크 로컬, 사설 및 클러스터 내부 주소 구독 허용 (기본값 false)
+	AllowPrivateNetworks bool
+}
+
+// DORAConfig DORA 지표 설정 (DORA_* 환경 변수). 기간(7d, 720h 등) 및 보고 시각은 핸들러 초기화 시 검증한다.
+type DORAConfig struct {
+	// 종료된 배포 기록 파일 경로 (미설정 시 메모리에만 보관) 및 보관 기간 (기본값 90d)
+	HistoryPath string
+	Retention   string
+	// Prometheus gauge 집계 기간 (기본값 30d)
+	Window string
+	// 주간 보고 Slack 채널 (Slack Bot) 및 보고 시각 (기본값 mon 09:00)
+	DigestChannel  string
+	DigestSchedule string
+}
+
+// TracingConfig OpenTelemetry trace export 설정 (OTEL_* 환경 변수)
+// endpoint, header, sampler는 OpenTelemetry SDK가 OTEL_EXPORTER_OTLP_*, OTEL_TRACES_SAMPLER 환경 변수에서 직접 읽는다.
+type TracingConfig struct {
+	ServiceName string
+	// otlp, none (기본값 none)
+	Exporter string
+	// grpc, http/protobuf (기본값 http/protobuf)
+	Protocol string
+}
+
+// LoggingConfig 로그 출력 설정 (LOG_* 환경 변수). Secrets Manager 조회 전 로그에도 적용되도록 별도로 읽는다.
+type LoggingConfig struct {
+	// trace, debug, info, warn, error (기본값 debug)
+	Level string
+	// json, console (기본값 json)
+	Format string
+	// debug, info 로그 샘플링 비율 (N건 중 1건 기록)
+	SampleRate int
+	// 로그 파일 경로 및 로테이션 설정 (크기 MB, 보관 파일 수, 보관 기간 일)
+	File           string
+	FileMaxSize    int
+	FileMaxBackups int
+	FileMaxAge     int
+	// 기본 마스킹 대상 외 추가 필드 이름
+	RedactKeys []string
+}
+
+// DatadogConfig Datadog Metrics API 인증 정보 (Secrets Manager) 및 배포 이벤트 전송 설정
+type DatadogConfig struct {
+	APIKey string
+	AppKey string
+	Site   string
+	// 배포 lifecycle 이벤트(Datadog Events) 및 배포 메트릭 전송 여부
+	Events bool
+	// 이벤트 및 메트릭 전송 주기
+	FlushInterval time.Duration
+	// 모든 이벤트 및 메트릭에 추가할 태그 (key:value, 콤마 구분)
+	Tags []string
 }
 
 type Secrets struct {
@@ -24,6 +125,14 @@ type Secrets struct {
 	ArgoAdminUserName     string `json:"ARGO_ADMIN_USERNAME"`
 	ProdArgoAdminPassword string `json:"PROD_ARGO_ADMIN_PASSWORD"`
 	DevArgoAdminPassword  string `json:"DEV_ARGO_ADMIN_PASSWORD"`
+	ProdArgoAPIToken      string `json:"PROD_ARGO_API_TOKEN"`
+	DevArgoAPIToken       string `json:"DEV_ARGO_API_TOKEN"`
+	SlackBotToken         string `json:"SLACK_BOT_TOKEN"`
+	DatadogAPIKey         string `json:"DATADOG_API_KEY"`
+	DatadogAppKey         string `json:"DATADOG_APP_KEY"`
+	DatadogSite           string `json:"DATADOG_SITE"`
+	MetricsToken          string `json:"METRICS_TOKEN"`
+	BreakGlassToken       string `json:"BREAK_GLASS_TOKEN"`
 }
 
 type SecretLoader struct {
@@ -42,6 +151,20 @@ var (
 	defaultPort          = "8080"
 )
 
+const (
+	defaultArgoCDURL       = "http://argocd-server.argocd.svc.cluster.local"
+	defaultArgoRolloutsURL = "http://argocd-argo-rollouts-dashboard.argocd.svc.cluster.local"
+	defaultArgoCDTimeout   = 30 * time.Second
+
+	defaultPipelineWorkers   = 4
+	defaultPipelineQueueSize = 100
+	defaultShutdownTimeout   = 25 * time.Second
+
+	defaultLogFileMaxSize    = 100
+	defaultLogFileMaxBackups = 5
+	defaultLogFileMaxAge     = 30
+)
+
 func getSecretLoader(region, secretName string) (*SecretLoader, error) {
 	log.Debug().Msg("=====> Getting Secret Loader")
 	var initErr error
@@ -63,8 +186,15 @@ func getSecretLoader(region, secretName string) (*SecretLoader, error) {
 			client:     secretsmanager.NewFromConfig(cfg),
 			secrets:    &Secrets{},
 			config: &Config{
-				ServerPort: defaultPort,
-				Timezone:   "Asia/Seoul",
+				ServerPort:      defaultPort,
+				Timezone:        "Asia/Seoul",
+				ArgoCDURL:       defaultArgoCDURL,
+				ArgoRolloutsURL: defaultArgoRolloutsURL,
+				ArgoCDTimeout:   defaultArgoCDTimeout,
+
+				PipelineWorkers:   defaultPipelineWorkers,
+				PipelineQueueSize: defaultPipelineQueueSize,
+				ShutdownTimeout:   defaultShutdownTimeout,
 			},
 			loaded: false,
 		}
@@ -104,6 +234,18 @@ func (sl *SecretLoader) loadSecrets() error {
 		return fmt.Errorf("LoadSecrets | failed to unmarshal secret value: %w", err)
 	}
 
+	// ArgoCD 인스턴스 인증 정보는 설정 파일에 지정된 Key 이름으로 조회
+	var rawSecrets map[string]any
+	if err := json.Unmarshal([]byte(*output.SecretString), &rawSecrets); err != nil {
+		return fmt.Errorf("LoadSecrets | failed to unmarshal secret value: %w", err)
+	}
+	secretValues := make(map[string]string, len(rawSecrets))
+	for k, v := range rawSecrets {
+		if value, ok := v.(string); ok {
+			secretValues[k] = value
+		}
+	}
+
 	if port := os.Getenv("SERVER_PORT"); port != "" {
 		sl.config.ServerPort = port
 	}
@@ -113,6 +255,185 @@ func (sl *SecretLoader) loadSecrets() error {
 		sl.config.Timezone = tz
 	}
 
+	// 관리자 목록 (GitHub 계정 혹은 Slack 사용자명, 콤마 구분)
+	if admins := os.Getenv("RELAY_ADMINS"); admins != "" {
+		for _, admin := range strings.Split(admins, ",") {
+			if admin = strings.TrimSpace(admin); admin != "" {
+				sl.config.Admins = append(sl.config.Admins, admin)
+			}
+		}
+	}
+
+	if path := os.Getenv("CHANGE_CALENDAR_PATH"); path != "" {
+		sl.config.ChangeCalendarPath = path
+	}
+
+	if path := os.Getenv("AUDIT_LOG_PATH"); path != "" {
+		sl.config.AuditLogPath = path
+	}
+
+	if dir := os.Getenv("POLICY_DIR"); dir != "" {
+		sl.config.PolicyDir = dir
+	}
+
+	if path := os.Getenv("APPLICATIONS_CONFIG_PATH"); path != "" {
+		sl.config.ApplicationsPath = path
+	}
+
+	if url := os.Getenv("ARGOCD_URL"); url != "" {
+		sl.config.ArgoCDURL = url
+	}
+
+	if url := os.Getenv("ARGO_ROLLOUTS_URL"); url != "" {
+		sl.config.ArgoRolloutsURL = url
+	}
+
+	if backend := os.Getenv("ROLLOUTS_BACKEND"); backend != "" {
+		if err := validateRolloutsBackend(backend); err != nil {
+			return fmt.Errorf("LoadSecrets | invalid ROLLOUTS_BACKEND: %w", err)
+		}
+		sl.config.RolloutsBackend = backend
+	}
+
+	if timeout := os.Getenv("ARGOCD_TIMEOUT"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("LoadSecrets | invalid ARGOCD_TIMEOUT %q", timeout)
+		}
+		sl.config.ArgoCDTimeout = d
+	}
+
+	if workers := os.Getenv("PIPELINE_WORKERS"); workers != "" {
+		n, err := strconv.Atoi(workers)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("LoadSecrets | invalid PIPELINE_WORKERS %q", workers)
+		}
+		sl.config.PipelineWorkers = n
+	}
+
+	if size := os.Getenv("PIPELINE_QUEUE_SIZE"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("LoadSecrets | invalid PIPELINE_QUEUE_SIZE %q", size)
+		}
+		sl.config.PipelineQueueSize = n
+	}
+
+	if path := os.Getenv("DEPLOYMENT_STATE_PATH"); path != "" {
+		sl.config.DeploymentStatePath = path
+	}
+
+	if timeout := os.Getenv("SHUTDOWN_TIMEOUT"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("LoadSecrets | invalid SHUTDOWN_TIMEOUT %q", timeout)
+		}
+		sl.config.ShutdownTimeout = d
+	}
+
+	if port := os.Getenv("METRICS_PORT"); port != "" {
+		sl.config.MetricsPort = port
+	}
+	sl.config.MetricsToken = sl.secrets.MetricsToken
+	sl.config.BreakGlassToken = sl.secrets.BreakGlassToken
+
+	sl.config.Tracing = TracingConfig{
+		ServiceName: "devops-relay-server",
+		Exporter:    os.Getenv("OTEL_TRACES_EXPORTER"),
+		Protocol:    os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"),
+	}
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		sl.config.Tracing.ServiceName = name
+	}
+
+	sl.config.Webhook = WebhookConfig{
+		StatePath: os.Getenv("WEBHOOK_STATE_PATH"),
+		Timeout:   10 * time.Second,
+	}
+	for env, target := range map[string]*int{
+		"WEBHOOK_WORKERS":      &sl.config.Webhook.Workers,
+		"WEBHOOK_MAX_ATTEMPTS": &sl.config.Webhook.MaxAttempts,
+	} {
+		if value := os.Getenv(env); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("LoadSecrets | invalid %s %q", env, value)
+			}
+			*target = n
+		}
+	}
+	if timeout := os.Getenv("WEBHOOK_TIMEOUT"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("LoadSecrets | invalid WEBHOOK_TIMEOUT %q", timeout)
+		}
+		sl.config.Webhook.Timeout = d
+	}
+	if allow := os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS"); allow != "" {
+		b, err := strconv.ParseBool(allow)
+		if err != nil {
+			return fmt.Errorf("LoadSecrets | invalid WEBHOOK_ALLOW_PRIVATE_NETWORKS %q", allow)
+		}
+		sl.config.Webhook.AllowPrivateNetworks = b
+	}
+
+	sl.config.DORA = DORAConfig{
+		HistoryPath:    os.Getenv("DORA_HISTORY_PATH"),
+		Retention:      os.Getenv("DORA_RETENTION"),
+		Window:         os.Getenv("DORA_WINDOW"),
+		DigestChannel:  os.Getenv("DORA_DIGEST_CHANNEL"),
+		DigestSchedule: os.Getenv("DORA_DIGEST_SCHEDULE"),
+	}
+
+	if url := os.Getenv("PROMETHEUS_URL"); url != "" {
+		sl.config.PrometheusURL = url
+	}
+
+	sl.config.Datadog = DatadogConfig{
+		APIKey: sl.secrets.DatadogAPIKey,
+		AppKey: sl.secrets.DatadogAppKey,
+		Site:   sl.secrets.DatadogSite,
+	}
+
+	if enabled := os.Getenv("DATADOG_EVENTS_ENABLED"); enabled != "" {
+		events, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return fmt.Errorf("LoadSecrets | invalid DATADOG_EVENTS_ENABLED %q", enabled)
+		}
+		if events && sl.config.Datadog.APIKey == "" {
+			return errors.New("LoadSecrets | DATADOG_EVENTS_ENABLED requires DATADOG_API_KEY")
+		}
+		sl.config.Datadog.Events = events
+	}
+
+	if interval := os.Getenv("DATADOG_FLUSH_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("LoadSecrets | invalid DATADOG_FLUSH_INTERVAL %q", interval)
+		}
+		sl.config.Datadog.FlushInterval = d
+	}
+
+	if tags := os.Getenv("DATADOG_TAGS"); tags != "" {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				sl.config.Datadog.Tags = append(sl.config.Datadog.Tags, tag)
+			}
+		}
+	}
+
+	if path := os.Getenv("ARGOCD_INSTANCES_PATH"); path != "" {
+		instances, defaultInstance, err := loadArgoCDInstances(path, secretValues, sl.config.ArgoCDTimeout)
+		if err != nil {
+			return err
+		}
+		sl.config.ArgoCDInstances = instances
+		sl.config.DefaultArgoCDInstance = defaultInstance
+	} else {
+		sl.config.ArgoCDInstances = map[string]ArgoCDInstance{DefaultArgoCDInstance: sl.singleArgoCDInstance()}
+		sl.config.DefaultArgoCDInstance = DefaultArgoCDInstance
+	}
+
 	sl.loaded = true
 	return nil
 }
@@ -126,20 +447,9 @@ func (sl *SecretLoader) setEnvironmentVariables() error {
 		return errors.New("SetEnvironmentVariables | secret does not exist")
 	}
 
-	serviceEnv := os.Getenv("APP_ENV")
-	var adminPassword string
-
-	switch serviceEnv {
-	case "dev":
-		adminPassword = sl.secrets.DevArgoAdminPassword
-	case "prod":
-		adminPassword = sl.secrets.ProdArgoAdminPassword
-	}
-
 	envVars := map[string]string{
-		"REQUEST_TOKEN":       sl.secrets.RequestToken,
-		"ARGO_ADMIN_USERNAME": sl.secrets.ArgoAdminUserName,
-		"ARGO_ADMIN_PASSWORD": adminPassword,
+		"REQUEST_TOKEN":   sl.secrets.RequestToken,
+		"SLACK_BOT_TOKEN": sl.secrets.SlackBotToken,
 	}
 
 	for k, v := range envVars {
@@ -150,6 +460,28 @@ func (sl *SecretLoader) setEnvironmentVariables() error {
 	return nil
 }
 
+// singleArgoCDInstance ARGOCD_INSTANCES_PATH 미설정 시 APP_ENV에 따라 인증 정보를 선택하는 단일 인스턴스
+func (sl *SecretLoader) singleArgoCDInstance() ArgoCDInstance {
+	instance := ArgoCDInstance{
+		URL:             sl.config.ArgoCDURL,
+		RolloutsURL:     sl.config.ArgoRolloutsURL,
+		RolloutsBackend: sl.config.RolloutsBackend,
+		Kubeconfig:      os.Getenv("KUBECONFIG"),
+		Timeout:         sl.config.ArgoCDTimeout,
+		Username:        sl.secrets.ArgoAdminUserName,
+	}
+
+	switch os.Getenv("APP_ENV") {
+	case "dev":
+		instance.Password = sl.secrets.DevArgoAdminPassword
+		instance.APIToken = sl.secrets.DevArgoAPIToken
+	case "prod":
+		instance.Password = sl.secrets.ProdArgoAdminPassword
+		instance.APIToken = sl.secrets.ProdArgoAPIToken
+	}
+	return instance
+}
+
 func (sl *SecretLoader) setTimezone() error {
 	log.Debug().Msg("=====> Setting Timezone")
 	sl.mu.RLock()
@@ -180,6 +512,52 @@ func (sl *SecretLoader) GetConfig() (*Config, error) {
 	return sl.config, nil
 }
 
+// IsAdmin GitHub 계정 혹은 Slack 사용자가 관리자 목록에 포함되어 있는지 확인
+func (c *Config) IsAdmin(name string) bool {
+	for _, admin := range c.Admins {
+		if strings.EqualFold(admin, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Logging LOG_* 환경 변수에서 로그 출력 설정 조회
+func Logging() (LoggingConfig, error) {
+	cfg := LoggingConfig{
+		Level:          strings.ToLower(os.Getenv("LOG_LEVEL")),
+		Format:         strings.ToLower(os.Getenv("LOG_FORMAT")),
+		File:           os.Getenv("LOG_FILE"),
+		FileMaxSize:    defaultLogFileMaxSize,
+		FileMaxBackups: defaultLogFileMaxBackups,
+		FileMaxAge:     defaultLogFileMaxAge,
+	}
+
+	for env, target := range map[string]*int{
+		"LOG_SAMPLE_RATE":      &cfg.SampleRate,
+		"LOG_FILE_MAX_SIZE":    &cfg.FileMaxSize,
+		"LOG_FILE_MAX_BACKUPS": &cfg.FileMaxBackups,
+		"LOG_FILE_MAX_AGE":     &cfg.FileMaxAge,
+	} {
+		if value := os.Getenv(env); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return cfg, fmt.Errorf("Logging | invalid %s %q", env, value)
+			}
+			*target = n
+		}
+	}
+
+	if keys := os.Getenv("LOG_REDACT_KEYS"); keys != "" {
+		for _, key := range strings.Split(keys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				cfg.RedactKeys = append(cfg.RedactKeys, key)
+			}
+		}
+	}
+	return cfg, nil
+}
+
 func Setting() *Config {
 	log.Debug().Msg("=====> Initialize Secret Loader")
 	// Instance 생성
@@ -0,0 +1,241 @@
+// Package datadog Datadog Events(배포 마커) 및 커스텀 메트릭 비동기 전송
+// 이벤트와 메트릭은 대기열(batch.Sender)에 저장한 뒤 주기적으로 묶어서 전송하므로 Datadog 장애가 배포 파이프라인을 지연시키지 않는다.
+package datadog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/batch"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultFlushInterval = 10 * time.Second
+
+// Options Datadog 전송 설정
+type Options struct {
+	// baseURL 미설정 시 site(예: datadoghq.com) 기준 API 주소를 사용한다.
+	BaseURL string
+	Site    string
+	APIKey  string
+	// 모든 이벤트 및 메트릭에 추가할 태그
+	Tags []string
+	// 이벤트 host (미설정 시 Datadog 기본값)
+	Host string
+	// 대기열 크기, 한 번에 전송할 최대 항목 수 및 전송 주기
+	QueueSize     int
+	BatchSize     int
+	FlushInterval time.Duration
+	HTTPClient    *http.Client
+}
+
+// AlertType 이벤트 종류 (Datadog Events alert_type)
+type AlertType string
+
+const (
+	AlertInfo    AlertType = "info"
+	AlertSuccess AlertType = "success"
+	AlertWarning AlertType = "warning"
+	AlertError   AlertType = "error"
+)
+
+// Event Datadog Event. 같은 AggregationKey의 이벤트는 Event Explorer에서 하나로 묶인다.
+type Event struct {
+	Title          string
+	Text           string
+	AlertType      AlertType
+	AggregationKey string
+	Tags           []string
+	Time           time.Time
+}
+
+// MetricType 메트릭 종류 (Datadog Metrics API v2 type)
+type MetricType int
+
+const (
+	Count MetricType = 1
+	Gauge MetricType = 3
+)
+
+// Metric Datadog 커스텀 메트릭 데이터 포인트
+type Metric struct {
+	Name  string
+	Type  MetricType
+	Value float64
+	Tags  []string
+	Time  time.Time
+}
+
+// Client Datadog Events/Metrics API 비동기 전송 클라이언트
+type Client struct {
+	baseURL    string
+	apiKey     string
+	tags       []string
+	host       string
+	httpClient *http.Client
+
+	events *batch.Sender[Event]
+	series *batch.Sender[Metric]
+}
+
+// New 전송 루프를 시작한 Client 생성. 종료 시 Close로 남은 항목을 전송한다.
+func New(opts Options) *Client {
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		site := opts.Site
+		if site == "" {
+			site = "datadoghq.com"
+		}
+		baseURL = fmt.Sprintf("https://api.%s", site)
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushInterval
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     opts.APIKey,
+		tags:       opts.Tags,
+		host:       opts.Host,
+		httpClient: opts.HTTPClient,
+	}
+	// Events API는 요청당 이벤트 1건만 지원
+	c.events = batch.New("datadog events", c.sendEvents, batch.Options{
+		QueueSize:     opts.QueueSize,
+		BatchSize:     1,
+		FlushInterval: opts.FlushInterval,
+	})
+	c.series = batch.New("datadog metrics", c.sendSeries, batch.Options{
+		QueueSize:     opts.QueueSize,
+		BatchSize:     opts.BatchSize,
+		FlushInterval: opts.FlushInterval,
+	})
+	return c
+}
+
+// Event 이벤트 전송 대기열 등록. 대기열이 가득 찬 경우 버린다.
+func (c *Client) Event(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	c.events.Add(e)
+}
+
+// Metric 메트릭 전송 대기열 등록. 대기열이 가득 찬 경우 버린다.
+func (c *Client) Metric(m Metric) {
+	if m.Time.IsZero() {
+		m.Time = time.Now()
+	}
+	c.series.Add(m)
+}
+
+// Close 대기열에 남은 항목을 전송한 뒤 종료. ctx가 만료되면 전송을 중단한다.
+func (c *Client) Close(ctx context.Context) error {
+	return errors.Join(c.events.Close(ctx), c.series.Close(ctx))
+}
+
+func (c *Client) sendEvents(ctx context.Context, events []Event) error {
+	for _, e := range events {
+		if err := c.post(ctx, "/api/v1/events", c.eventPayload(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) sendSeries(ctx context.Context, metrics []Metric) error {
+	return c.post(ctx, "/api/v2/series", c.seriesPayload(metrics))
+}
+
+type eventPayload struct {
+	Title          string   `json:"title"`
+	Text           string   `json:"text"`
+	DateHappened   int64    `json:"date_happened"`
+	AlertType      string   `json:"alert_type,omitempty"`
+	AggregationKey string   `json:"aggregation_key,omitempty"`
+	SourceTypeName string   `json:"source_type_name"`
+	Host           string   `json:"host,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+}
+
+func (c *Client) eventPayload(e Event) eventPayload {
+	return eventPayload{
+		Title:          e.Title,
+		Text:           e.Text,
+		DateHappened:   e.Time.Unix(),
+		AlertType:      string(e.AlertType),
+		AggregationKey: e.AggregationKey,
+		SourceTypeName: "devops-relay",
+		Host:           c.host,
+		Tags:           append(append([]string{}, c.tags...), e.Tags...),
+	}
+}
+
+type seriesPayload struct {
+	Series []seriesItem `json:"series"`
+}
+
+type seriesItem struct {
+	Metric string        `json:"metric"`
+	Type   MetricType    `json:"type"`
+	Points []seriesPoint `json:"points"`
+	Tags   []string      `json:"tags,omitempty"`
+}
+
+type seriesPoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+func (c *Client) seriesPayload(metrics []Metric) seriesPayload {
+	p := seriesPayload{Series: make([]seriesItem, 0, len(metrics))}
+	for _, m := range metrics {
+		p.Series = append(p.Series, seriesItem{
+			Metric: m.Name,
+			Type:   m.Type,
+			Points: []seriesPoint{{Timestamp: m.Time.Unix(), Value: m.Value}},
+			Tags:   append(append([]string{}, c.tags...), m.Tags...),
+		})
+	}
+	return p
+}
+
+// post 연결 오류, 429, 5xx 외의 실패는 batch.Permanent로 반환해 재시도하지 않는다.
+func (c *Client) post(ctx context.Context, path string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return batch.Permanent(fmt.Errorf("datadog: failed to marshal payload: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return batch.Permanent(fmt.Errorf("datadog: failed to create request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("datadog: POST %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := fmt.Errorf("datadog: POST %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+			return batch.Permanent(err)
+		}
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,169 @@
+package datadog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiver Datadog API 요청을 기록하고 statuses 순서대로 응답하는 테스트 서버
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []request
+}
+
+type request struct {
+	path   string
+	apiKey string
+	body   []byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, request{path: req.URL.Path, apiKey: req.Header.Get("DD-API-KEY"), body: body})
+		status := http.StatusAccepted
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]request(nil), r.requests...)
+}
+
+func newClient(r *receiver) *Client {
+	return New(Options{BaseURL: r.URL + "/", APIKey: "dd-key", Tags: []string{"team:devops"}, Host: "relay-0", FlushInterval: time.Hour})
+}
+
+func closeClient(t *testing.T, c *Client) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := c.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestEventPayload(t *testing.T) {
+	r := newReceiver(t)
+	c := newClient(r)
+	happened := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	c.Event(Event{
+		Title:          "homepage-front v1.2.3 deployed to prod",
+		Text:           "approved by dev-lead",
+		AlertType:      AlertSuccess,
+		AggregationKey: "dep-1",
+		Tags:           []string{"env:prod"},
+		Time:           happened,
+	})
+	c.Event(Event{Title: "cms-front started"})
+	closeClient(t, c)
+
+	reqs := r.received()
+	// Events API는 요청당 이벤트 1건만 전송한다.
+	if len(reqs) != 2 {
+		t.Fatalf("requests = %d, want 2", len(reqs))
+	}
+	if reqs[0].path != "/api/v1/events" || reqs[0].apiKey != "dd-key" {
+		t.Errorf("request = %s (DD-API-KEY %q)", reqs[0].path, reqs[0].apiKey)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(reqs[0].body, &got); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	want := map[string]any{
+		"title":            "homepage-front v1.2.3 deployed to prod",
+		"text":             "approved by dev-lead",
+		"date_happened":    float64(happened.Unix()),
+		"alert_type":       "success",
+		"aggregation_key":  "dep-1",
+		"source_type_name": "devops-relay",
+		"host":             "relay-0",
+		"tags":             []any{"team:devops", "env:prod"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("payload = %v, want %v", got, want)
+	}
+
+	// 시간 미설정 이벤트는 등록 시각을 사용한다.
+	var second eventPayload
+	if err := json.Unmarshal(reqs[1].body, &second); err != nil || second.DateHappened == 0 {
+		t.Errorf("date_happened = %d (%v), want set on enqueue", second.DateHappened, err)
+	}
+}
+
+func TestSeriesPayload(t *testing.T) {
+	r := newReceiver(t)
+	c := newClient(r)
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	c.Metric(Metric{Name: "devops_relay.deployment.count", Type: Count, Value: 1, Tags: []string{"env:prod"}, Time: now})
+	c.Metric(Metric{Name: "devops_relay.deployment.lead_time", Type: Gauge, Value: 42.5, Time: now})
+	closeClient(t, c)
+
+	reqs := r.received()
+	// 메트릭은 한 요청으로 묶어서 전송한다.
+	if len(reqs) != 1 || reqs[0].path != "/api/v2/series" || reqs[0].apiKey != "dd-key" {
+		t.Fatalf("requests = %+v, want 1 series request", reqs)
+	}
+
+	var got seriesPayload
+	if err := json.Unmarshal(reqs[0].body, &got); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	want := seriesPayload{Series: []seriesItem{
+		{Metric: "devops_relay.deployment.count", Type: Count, Points: []seriesPoint{{Timestamp: now.Unix(), Value: 1}}, Tags: []string{"team:devops", "env:prod"}},
+		{Metric: "devops_relay.deployment.lead_time", Type: Gauge, Points: []seriesPoint{{Timestamp: now.Unix(), Value: 42.5}}, Tags: []string{"team:devops"}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("payload = %+v, want %+v", got, want)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	cases := []struct {
+		name     string
+		statuses []int
+		// 전송 시도 횟수
+		want int
+	}{
+		{name: "too many requests", statuses: []int{http.StatusTooManyRequests, http.StatusAccepted}, want: 2},
+		{name: "server error", statuses: []int{http.StatusServiceUnavailable, http.StatusAccepted}, want: 2},
+		// 잘못된 요청, 인증 실패는 재시도하지 않는다.
+		{name: "bad request", statuses: []int{http.StatusBadRequest, http.StatusAccepted}, want: 1},
+		{name: "forbidden", statuses: []int{http.StatusForbidden, http.StatusAccepted}, want: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r := newReceiver(t, tc.statuses...)
+			c := newClient(r)
+			c.Metric(Metric{Name: "devops_relay.deployment.count", Type: Count, Value: 1})
+			closeClient(t, c)
+
+			if got := len(r.received()); got != tc.want {
+				t.Errorf("attempts = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package deployment
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/analysis"
+	"github.com/antonio-kim-1994/devops-relay/server/diagnostics"
+	"github.com/antonio-kim-1994/devops-relay/server/probe"
+	"regexp"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusQueued           Status = "queued"
+	StatusRunning          Status = "running"
+	StatusAwaitingApproval Status = "awaiting_approval"
+	StatusApproved         Status = "approved"
+	StatusRejected         Status = "rejected"
+	StatusSucceeded        Status = "succeeded"
+	StatusFailed           Status = "failed"
+	StatusSuperseded       Status = "superseded"
+)
+
+// Stage 배포 파이프라인 진행 단계. 서버 재기동 시 마지막 단계부터 재개한다.
+type Stage string
+
+const (
+	StageSync         Stage = "sync"
+	StageSyncWait     Stage = "sync_wait"
+	StageVerify       Stage = "verify"
+	StageHealthCheck  Stage = "health_check"
+	StageAnalysis     Stage = "analysis"
+	StageApproval     Stage = "approval"
+	StagePromote      Stage = "promote"
+	StageRolloutWatch Stage = "rollout_watch"
+)
+
+type Action string
+
+const (
+	ActionDeploy   Action = "deploy"
+	ActionRollback Action = "rollback"
+)
+
+// Deployment 애플리케이션/환경 단위 배포 기록
+type Deployment struct {
+	ID            string    `json:"id"`
+	Action        Action    `json:"action"`
+	Application   string    `json:"application"`
+	Namespace     string    `json:"namespace"`
+	Environment   string    `json:"environment"`
+	Org           string    `json:"org"`
+	Repo          string    `json:"repo"`
+	DockerTag     string    `json:"docker_tag"`
+	Operator      string    `json:"operator"`
+	CommitMessage string    `json:"commit_message"`
+	Status        Status    `json:"status"`
+	Stage         Stage     `json:"stage,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	SupersededBy  string    `json:"superseded_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// 파이프라인 시작(배포 잠금 획득) 및 종료 시각
+	StartedAt  time.Time `json:"started_at,omitzero"`
+	FinishedAt time.Time `json:"finished_at,omitzero"`
+
+	// 애플리케이션 담당 팀 (애플리케이션 설정 team 혹은 GitHub 배포 요청)
+	Team string `json:"team,omitempty"`
+	// 배포 대상 커밋 시각 (변경 리드 타임 기준)
+	CommittedAt time.Time `json:"committed_at,omitzero"`
+
+	// 배포 대상 ArgoCD 인스턴스
+	ArgoCD string `json:"argocd"`
+
+	// 배포를 시작한 요청 ID (X-Request-ID). 승인, 롤백 등 이후 요청의 로그를 원본 요청과 연결한다.
+	RequestID string `json:"request_id,omitempty"`
+	// 배포를 시작한 요청의 W3C traceparent (워커 및 재기동 이후 파이프라인을 같은 trace로 연결)
+	TraceParent string `json:"traceparent,omitempty"`
+
+	// 롤백 대상 배포 ID 및 ArgoCD 배포 이력 (rollback)
+	RollbackOf string `json:"rollback_of,omitempty"`
+	HistoryID  int64  `json:"history_id,omitempty"`
+	Revision   string `json:"revision,omitempty"`
+
+	// ArgoCD Sync 요청 시각 (Sync Operation 확인 재개 시 이전 Operation 구분)
+	SyncStartedAt time.Time `json:"sync_started_at,omitzero"`
+	// Sync 요청 직전 ArgoCD 최신 배포 이력 ID (배포 기록 기준 롤백 대상)
+	PreviousHistoryID *int64 `json:"previous_history_id,omitempty"`
+
+	// 마지막 Preview 서비스 Health Check 결과 (시도 기록 포함)
+	HealthCheck *probe.Result `json:"health_check,omitempty"`
+	// Health Check 실패 시 수집한 Kubernetes 진단 정보 (Pod 상태, 이벤트, 컨테이너 로그)
+	Diagnostics *diagnostics.Report `json:"diagnostics,omitempty"`
+	// Canary 메트릭 분석 결과
+	Analysis *analysis.Result `json:"analysis,omitempty"`
+
+	// 운영 배포 승인 요청 시각
+	ApprovalRequestedAt time.Time `json:"approval_requested_at,omitzero"`
+	// 운영 배포 승인자 및 승인 시각
+	Approver   string    `json:"approver,omitempty"`
+	ApprovedAt time.Time `json:"approved_at,omitzero"`
+
+	// Slack Bot으로 전송한 승인 요청 메시지 (chat.update 용)
+	SlackChannel   string `json:"slack_channel,omitempty"`
+	SlackTimestamp string `json:"slack_timestamp,omitempty"`
+	// 승인 버튼 응답 response_url 사용 횟수 (Gateway 승인 접수 메시지 포함, 최대 5회)
+	ResponseURLUses int `json:"response_url_uses,omitempty"`
+
+	// 파이프라인 재개에 사용하는 원본 요청 (GitHub 배포 요청, Slack 승인 응답).
+	// Slack Webhook 주소 등이 포함되므로 API 응답에는 포함하지 않고 상태 파일에만 저장한다.
+	Request  json.RawMessage `json:"-"`
+	Approval json.RawMessage `json:"-"`
+}
+
+// Gateway가 발급한 배포 ID는 Slack 버튼 값 및 API 경로에 포함되므로 허용 문자와 길이를 제한한다.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// NewID 배포 ID 생성
+func NewID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("dep-%d", time.Now().UnixNano())
+	}
+	return fmt.Sprintf("dep-%s-%s", time.Now().Format("20060102"), hex.EncodeToString(b))
+}
+
+// ValidID Gateway가 발급한 배포 ID 사용 가능 여부 (영문, 숫자, '.', '_', '-' 최대 64자)
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// Key 배포 잠금 단위 (애플리케이션/환경)
+func (d Deployment) Key() string {
+	return fmt.Sprintf("%s/%s", d.Application, d.Environment)
+}
+
+// Finished 배포 종료 여부
+func (d Deployment) Finished() bool {
+	switch d.Status {
+	case StatusApproved, StatusRejected, StatusSucceeded, StatusFailed, StatusSuperseded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package deployment
+
+import "time"
+
+// EventType 배포 lifecycle 이벤트
+type EventType string
+
+const (
+	// EventQueued 배포 요청 등록 (배포 잠금 대기)
+	EventQueued EventType = "queued"
+	// EventStarted 배포 잠금 획득 후 파이프라인 시작 (재기동 이후 재개는 제외)
+	EventStarted EventType = "started"
+	// EventStageChanged 파이프라인 진행 단계 변경 (Sync, Health Check, Canary 분석, Promote 등. Deployment.Stage)
+	EventStageChanged EventType = "stage_changed"
+	// EventHealthCheckFailed Preview 서비스 Health Check 실패
+	EventHealthCheckFailed EventType = "health_check_failed"
+	// EventApprovalRequested 운영 배포 승인 요청 (Rollout 단계별 추가 승인 포함)
+	EventApprovalRequested EventType = "approval_requested"
+	// EventApproved 운영 배포 승인
+	EventApproved EventType = "approved"
+	// EventFinished 배포 종료. 결과(succeeded, failed, approved, rejected, superseded)는 Deployment.Status로 구분한다.
+	EventFinished EventType = "finished"
+)
+
+// Event 배포 lifecycle 이벤트. Deployment는 이벤트 발생 시점의 배포 기록 복사본이다.
+type Event struct {
+	Type       EventType
+	Time       time.Time
+	Deployment Deployment
+}
+
+// Subscribe 배포 lifecycle 이벤트 수신 함수 등록
+// 이벤트는 배포 기록 잠금 상태에서 발생 순서대로 전달되므로 fn은 대기하지 않아야 하며 Registry를 호출해서는 안 된다.
+func (r *Registry) Subscribe(fn func(Event)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// emit 배포 lifecycle 이벤트 전달 (r.mu 잠금 상태에서 호출)
+func (r *Registry) emit(t EventType, d *Deployment) {
+	if len(r.subscribers) == 0 {
+		return
+	}
+	e := Event{Type: t, Time: time.Now(), Deployment: *d}
+	for _, fn := range r.subscribers {
+		fn(e)
+	}
+}
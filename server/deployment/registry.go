@@ -0,0 +1,587 @@
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/metrics"
+	"github.com/antonio-kim-1994/devops-relay/server/probe"
+	"github.com/rs/zerolog/log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type LockPolicy string
+
+const (
+	// LockQueue 진행 중인 배포가 종료될 때까지 대기
+	LockQueue LockPolicy = "queue"
+	// LockSupersede 진행 중인 배포를 중단하고 신규 배포로 대체
+	LockSupersede LockPolicy = "supersede"
+)
+
+const maxHistory = 500
+
+var (
+	ErrSuperseded   = errors.New("deployment superseded by a newer request")
+	ErrQueueTimeout = errors.New("timed out waiting for the running deployment")
+	ErrNotFound     = errors.New("deployment not found")
+	ErrExists       = errors.New("deployment already registered")
+)
+
+// Registry 배포 기록 및 애플리케이션/환경 단위 배포 잠금 관리
+type Registry struct {
+	mu          sync.Mutex
+	deployments map[string]*Deployment
+	order       []string
+	active      map[string]*lockEntry
+	// 배포 잠금 대기 중인 배포 (애플리케이션/환경별 요청 순)
+	queued map[string][]*waiter
+	// 배포 기록 상태 파일 경로 (미설정 시 메모리에만 보관)
+	statePath string
+	// 배포 lifecycle 이벤트 수신 함수
+	subscribers []func(Event)
+}
+
+type lockEntry struct {
+	id     string
+	cancel context.CancelCauseFunc
+	// 승인 대기 중(실행 중인 작업 없음) 여부
+	idle bool
+}
+
+// waiter 배포 잠금 대기. 잠금 해제 시 요청 순서대로 잠금 context를 전달받는다.
+type waiter struct {
+	id      string
+	created time.Time
+	granted chan context.Context
+	cancel  context.CancelCauseFunc
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		deployments: map[string]*Deployment{},
+		active:      map[string]*lockEntry{},
+		queued:      map[string][]*waiter{},
+	}
+}
+
+// Begin 배포 기록 등록 및 배포 잠금 획득
+// 같은 애플리케이션/환경의 배포가 진행 중이면 policy에 따라 대기(queue)하거나 이전 배포를 대체(supersede)한다.
+// 반환된 context는 배포가 대체되면 ErrSuperseded로 취소되며, 대체된 이전 배포 목록을 함께 반환한다.
+func (r *Registry) Begin(d Deployment, policy LockPolicy, queueTimeout time.Duration) (context.Context, []Deployment, error) {
+	d, err := r.Register(d)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.Acquire(context.Background(), d.ID, policy, queueTimeout)
+}
+
+// Register 대기(queued) 상태의 배포 기록 등록. 배포 잠금은 Acquire로 획득한다.
+// 같은 ID의 배포가 이미 등록된 경우(GitHub 배포 요청 재시도) 기존 배포 기록과 ErrExists를 반환한다.
+func (r *Registry) Register(d Deployment) (Deployment, error) {
+	now := time.Now()
+	d.Status = StatusQueued
+	d.CreatedAt = now
+	d.UpdatedAt = now
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, exist := r.deployments[d.ID]; exist {
+		return *existing, fmt.Errorf("%w: %s", ErrExists, d.ID)
+	}
+	r.deployments[d.ID] = &d
+	r.order = append(r.order, d.ID)
+	r.emit(EventQueued, &d)
+	r.save()
+	return d, nil
+}
+
+// Acquire 등록된 배포의 배포 잠금 획득. 대기 중인 배포는 요청(등록) 순서대로 잠금을 획득한다.
+// supersede 정책은 먼저 요청된 진행 중/대기 중 배포를 대체하며, 대기 중 더 최근 요청으로 대체되면 ErrSuperseded를 반환한다.
+// 대기 시간 초과 시 배포를 실패로 종료하고, 대기 중 ctx가 취소(서버 종료)된 경우 배포는 대기 상태로 유지된다.
+func (r *Registry) Acquire(ctx context.Context, id string, policy LockPolicy, queueTimeout time.Duration) (context.Context, []Deployment, error) {
+	r.mu.Lock()
+	d, exist := r.deployments[id]
+	if !exist {
+		r.mu.Unlock()
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if d.Status == StatusSuperseded {
+		r.mu.Unlock()
+		return nil, nil, ErrSuperseded
+	}
+	key := d.Key()
+
+	var superseded []Deployment
+	if policy == LockSupersede {
+		var err error
+		if superseded, err = r.supersede(key, d); err != nil {
+			r.save()
+			r.mu.Unlock()
+			return nil, nil, err
+		}
+	}
+
+	if _, locked := r.active[key]; !locked && len(r.queued[key]) == 0 {
+		lockCtx := r.lock(key, d)
+		r.save()
+		r.mu.Unlock()
+		return lockCtx, superseded, nil
+	}
+
+	waitCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	w := &waiter{id: id, created: d.CreatedAt, granted: make(chan context.Context, 1), cancel: cancel}
+	r.enqueue(key, w)
+	if len(superseded) > 0 {
+		r.save()
+	}
+	r.mu.Unlock()
+
+	timeout := time.NewTimer(queueTimeout)
+	defer timeout.Stop()
+
+	var err error
+	select {
+	case lockCtx := <-w.granted:
+		return lockCtx, superseded, nil
+	case <-timeout.C:
+	case <-waitCtx.Done():
+		err = context.Cause(waitCtx)
+	}
+
+	r.mu.Lock()
+	select {
+	case lockCtx := <-w.granted:
+		// 대기 종료와 동시에 잠금을 전달받은 경우
+		if err == nil {
+			r.mu.Unlock()
+			return lockCtx, superseded, nil
+		}
+		// 서버 종료 시에는 대기 상태로 되돌리고 다음 배포에 잠금을 넘긴다.
+		if d.Status != StatusSuperseded {
+			r.setStatus(id, StatusQueued)
+		}
+		if entry, locked := r.active[key]; locked && entry.id == id {
+			r.release(key, entry)
+		}
+		r.save()
+	default:
+		r.dequeue(key, w)
+	}
+	if err == nil && d.Status == StatusSuperseded {
+		err = ErrSuperseded
+	}
+	holder := ""
+	if entry, locked := r.active[key]; locked {
+		holder = entry.id
+	}
+	r.mu.Unlock()
+
+	if err != nil {
+		return nil, superseded, err
+	}
+	r.Finish(id, StatusFailed)
+	return nil, superseded, fmt.Errorf("%w: %s", ErrQueueTimeout, holder)
+}
+
+// AwaitApproval 승인 대기 상태로 변경. 잠금은 승인/반려 처리 시까지 유지된다.
+func (r *Registry) AwaitApproval(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, exist := r.deployments[id]
+	if !exist || d.Status != StatusRunning {
+		return
+	}
+
+	r.setStatus(id, StatusAwaitingApproval)
+	d.ApprovalRequestedAt = d.UpdatedAt
+	if entry, locked := r.active[d.Key()]; locked && entry.id == id {
+		entry.idle = true
+	}
+	r.emit(EventApprovalRequested, d)
+	r.save()
+}
+
+// Resume 승인 대기 중인 배포의 승인/반려 처리 시작
+func (r *Registry) Resume(id string) (context.Context, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, exist := r.deployments[id]
+	if !exist {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	switch d.Status {
+	case StatusSuperseded:
+		return nil, ErrSuperseded
+	case StatusAwaitingApproval:
+	default:
+		return nil, fmt.Errorf("deployment %s is not awaiting approval: %s", id, d.Status)
+	}
+
+	entry, locked := r.active[d.Key()]
+	if !locked || entry.id != id {
+		return nil, fmt.Errorf("deployment %s does not hold the deploy lock", id)
+	}
+
+	if !d.ApprovalRequestedAt.IsZero() {
+		metrics.ObserveApprovalWait(d.Application, d.Environment, time.Since(d.ApprovalRequestedAt))
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	entry.cancel = cancel
+	entry.idle = false
+	r.setStatus(id, StatusRunning)
+	r.save()
+	return ctx, nil
+}
+
+// Finish 배포 종료 및 잠금 해제. 대체된 배포의 상태는 변경하지 않는다.
+func (r *Registry) Finish(id string, status Status) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, exist := r.deployments[id]
+	if !exist {
+		return
+	}
+
+	// 배포 결과는 최초 종료 시 한 번만 집계한다. (대체된 배포는 대체 시점에 집계)
+	finished := d.Finished()
+	if !finished {
+		metrics.ObserveDeployment(d.Application, d.Environment, string(d.Action), string(status))
+	}
+	if d.Status != StatusSuperseded {
+		r.setStatus(id, status)
+	}
+	if !finished {
+		d.FinishedAt = d.UpdatedAt
+		r.emit(EventFinished, d)
+	}
+
+	if entry, locked := r.active[d.Key()]; locked && entry.id == id {
+		r.release(d.Key(), entry)
+	}
+	r.prune()
+	r.save()
+}
+
+// Approve 운영 배포 승인 정보 저장. update로 승인 처리 재개에 필요한 정보를 함께 저장한다.
+func (r *Registry) Approve(id, approver string, update func(d *Deployment)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, exist := r.deployments[id]
+	if !exist {
+		return
+	}
+
+	status := d.Status
+	update(d)
+	d.Status = status
+	d.Approver = approver
+	d.ApprovedAt = time.Now()
+	d.UpdatedAt = d.ApprovedAt
+	r.emit(EventApproved, d)
+	r.save()
+}
+
+// SetApprovalMessage 승인 요청 Slack 메시지 정보 저장
+func (r *Registry) SetApprovalMessage(id, channel, timestamp string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if d, exist := r.deployments[id]; exist {
+		d.SlackChannel = channel
+		d.SlackTimestamp = timestamp
+		r.save()
+	}
+}
+
+// SetHealthCheck Health Check 결과 저장
+func (r *Registry) SetHealthCheck(id string, result probe.Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if d, exist := r.deployments[id]; exist {
+		d.HealthCheck = &result
+		d.UpdatedAt = time.Now()
+		if !result.Healthy {
+			r.emit(EventHealthCheckFailed, d)
+		}
+		r.save()
+	}
+}
+
+// Update 배포 기록 수정 (진행 단계, 오류, 분석 결과, 승인 정보 등). 상태 변경은 Finish 등 잠금 메서드를 사용한다.
+func (r *Registry) Update(id string, update func(d *Deployment)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, exist := r.deployments[id]
+	if !exist {
+		return
+	}
+
+	status, stage := d.Status, d.Stage
+	update(d)
+	d.Status = status
+	d.UpdatedAt = time.Now()
+	if d.Stage != stage && d.Stage != "" {
+		r.emit(EventStageChanged, d)
+	}
+	r.save()
+}
+
+// InFlight 종료되지 않은 배포 목록 (요청 순)
+func (r *Registry) InFlight() []Deployment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var inFlight []Deployment
+	for _, id := range r.order {
+		if d, exist := r.deployments[id]; exist && !d.Finished() {
+			inFlight = append(inFlight, *d)
+		}
+	}
+	return inFlight
+}
+
+// Get 배포 기록 조회
+func (r *Registry) Get(id string) (Deployment, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, exist := r.deployments[id]
+	if !exist {
+		return Deployment{}, false
+	}
+	return *d, true
+}
+
+// Latest 애플리케이션/환경의 최근 배포 기록 조회 (environment 미지정 시 전체 환경)
+func (r *Registry) Latest(application, environment string) (Deployment, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(r.order) - 1; i >= 0; i-- {
+		d, exist := r.deployments[r.order[i]]
+		if exist && d.Application == application && (environment == "" || d.Environment == environment) {
+			return *d, true
+		}
+	}
+	return Deployment{}, false
+}
+
+func (r *Registry) setStatus(id string, status Status) {
+	d := r.deployments[id]
+	d.Status = status
+	d.UpdatedAt = time.Now()
+}
+
+// lock 배포 잠금 획득 및 실행 상태로 변경 (r.mu 잠금 상태에서 호출)
+func (r *Registry) lock(key string, d *Deployment) context.Context {
+	lockCtx, cancel := context.WithCancelCause(context.Background())
+	r.active[key] = &lockEntry{id: d.ID, cancel: cancel}
+	r.setStatus(d.ID, StatusRunning)
+	if d.StartedAt.IsZero() {
+		d.StartedAt = d.UpdatedAt
+		r.emit(EventStarted, d)
+	}
+	return lockCtx
+}
+
+// release 배포 잠금 해제. 대기 중인 배포가 있으면 요청 순서대로 잠금을 넘긴다.
+func (r *Registry) release(key string, entry *lockEntry) {
+	if r.active[key] == entry {
+		delete(r.active, key)
+	}
+
+	queue := r.queued[key]
+	if len(queue) == 0 {
+		return
+	}
+	next := queue[0]
+	r.dequeue(key, next)
+	next.granted <- r.lock(key, r.deployments[next.id])
+}
+
+// supersede d보다 먼저 요청된 진행 중/대기 중 배포를 d로 대체
+// 더 최근에 요청된 배포가 이미 있으면(재기동 후 재개 순서 역전) d를 대체 처리하고 ErrSuperseded를 반환한다.
+func (r *Registry) supersede(key string, d *Deployment) ([]Deployment, error) {
+	entry, locked := r.active[key]
+
+	for _, w := range r.queued[key] {
+		if w.created.After(d.CreatedAt) {
+			r.markSuperseded(d, w.id)
+			return nil, ErrSuperseded
+		}
+	}
+	if locked {
+		if holder := r.deployments[entry.id]; holder.Status != StatusSuperseded && holder.CreatedAt.After(d.CreatedAt) {
+			r.markSuperseded(d, holder.ID)
+			return nil, ErrSuperseded
+		}
+	}
+
+	var superseded []Deployment
+	for _, w := range r.queued[key] {
+		old := r.deployments[w.id]
+		r.markSuperseded(old, d.ID)
+		w.cancel(ErrSuperseded)
+		superseded = append(superseded, *old)
+	}
+	delete(r.queued, key)
+
+	if locked {
+		holder := r.deployments[entry.id]
+		if holder.Status != StatusSuperseded {
+			r.markSuperseded(holder, d.ID)
+			entry.cancel(ErrSuperseded)
+			superseded = append(superseded, *holder)
+		}
+		// 승인 대기 중인 배포는 실행 중인 작업이 없으므로 즉시 잠금 해제.
+		// 실행 중인 배포는 취소 후 Finish 호출 시 잠금이 해제된다.
+		if entry.idle {
+			r.release(key, entry)
+		}
+	}
+	return superseded, nil
+}
+
+func (r *Registry) markSuperseded(d *Deployment, by string) {
+	d.SupersededBy = by
+	r.setStatus(d.ID, StatusSuperseded)
+	d.FinishedAt = d.UpdatedAt
+	metrics.ObserveDeployment(d.Application, d.Environment, string(d.Action), string(StatusSuperseded))
+	r.emit(EventFinished, d)
+}
+
+// enqueue 배포 요청(등록) 순서로 대기열에 추가. 재기동 후 재개된 배포도 원래 요청 순서를 유지한다.
+func (r *Registry) enqueue(key string, w *waiter) {
+	queue := r.queued[key]
+	i := len(queue)
+	for i > 0 && queue[i-1].created.After(w.created) {
+		i--
+	}
+	r.queued[key] = append(queue[:i], append([]*waiter{w}, queue[i:]...)...)
+}
+
+// dequeue 대기열에서 제거. 이미 잠금을 전달받아 대기열에 없는 경우 false를 반환한다.
+func (r *Registry) dequeue(key string, w *waiter) bool {
+	queue := r.queued[key]
+	for i, q := range queue {
+		if q == w {
+			r.queued[key] = append(queue[:i:i], queue[i+1:]...)
+			if len(r.queued[key]) == 0 {
+				delete(r.queued, key)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// prune 오래된 종료 배포 기록 정리
+func (r *Registry) prune() {
+	for len(r.order) > maxHistory {
+		id := r.order[0]
+		if d, exist := r.deployments[id]; exist && !d.Finished() {
+			return
+		}
+		delete(r.deployments, id)
+		r.order = r.order[1:]
+	}
+}
+
+// state 배포 기록 상태 파일 항목. API 응답에서 제외되는 원본 요청을 함께 저장한다.
+type state struct {
+	Deployment
+	Request  json.RawMessage `json:"request,omitempty"`
+	Approval json.RawMessage `json:"approval,omitempty"`
+}
+
+// Open 상태 파일에서 배포 기록을 복원한 Registry 생성. 경로가 비어있는 경우 메모리에만 보관한다.
+// 승인 대기 중인 배포는 배포 잠금을 복원하고, 실행 중이던 배포는 대기(queued) 상태로 되돌려 재개(Acquire) 시 잠금을 다시 획득한다.
+func Open(path string) (*Registry, error) {
+	r := NewRegistry()
+	if path == "" {
+		return r, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("deployment: failed to create state directory: %w", err)
+	}
+	r.statePath = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("deployment: failed to read state file: %w", err)
+	}
+
+	var states []state
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("deployment: failed to decode state file %s: %w", path, err)
+	}
+
+	for _, st := range states {
+		d := st.Deployment
+		d.Request = st.Request
+		d.Approval = st.Approval
+
+		switch d.Status {
+		case StatusAwaitingApproval:
+			if _, locked := r.active[d.Key()]; !locked {
+				_, cancel := context.WithCancelCause(context.Background())
+				r.active[d.Key()] = &lockEntry{id: d.ID, cancel: cancel, idle: true}
+			}
+		case StatusRunning:
+			d.Status = StatusQueued
+		}
+
+		r.deployments[d.ID] = &d
+		r.order = append(r.order, d.ID)
+	}
+	return r, nil
+}
+
+// save 상태 파일에 배포 기록 저장 (r.mu 잠금 상태에서 호출)
+// 임시 파일에 기록한 뒤 교체하므로 저장 중 종료되어도 이전 상태 파일은 유지된다.
+func (r *Registry) save() {
+	if r.statePath == "" {
+		return
+	}
+
+	states := make([]state, 0, len(r.order))
+	for _, id := range r.order {
+		if d, exist := r.deployments[id]; exist {
+			states = append(states, state{Deployment: *d, Request: d.Request, Approval: d.Approval})
+		}
+	}
+
+	data, err := json.Marshal(states)
+	if err != nil {
+		log.Error().Err(err).Msg("deployment | failed to encode deployment state")
+		return
+	}
+
+	tmp := r.statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		log.Error().Err(err).Msgf("deployment | failed to write deployment state: %s", tmp)
+		return
+	}
+	if err := os.Rename(tmp, r.statePath); err != nil {
+		log.Error().Err(err).Msgf("deployment | failed to replace deployment state: %s", r.statePath)
+	}
+}
@@ -0,0 +1,372 @@
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testKey = "homepage-front/prod"
+
+// acquireResult 대기 중인 Acquire 결과
+type acquireResult struct {
+	lock       context.Context
+	superseded []Deployment
+	err        error
+}
+
+func register(t *testing.T, r *Registry, id string) {
+	t.Helper()
+	if _, err := r.Register(Deployment{ID: id, Action: ActionDeploy, Application: "homepage-front", Environment: "prod"}); err != nil {
+		t.Fatalf("Register(%s): %v", id, err)
+	}
+}
+
+func acquire(t *testing.T, r *Registry, id string, policy LockPolicy) context.Context {
+	t.Helper()
+	lock, _, err := r.Acquire(context.Background(), id, policy, time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire(%s): %v", id, err)
+	}
+	return lock
+}
+
+// acquireAsync 대기열에 등록될 때까지 기다린 뒤 결과 채널 반환
+func acquireAsync(t *testing.T, ctx context.Context, r *Registry, id string, policy LockPolicy, timeout time.Duration) <-chan acquireResult {
+	t.Helper()
+	result := make(chan acquireResult, 1)
+	go func() {
+		lock, superseded, err := r.Acquire(ctx, id, policy, timeout)
+		result <- acquireResult{lock: lock, superseded: superseded, err: err}
+	}()
+	waitFor(t, func() bool { return slices.Contains(queuedIDs(r), id) }, "%s queued", id)
+	return result
+}
+
+func queuedIDs(r *Registry) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, w := range r.queued[testKey] {
+		ids = append(ids, w.id)
+	}
+	return ids
+}
+
+func waitFor(t *testing.T, cond func() bool, format string, args ...any) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for "+format, args...)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func receive(t *testing.T, result <-chan acquireResult) acquireResult {
+	t.Helper()
+	select {
+	case res := <-result:
+		return res
+	case <-time.After(5 * time.Second):
+		t.Fatal("Acquire did not return")
+		return acquireResult{}
+	}
+}
+
+func assertPending(t *testing.T, result <-chan acquireResult) {
+	t.Helper()
+	select {
+	case res := <-result:
+		t.Fatalf("Acquire returned %+v, want waiting", res)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func assertStatus(t *testing.T, r *Registry, id string, want Status) Deployment {
+	t.Helper()
+	d, _ := r.Get(id)
+	if d.Status != want {
+		t.Errorf("%s status = %s, want %s", id, d.Status, want)
+	}
+	return d
+}
+
+func TestAcquireQueueOrder(t *testing.T) {
+	r := NewRegistry()
+	for _, id := range []string{"dep-1", "dep-2", "dep-3"} {
+		register(t, r, id)
+	}
+
+	acquire(t, r, "dep-1", LockQueue)
+	second := acquireAsync(t, context.Background(), r, "dep-2", LockQueue, time.Minute)
+	third := acquireAsync(t, context.Background(), r, "dep-3", LockQueue, time.Minute)
+	assertStatus(t, r, "dep-2", StatusQueued)
+
+	// 잠금 해제 시 먼저 요청된 배포부터 잠금을 획득한다.
+	r.Finish("dep-1", StatusSucceeded)
+	if res := receive(t, second); res.err != nil || res.lock == nil {
+		t.Fatalf("dep-2 Acquire = %+v", res)
+	}
+	assertStatus(t, r, "dep-2", StatusRunning)
+	assertPending(t, third)
+
+	r.Finish("dep-2", StatusFailed)
+	if res := receive(t, third); res.err != nil {
+		t.Fatalf("dep-3 Acquire = %+v", res)
+	}
+	if d := assertStatus(t, r, "dep-3", StatusRunning); d.StartedAt.IsZero() {
+		t.Error("dep-3 started_at not set")
+	}
+}
+
+func TestAcquireSupersedeRunning(t *testing.T) {
+	r := NewRegistry()
+	register(t, r, "dep-1")
+	register(t, r, "dep-2")
+
+	lock := acquire(t, r, "dep-1", LockSupersede)
+	result := acquireAsync(t, context.Background(), r, "dep-2", LockSupersede, time.Minute)
+
+	// 실행 중인 배포는 취소되고, Finish로 잠금이 해제되면 신규 배포가 잠금을 획득한다.
+	if !errors.Is(context.Cause(lock), ErrSuperseded) {
+		t.Errorf("dep-1 lock cause = %v, want ErrSuperseded", context.Cause(lock))
+	}
+	if d := assertStatus(t, r, "dep-1", StatusSuperseded); d.SupersededBy != "dep-2" || d.FinishedAt.IsZero() {
+		t.Errorf("dep-1 = %+v", d)
+	}
+	assertPending(t, result)
+
+	r.Finish("dep-1", StatusFailed)
+	res := receive(t, result)
+	if res.err != nil || len(res.superseded) != 1 || res.superseded[0].ID != "dep-1" {
+		t.Fatalf("dep-2 Acquire = %+v", res)
+	}
+	assertStatus(t, r, "dep-1", StatusSuperseded)
+}
+
+func TestAcquireSupersedeAwaitingApproval(t *testing.T) {
+	r := NewRegistry()
+	register(t, r, "dep-1")
+	register(t, r, "dep-2")
+
+	acquire(t, r, "dep-1", LockSupersede)
+	r.AwaitApproval("dep-1")
+
+	// 승인 대기 중인 배포는 실행 중인 작업이 없으므로 즉시 잠금을 넘긴다.
+	_, superseded, err := r.Acquire(context.Background(), "dep-2", LockSupersede, time.Minute)
+	if err != nil || len(superseded) != 1 || superseded[0].ID != "dep-1" {
+		t.Fatalf("Acquire = %v, %v", superseded, err)
+	}
+	assertStatus(t, r, "dep-2", StatusRunning)
+	if _, err := r.Resume("dep-1"); !errors.Is(err, ErrSuperseded) {
+		t.Errorf("Resume(dep-1) = %v, want ErrSuperseded", err)
+	}
+}
+
+func TestAcquireSupersedeQueued(t *testing.T) {
+	r := NewRegistry()
+	for _, id := range []string{"dep-1", "dep-2", "dep-3"} {
+		register(t, r, id)
+	}
+
+	acquire(t, r, "dep-1", LockQueue)
+	queued := acquireAsync(t, context.Background(), r, "dep-2", LockQueue, time.Minute)
+	latest := acquireAsync(t, context.Background(), r, "dep-3", LockSupersede, time.Minute)
+
+	// 대기 중인 배포도 대체되어 잠금을 기다리지 않고 ErrSuperseded를 반환한다.
+	if res := receive(t, queued); !errors.Is(res.err, ErrSuperseded) {
+		t.Fatalf("dep-2 Acquire = %+v, want ErrSuperseded", res)
+	}
+	if d := assertStatus(t, r, "dep-2", StatusSuperseded); d.SupersededBy != "dep-3" {
+		t.Errorf("dep-2 superseded by %q, want dep-3", d.SupersededBy)
+	}
+	if ids := queuedIDs(r); len(ids) != 1 || ids[0] != "dep-3" {
+		t.Errorf("queued = %v, want [dep-3]", ids)
+	}
+
+	r.Finish("dep-1", StatusFailed)
+	res := receive(t, latest)
+	if res.err != nil || len(res.superseded) != 2 || res.superseded[0].ID != "dep-2" || res.superseded[1].ID != "dep-1" {
+		t.Fatalf("dep-3 Acquire = %+v", res)
+	}
+}
+
+func TestAcquireQueueTimeout(t *testing.T) {
+	r := NewRegistry()
+	register(t, r, "dep-1")
+	register(t, r, "dep-2")
+
+	acquire(t, r, "dep-1", LockQueue)
+	_, _, err := r.Acquire(context.Background(), "dep-2", LockQueue, 20*time.Millisecond)
+	if !errors.Is(err, ErrQueueTimeout) || err.Error() != ErrQueueTimeout.Error()+": dep-1" {
+		t.Fatalf("err = %v, want ErrQueueTimeout for dep-1", err)
+	}
+	if d := assertStatus(t, r, "dep-2", StatusFailed); d.FinishedAt.IsZero() {
+		t.Error("dep-2 finished_at not set")
+	}
+	if ids := queuedIDs(r); len(ids) != 0 {
+		t.Errorf("queued = %v, want none", ids)
+	}
+	assertStatus(t, r, "dep-1", StatusRunning)
+}
+
+func TestAcquireCanceled(t *testing.T) {
+	r := NewRegistry()
+	for _, id := range []string{"dep-1", "dep-2", "dep-3"} {
+		register(t, r, id)
+	}
+
+	acquire(t, r, "dep-1", LockQueue)
+	shutdown := errors.New("server shutdown")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	canceled := acquireAsync(t, ctx, r, "dep-2", LockQueue, time.Minute)
+	next := acquireAsync(t, context.Background(), r, "dep-3", LockQueue, time.Minute)
+
+	// 서버 종료로 대기가 취소된 배포는 대기 상태로 남아 재기동 시 재개된다.
+	cancel(shutdown)
+	if res := receive(t, canceled); !errors.Is(res.err, shutdown) {
+		t.Fatalf("dep-2 Acquire = %+v, want shutdown", res)
+	}
+	assertStatus(t, r, "dep-2", StatusQueued)
+
+	r.Finish("dep-1", StatusSucceeded)
+	if res := receive(t, next); res.err != nil {
+		t.Fatalf("dep-3 Acquire = %+v", res)
+	}
+}
+
+func writeState(t *testing.T, states []state) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "deployments.json")
+	data, err := json.Marshal(states)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func stateOf(id string, status Status, created time.Time) state {
+	return state{
+		Deployment: Deployment{ID: id, Action: ActionDeploy, Application: "homepage-front", Environment: "prod", Status: status, CreatedAt: created},
+		Request:    json.RawMessage(`{"docker_tag":"v1.2.3"}`),
+	}
+}
+
+func TestOpenResume(t *testing.T) {
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	path := writeState(t, []state{
+		stateOf("dep-1", StatusSucceeded, base),
+		stateOf("dep-2", StatusAwaitingApproval, base.Add(time.Minute)),
+		stateOf("dep-3", StatusRunning, base.Add(2*time.Minute)),
+		stateOf("dep-4", StatusQueued, base.Add(3*time.Minute)),
+	})
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	// 실행 중이던 배포는 대기 상태로 복원하고, 승인 대기 중인 배포는 잠금을 유지한다.
+	var inFlight []string
+	for _, d := range r.InFlight() {
+		inFlight = append(inFlight, d.ID+":"+string(d.Status))
+	}
+	if want := "dep-2:awaiting_approval,dep-3:queued,dep-4:queued"; strings.Join(inFlight, ",") != want {
+		t.Errorf("in flight = %s, want %s", strings.Join(inFlight, ","), want)
+	}
+	if d, _ := r.Get("dep-3"); string(d.Request) != `{"docker_tag":"v1.2.3"}` {
+		t.Errorf("request = %s, want restored", d.Request)
+	}
+
+	// 재개 순서와 관계없이 원래 요청 순서대로 잠금을 획득한다.
+	later := acquireAsync(t, context.Background(), r, "dep-4", LockQueue, time.Minute)
+	earlier := acquireAsync(t, context.Background(), r, "dep-3", LockQueue, time.Minute)
+	if ids := queuedIDs(r); strings.Join(ids, ",") != "dep-3,dep-4" {
+		t.Errorf("queued = %v, want request order", ids)
+	}
+
+	if _, err := r.Resume("dep-2"); err != nil {
+		t.Fatalf("Resume(dep-2): %v", err)
+	}
+	r.Finish("dep-2", StatusApproved)
+	if res := receive(t, earlier); res.err != nil {
+		t.Fatalf("dep-3 Acquire = %+v", res)
+	}
+	assertPending(t, later)
+	r.Finish("dep-3", StatusSucceeded)
+	if res := receive(t, later); res.err != nil {
+		t.Fatalf("dep-4 Acquire = %+v", res)
+	}
+
+	// 상태 파일에 변경 사항이 저장되어 다시 열어도 유지된다.
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	assertStatus(t, reopened, "dep-3", StatusSucceeded)
+	assertStatus(t, reopened, "dep-4", StatusQueued)
+}
+
+func TestResumeSupersedeOrder(t *testing.T) {
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	r, err := Open(writeState(t, []state{
+		stateOf("dep-1", StatusRunning, base),
+		stateOf("dep-2", StatusRunning, base.Add(time.Minute)),
+	}))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	// 최근 요청이 먼저 재개된 경우 이전 요청은 최근 요청을 대체하지 않고 대체 처리된다.
+	acquire(t, r, "dep-2", LockSupersede)
+	_, superseded, err := r.Acquire(context.Background(), "dep-1", LockSupersede, time.Minute)
+	if !errors.Is(err, ErrSuperseded) || len(superseded) != 0 {
+		t.Fatalf("Acquire(dep-1) = %v, %v, want ErrSuperseded", superseded, err)
+	}
+	if d := assertStatus(t, r, "dep-1", StatusSuperseded); d.SupersededBy != "dep-2" {
+		t.Errorf("dep-1 superseded by %q, want dep-2", d.SupersededBy)
+	}
+	assertStatus(t, r, "dep-2", StatusRunning)
+}
+
+func TestLatest(t *testing.T) {
+	r := NewRegistry()
+	for _, d := range []Deployment{
+		{ID: "dep-1", Application: "homepage-front", Environment: "prod"},
+		{ID: "dep-2", Application: "homepage-front", Environment: "dev"},
+		{ID: "dep-3", Application: "cms-front", Environment: "prod"},
+	} {
+		if _, err := r.Register(d); err != nil {
+			t.Fatalf("Register(%s): %v", d.ID, err)
+		}
+	}
+
+	cases := []struct {
+		application string
+		environment string
+		want        string
+	}{
+		{application: "homepage-front", environment: "prod", want: "dep-1"},
+		{application: "homepage-front", want: "dep-2"},
+		{application: "cms-front", environment: "dev"},
+		{application: "mydata-api"},
+	}
+
+	for _, tc := range cases {
+		d, exist := r.Latest(tc.application, tc.environment)
+		if exist != (tc.want != "") || d.ID != tc.want {
+			t.Errorf("Latest(%s, %s) = %q, %t, want %q", tc.application, tc.environment, d.ID, exist, tc.want)
+		}
+	}
+}
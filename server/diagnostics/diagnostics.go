@@ -0,0 +1,278 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxLogContainers 로그를 수집할 최대 컨테이너 수
+const maxLogContainers = 5
+
+// Options 진단 정보 수집 범위
+type Options struct {
+	// 실패한 컨테이너별 로그 라인 수
+	LogLines int64
+	// 최근 이벤트 수
+	Events int
+}
+
+// Target 진단 대상 Pod (Preview ReplicaSet)
+type Target struct {
+	Namespace string
+	// Pod label selector (예: rollouts-pod-template-hash=6d4b8c7f9)
+	Selector string
+	// 이벤트 조회 대상 ReplicaSet 이름 (선택)
+	ReplicaSet string
+}
+
+// Report Pod 상태, 재시작 횟수, 최근 이벤트 및 실패한 컨테이너 로그
+type Report struct {
+	Namespace   string    `json:"namespace"`
+	Selector    string    `json:"selector"`
+	ReplicaSet  string    `json:"replica_set,omitempty"`
+	CollectedAt time.Time `json:"collected_at"`
+	Pods        []Pod     `json:"pods"`
+	Events      []Event   `json:"events,omitempty"`
+}
+
+type Pod struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+	Node  string `json:"node,omitempty"`
+	Ready bool   `json:"ready"`
+	// 비정상 Pod 조건 (예: PodScheduled: Unschedulable - 0/3 nodes are available)
+	Conditions []string    `json:"conditions,omitempty"`
+	Containers []Container `json:"containers"`
+}
+
+type Container struct {
+	Name     string `json:"name"`
+	Init     bool   `json:"init,omitempty"`
+	Ready    bool   `json:"ready"`
+	Restarts int32  `json:"restarts"`
+	// 현재 상태 (예: Waiting: CrashLoopBackOff)
+	State     string `json:"state"`
+	LastState string `json:"last_state,omitempty"`
+	Message   string `json:"message,omitempty"`
+	// 실패한 컨테이너의 마지막 로그 (재시작된 경우 이전 컨테이너 로그)
+	Logs     string `json:"logs,omitempty"`
+	LogError string `json:"log_error,omitempty"`
+}
+
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Object  string    `json:"object"`
+	Message string    `json:"message"`
+	Count   int32     `json:"count,omitempty"`
+}
+
+// Collector Kubernetes API로 진단 정보 수집
+type Collector struct {
+	client kubernetes.Interface
+}
+
+func NewCollector(client kubernetes.Interface) *Collector {
+	return &Collector{client: client}
+}
+
+// NewCollectorFromConfig kubeconfig 경로가 비어있으면 in-cluster ServiceAccount 인증 정보를 사용한다.
+func NewCollectorFromConfig(kubeconfig, kubeContext string) (*Collector, error) {
+	var (
+		cfg *rest.Config
+		err error
+	)
+
+	if kubeconfig == "" {
+		cfg, err = rest.InClusterConfig()
+	} else {
+		cfg, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+			&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+		).ClientConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to load kubernetes config: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to create kubernetes client: %w", err)
+	}
+	return NewCollector(client), nil
+}
+
+// Collect 대상 Pod 상태, 최근 이벤트 및 실패한 컨테이너 로그 수집
+// 이벤트 및 로그 조회 실패는 보고서에 기록하고 Pod 목록 조회 실패만 오류로 반환한다.
+func (c *Collector) Collect(ctx context.Context, t Target, opts Options) (*Report, error) {
+	pods, err := c.client.CoreV1().Pods(t.Namespace).List(ctx, metav1.ListOptions{LabelSelector: t.Selector})
+	if err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to list pods in %s (%s): %w", t.Namespace, t.Selector, err)
+	}
+
+	report := &Report{
+		Namespace:   t.Namespace,
+		Selector:    t.Selector,
+		ReplicaSet:  t.ReplicaSet,
+		CollectedAt: time.Now(),
+		Pods:        []Pod{},
+	}
+
+	objects := map[string]bool{}
+	if t.ReplicaSet != "" {
+		objects[t.ReplicaSet] = true
+	}
+
+	logs := 0
+	for _, item := range pods.Items {
+		objects[item.Name] = true
+		pod := newPod(item)
+
+		for i := range pod.Containers {
+			container := &pod.Containers[i]
+			if !container.failing() || logs >= maxLogContainers || opts.LogLines <= 0 {
+				continue
+			}
+			logs++
+			container.Logs, err = c.logs(ctx, item, *container, opts.LogLines)
+			if err != nil {
+				container.LogError = err.Error()
+			}
+		}
+		report.Pods = append(report.Pods, pod)
+	}
+
+	if opts.Events > 0 {
+		events, err := c.events(ctx, t.Namespace, objects, opts.Events)
+		if err != nil {
+			report.Events = []Event{{Time: time.Now(), Type: "Error", Reason: "EventsUnavailable", Message: err.Error()}}
+		} else {
+			report.Events = events
+		}
+	}
+	return report, nil
+}
+
+// logs 컨테이너 마지막 로그. 재시작 이후 실행 중이 아닌 경우 종료된 이전 컨테이너 로그를 조회한다.
+func (c *Collector) logs(ctx context.Context, pod corev1.Pod, container Container, lines int64) (string, error) {
+	options := &corev1.PodLogOptions{
+		Container: container.Name,
+		TailLines: &lines,
+		Previous:  container.Restarts > 0 && !strings.HasPrefix(container.State, "Running"),
+	}
+
+	raw, err := c.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("diagnostics: failed to get logs of %s/%s: %w", pod.Name, container.Name, err)
+	}
+	return strings.TrimRight(string(raw), "\n"), nil
+}
+
+// events Pod 및 ReplicaSet 관련 최근 이벤트 (오래된 순)
+func (c *Collector) events(ctx context.Context, namespace string, objects map[string]bool, limit int) ([]Event, error) {
+	list, err := c.client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to list events in %s: %w", namespace, err)
+	}
+
+	var events []Event
+	for _, e := range list.Items {
+		if !objects[e.InvolvedObject.Name] {
+			continue
+		}
+		events = append(events, Event{
+			Time:    eventTime(e),
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Object:  fmt.Sprintf("%s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Name),
+			Message: e.Message,
+			Count:   e.Count,
+		})
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	if len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events, nil
+}
+
+func eventTime(e corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+func newPod(item corev1.Pod) Pod {
+	pod := Pod{
+		Name:  item.Name,
+		Phase: string(item.Status.Phase),
+		Node:  item.Spec.NodeName,
+	}
+
+	for _, cond := range item.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			pod.Ready = cond.Status == corev1.ConditionTrue
+		}
+		if cond.Status != corev1.ConditionTrue && cond.Message != "" {
+			pod.Conditions = append(pod.Conditions, fmt.Sprintf("%s: %s - %s", cond.Type, cond.Reason, cond.Message))
+		}
+	}
+
+	for _, cs := range item.Status.InitContainerStatuses {
+		container := newContainer(cs)
+		container.Init = true
+		pod.Containers = append(pod.Containers, container)
+	}
+	for _, cs := range item.Status.ContainerStatuses {
+		pod.Containers = append(pod.Containers, newContainer(cs))
+	}
+	return pod
+}
+
+func newContainer(cs corev1.ContainerStatus) Container {
+	container := Container{
+		Name:     cs.Name,
+		Ready:    cs.Ready,
+		Restarts: cs.RestartCount,
+	}
+	container.State, container.Message = describeState(cs.State)
+	if cs.LastTerminationState.Terminated != nil {
+		container.LastState, _ = describeState(cs.LastTerminationState)
+	}
+	return container
+}
+
+// describeState 컨테이너 상태 요약 및 메시지
+func describeState(state corev1.ContainerState) (string, string) {
+	switch {
+	case state.Running != nil:
+		return "Running", ""
+	case state.Waiting != nil:
+		return fmt.Sprintf("Waiting: %s", state.Waiting.Reason), state.Waiting.Message
+	case state.Terminated != nil:
+		return fmt.Sprintf("Terminated: %s (exit %d)", state.Terminated.Reason, state.Terminated.ExitCode), state.Terminated.Message
+	}
+	return "Unknown", ""
+}
+
+// failing 로그 수집 대상 컨테이너 (준비되지 않았거나 재시작된 컨테이너). 정상 종료된 init 컨테이너는 제외한다.
+func (c Container) failing() bool {
+	if c.Init && c.State == "Terminated: Completed (exit 0)" {
+		return false
+	}
+	return !c.Ready || c.Restarts > 0
+}
@@ -0,0 +1,127 @@
+package diagnostics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// summaryEvents 요약에 표시할 최근 Warning 이벤트 수
+const summaryEvents = 3
+
+// Summary Slack 알림에 표시할 요약 (Pod 수, 비정상 Pod 및 컨테이너 상태, 최근 Warning 이벤트)
+func (r Report) Summary() []string {
+	ready := 0
+	var lines []string
+	for _, pod := range r.Pods {
+		if pod.Ready {
+			ready++
+			continue
+		}
+
+		line := fmt.Sprintf("`%s` %s", pod.Name, pod.Phase)
+		for _, c := range pod.Containers {
+			if c.failing() {
+				line += fmt.Sprintf(" | %s: %s (재시작 %d회)", c.Name, c.State, c.Restarts)
+			}
+		}
+		for _, cond := range pod.Conditions {
+			line += " | " + cond
+		}
+		lines = append(lines, line)
+	}
+
+	var warnings []string
+	for i := len(r.Events) - 1; i >= 0 && len(warnings) < summaryEvents; i-- {
+		if e := r.Events[i]; e.Type != "Normal" {
+			warnings = append(warnings, fmt.Sprintf("%s `%s` %s", e.Reason, e.Object, e.Message))
+		}
+	}
+
+	summary := []string{fmt.Sprintf("Pod `%d/%d` Ready (`%s`)", ready, len(r.Pods), r.Selector)}
+	summary = append(summary, lines...)
+	return append(summary, warnings...)
+}
+
+// Text Pod 상태, 이벤트 및 컨테이너 로그 전체 보고서 (Slack 파일 업로드용)
+func (r Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Namespace: %s\nSelector: %s\n", r.Namespace, r.Selector)
+	if r.ReplicaSet != "" {
+		fmt.Fprintf(&b, "ReplicaSet: %s\n", r.ReplicaSet)
+	}
+	fmt.Fprintf(&b, "Collected at: %s\n", r.CollectedAt.Format(time.RFC3339))
+
+	b.WriteString("\n== Pods ==\n")
+	if len(r.Pods) == 0 {
+		b.WriteString("(no pods)\n")
+	}
+	for _, pod := range r.Pods {
+		fmt.Fprintf(&b, "%s  phase=%s ready=%t node=%s\n", pod.Name, pod.Phase, pod.Ready, pod.Node)
+		for _, cond := range pod.Conditions {
+			fmt.Fprintf(&b, "  condition: %s\n", cond)
+		}
+		for _, c := range pod.Containers {
+			kind := "container"
+			if c.Init {
+				kind = "init"
+			}
+			fmt.Fprintf(&b, "  %s %s  ready=%t restarts=%d state=%s", kind, c.Name, c.Ready, c.Restarts, c.State)
+			if c.LastState != "" {
+				fmt.Fprintf(&b, " last=%s", c.LastState)
+			}
+			b.WriteString("\n")
+			if c.Message != "" {
+				fmt.Fprintf(&b, "    message: %s\n", c.Message)
+			}
+		}
+	}
+
+	b.WriteString("\n== Events ==\n")
+	if len(r.Events) == 0 {
+		b.WriteString("(no events)\n")
+	}
+	for _, e := range r.Events {
+		fmt.Fprintf(&b, "%s  %-7s %-20s %s  %s", e.Time.Format(time.RFC3339), e.Type, e.Reason, e.Object, e.Message)
+		if e.Count > 1 {
+			fmt.Fprintf(&b, " (x%d)", e.Count)
+		}
+		b.WriteString("\n")
+	}
+
+	for _, pod := range r.Pods {
+		for _, c := range pod.Containers {
+			if c.Logs == "" && c.LogError == "" {
+				continue
+			}
+			fmt.Fprintf(&b, "\n== Logs: %s/%s ==\n", pod.Name, c.Name)
+			if c.LogError != "" {
+				fmt.Fprintf(&b, "(%s)\n", c.LogError)
+				continue
+			}
+			b.WriteString(c.Logs + "\n")
+		}
+	}
+	return b.String()
+}
+
+// Redact 컨테이너 로그, 메시지, Pod 조건 및 이벤트 메시지에 redact 적용 (배포 기록 저장 및 Slack 전송 전 비밀 정보 마스킹)
+func (r *Report) Redact(redact func(string) string) {
+	for i := range r.Pods {
+		pod := &r.Pods[i]
+		for j := range pod.Conditions {
+			pod.Conditions[j] = redact(pod.Conditions[j])
+		}
+		for j := range pod.Containers {
+			c := &pod.Containers[j]
+			c.State = redact(c.State)
+			c.LastState = redact(c.LastState)
+			c.Message = redact(c.Message)
+			c.Logs = redact(c.Logs)
+			c.LogError = redact(c.LogError)
+		}
+	}
+	for i := range r.Events {
+		r.Events[i].Message = redact(r.Events[i].Message)
+	}
+}
@@ -0,0 +1,225 @@
+package dora
+
+import (
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter 지표 계산 대상. 빈 값은 전체를 의미한다.
+type Filter struct {
+	Team        string
+	Application string
+	Environment string
+	// 종료 시각 기준 집계 기간 및 기준 시각 (기본값 현재)
+	Window time.Duration
+	Now    time.Time
+}
+
+// Metrics DORA 지표
+type Metrics struct {
+	Team        string    `json:"team,omitempty"`
+	Application string    `json:"application,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	Window      string    `json:"window"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+
+	DeploymentFrequency Frequency    `json:"deployment_frequency"`
+	LeadTime            DurationStat `json:"lead_time"`
+	ChangeFailureRate   FailureRate  `json:"change_failure_rate"`
+	TimeToRestore       DurationStat `json:"time_to_restore"`
+}
+
+// Frequency 성공한 배포 수 및 일 평균 배포 수
+type Frequency struct {
+	Deployments int     `json:"deployments"`
+	PerDay      float64 `json:"per_day"`
+}
+
+// DurationStat 소요 시간 중앙값. 표본이 없는 경우 MedianSeconds는 nil이다.
+type DurationStat struct {
+	MedianSeconds *float64 `json:"median_seconds"`
+	Samples       int      `json:"samples"`
+	// 아직 복구되지 않은 장애 수 (time_to_restore)
+	Unresolved int `json:"unresolved,omitempty"`
+}
+
+// FailureRate 변경 실패율 (실패한 배포 / 운영 반영을 시도한 배포)
+type FailureRate struct {
+	Failures    int     `json:"failures"`
+	Deployments int     `json:"deployments"`
+	Rate        float64 `json:"rate"`
+}
+
+// ParseWindow 집계 기간 (예: 7d, 30d, 12h, 2w)
+func ParseWindow(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if value, found := strings.CutSuffix(s, suffix); found {
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("dora: invalid window %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("dora: invalid window %q", s)
+	}
+	return d, nil
+}
+
+// FormatWindow 집계 기간 표시 (일 단위인 경우 7d 형식)
+func FormatWindow(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// Compute 필터에 해당하는 배포 기록의 DORA 지표 계산
+//
+//   - 배포 빈도: 기간 내 성공(succeeded, approved)한 배포(deploy) 수
+//   - 변경 리드 타임: 성공한 배포의 커밋 시각부터 배포 종료까지 시간
+//   - 변경 실패율: 운영 반영을 시도한 배포(성공, ArgoCD Sync 요청 이후 실패) 중 실패, Health Check 실패 혹은 롤백된 배포 비율.
+//     Sync 요청 전에 실패한 배포(이미지 반영 실패, Sync 요청 실패 등)는 운영에 반영되지 않았으므로 제외한다.
+//   - 서비스 복구 시간: 실패한 배포 종료부터 같은 애플리케이션/환경의 다음 성공한 배포 혹은 롤백 종료까지 시간
+func (s *Store) Compute(f Filter) Metrics {
+	return compute(s.Records(), f)
+}
+
+// Groups 팀/애플리케이션/환경 단위 DORA 지표 (Prometheus gauge 용)
+func (s *Store) Groups(window time.Duration, now time.Time) []Metrics {
+	records := s.Records()
+
+	type key struct{ team, application, environment string }
+	seen := map[key]bool{}
+	var groups []Metrics
+	for _, r := range records {
+		k := key{r.Team, r.Application, r.Environment}
+		if r.Action != deployment.ActionDeploy || seen[k] {
+			continue
+		}
+		seen[k] = true
+		groups = append(groups, compute(records, Filter{
+			Team:        r.Team,
+			Application: r.Application,
+			Environment: r.Environment,
+			Window:      window,
+			Now:         now,
+		}))
+	}
+	return groups
+}
+
+func compute(records []Record, f Filter) Metrics {
+	if f.Now.IsZero() {
+		f.Now = time.Now()
+	}
+	from := f.Now.Add(-f.Window)
+	m := Metrics{
+		Team:        f.Team,
+		Application: f.Application,
+		Environment: f.Environment,
+		Window:      FormatWindow(f.Window),
+		From:        from,
+		To:          f.Now,
+	}
+
+	match := func(r Record) bool {
+		return (f.Team == "" || r.Team == f.Team) &&
+			(f.Application == "" || r.Application == f.Application) &&
+			(f.Environment == "" || r.Environment == f.Environment)
+	}
+
+	rolledBack := map[string]bool{}
+	for _, r := range records {
+		if r.Action == deployment.ActionRollback && r.RollbackOf != "" {
+			rolledBack[r.RollbackOf] = true
+		}
+	}
+
+	var leadTimes, restoreTimes []time.Duration
+	for i, r := range records {
+		if !match(r) || r.Action != deployment.ActionDeploy || r.FinishedAt.Before(from) || r.FinishedAt.After(f.Now) {
+			continue
+		}
+
+		succeeded := r.Succeeded()
+		if !succeeded && !r.failedAfterSync() {
+			// 반려(rejected)되었거나 Sync 요청 전에 실패한 배포는 운영에 반영되지 않았으므로 제외
+			continue
+		}
+		m.ChangeFailureRate.Deployments++
+
+		if succeeded {
+			m.DeploymentFrequency.Deployments++
+			if !r.CommittedAt.IsZero() && r.FinishedAt.After(r.CommittedAt) {
+				leadTimes = append(leadTimes, r.FinishedAt.Sub(r.CommittedAt))
+			}
+		}
+
+		if !succeeded || r.HealthCheckFailed || rolledBack[r.ID] {
+			m.ChangeFailureRate.Failures++
+			if restored, ok := restoredAt(records[i+1:], r); ok {
+				restoreTimes = append(restoreTimes, restored.Sub(r.FinishedAt))
+			} else {
+				m.TimeToRestore.Unresolved++
+			}
+		}
+	}
+
+	if days := f.Window.Hours() / 24; days > 0 {
+		m.DeploymentFrequency.PerDay = float64(m.DeploymentFrequency.Deployments) / days
+	}
+	if m.ChangeFailureRate.Deployments > 0 {
+		m.ChangeFailureRate.Rate = float64(m.ChangeFailureRate.Failures) / float64(m.ChangeFailureRate.Deployments)
+	}
+	m.LeadTime = durationStat(leadTimes)
+	unresolved := m.TimeToRestore.Unresolved
+	m.TimeToRestore = durationStat(restoreTimes)
+	m.TimeToRestore.Unresolved = unresolved
+	return m
+}
+
+// Succeeded 배포 성공 여부
+func (r Record) Succeeded() bool {
+	return r.Status == deployment.StatusSucceeded || r.Status == deployment.StatusApproved
+}
+
+// failedAfterSync 운영 반영을 시도했으나 실패한 배포 여부 (ArgoCD Sync 요청 이후 실패)
+func (r Record) failedAfterSync() bool {
+	return r.Status == deployment.StatusFailed && !r.SyncedAt.IsZero()
+}
+
+// restoredAt 실패한 배포 이후 같은 애플리케이션/환경에서 처음 성공한 배포 혹은 롤백의 종료 시각
+func restoredAt(next []Record, failed Record) (time.Time, bool) {
+	for _, r := range next {
+		if r.Application == failed.Application && r.Environment == failed.Environment && r.Succeeded() && r.FinishedAt.After(failed.FinishedAt) {
+			return r.FinishedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func durationStat(samples []time.Duration) DurationStat {
+	stat := DurationStat{Samples: len(samples)}
+	if len(samples) == 0 {
+		return stat
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	median := samples[len(samples)/2]
+	if len(samples)%2 == 0 {
+		median = (samples[len(samples)/2-1] + samples[len(samples)/2]) / 2
+	}
+	seconds := median.Seconds()
+	stat.MedianSeconds = &seconds
+	return stat
+}
@@ -0,0 +1,154 @@
+package dora
+
+import (
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+func deploy(id, application string, status deployment.Status, finished time.Duration) Record {
+	return Record{
+		ID:          id,
+		Action:      deployment.ActionDeploy,
+		Application: application,
+		Environment: "prod",
+		Team:        "platform",
+		Status:      status,
+		FinishedAt:  now.Add(finished),
+	}
+}
+
+// testRecords 종료 시각 순 배포 기록
+func testRecords() []Record {
+	day := 24 * time.Hour
+
+	old := deploy("dep-0", "homepage-front", deployment.StatusSucceeded, -10*day)
+	succeeded := deploy("dep-1", "homepage-front", deployment.StatusSucceeded, -6*day)
+	succeeded.CommittedAt = succeeded.FinishedAt.Add(-3 * time.Hour)
+	rejected := deploy("dep-2", "homepage-front", deployment.StatusRejected, -5*day)
+	// Sync 요청 전에 실패한 배포 (이미지 반영 실패 등)
+	notSynced := deploy("dep-3", "homepage-front", deployment.StatusFailed, -5*day+time.Hour)
+	failed := deploy("dep-4", "homepage-front", deployment.StatusFailed, -4*day)
+	failed.SyncedAt = failed.FinishedAt.Add(-10 * time.Minute)
+	restored := deploy("dep-5", "homepage-front", deployment.StatusApproved, -4*day+2*time.Hour)
+	restored.CommittedAt = restored.FinishedAt.Add(-time.Hour)
+	rolledBack := deploy("dep-6", "homepage-front", deployment.StatusSucceeded, -2*day)
+	rolledBack.CommittedAt = rolledBack.FinishedAt.Add(-5 * time.Hour)
+	rollback := deploy("dep-7", "homepage-front", deployment.StatusSucceeded, -2*day+30*time.Minute)
+	rollback.Action, rollback.RollbackOf = deployment.ActionRollback, "dep-6"
+	unresolved := deploy("dep-8", "cms-front", deployment.StatusFailed, -day)
+	unresolved.SyncedAt = unresolved.FinishedAt.Add(-time.Minute)
+	dev := deploy("dep-9", "homepage-front", deployment.StatusSucceeded, -time.Hour)
+	dev.Environment = "dev"
+	future := deploy("dep-10", "homepage-front", deployment.StatusSucceeded, time.Hour)
+
+	return []Record{old, succeeded, rejected, notSynced, failed, restored, rolledBack, rollback, unresolved, dev, future}
+}
+
+func seconds(d time.Duration) float64 { return d.Seconds() }
+
+func TestCompute(t *testing.T) {
+	m := compute(testRecords(), Filter{Environment: "prod", Window: 7 * 24 * time.Hour, Now: now})
+
+	if m.Window != "7d" || !m.From.Equal(now.Add(-7*24*time.Hour)) || !m.To.Equal(now) {
+		t.Errorf("window = %s (%s ~ %s)", m.Window, m.From, m.To)
+	}
+	// 기간 밖(dep-0, dep-10), 다른 환경(dep-9), 반려(dep-2)된 배포는 제외한다.
+	if got := m.DeploymentFrequency; got.Deployments != 3 || got.PerDay != 3.0/7 {
+		t.Errorf("deployment_frequency = %+v, want 3 deployments", got)
+	}
+	if got := m.LeadTime; got.Samples != 3 || got.MedianSeconds == nil || *got.MedianSeconds != seconds(3*time.Hour) {
+		t.Errorf("lead_time = %+v, want median 3h of 3 samples", got)
+	}
+	// Sync 요청 전에 실패한 배포(dep-3)는 제외하고, 롤백된 배포(dep-6)는 실패로 집계한다.
+	if got := m.ChangeFailureRate; got.Failures != 3 || got.Deployments != 5 || got.Rate != 0.6 {
+		t.Errorf("change_failure_rate = %+v, want 3/5", got)
+	}
+	// dep-4는 dep-5(2h), dep-6은 롤백(30m)으로 복구되었으며 dep-8은 복구되지 않았다.
+	if got := m.TimeToRestore; got.Samples != 2 || got.Unresolved != 1 || got.MedianSeconds == nil || *got.MedianSeconds != seconds(75*time.Minute) {
+		t.Errorf("time_to_restore = %+v, want median 75m of 2 samples, 1 unresolved", got)
+	}
+}
+
+func TestComputeFilter(t *testing.T) {
+	cases := []struct {
+		name        string
+		filter      Filter
+		deployments int
+		failures    int
+	}{
+		{name: "application", filter: Filter{Application: "cms-front"}, deployments: 1, failures: 1},
+		{name: "team", filter: Filter{Team: "platform", Environment: "prod"}, deployments: 5, failures: 3},
+		{name: "other team", filter: Filter{Team: "data"}},
+		{name: "all environments", filter: Filter{}, deployments: 6, failures: 3},
+		{name: "short window", filter: Filter{Window: 3 * 24 * time.Hour}, deployments: 3, failures: 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.filter.Now = now
+			if tc.filter.Window == 0 {
+				tc.filter.Window = 7 * 24 * time.Hour
+			}
+			m := compute(testRecords(), tc.filter)
+			if got := m.ChangeFailureRate; got.Deployments != tc.deployments || got.Failures != tc.failures {
+				t.Errorf("change_failure_rate = %+v, want %d/%d", got, tc.failures, tc.deployments)
+			}
+		})
+	}
+}
+
+func TestComputeEmpty(t *testing.T) {
+	m := compute(nil, Filter{Window: 30 * 24 * time.Hour, Now: now})
+	if m.ChangeFailureRate.Rate != 0 || m.DeploymentFrequency.PerDay != 0 || m.LeadTime.MedianSeconds != nil || m.TimeToRestore.MedianSeconds != nil {
+		t.Errorf("metrics = %+v, want zero values", m)
+	}
+}
+
+func TestRestoredAt(t *testing.T) {
+	failed := deploy("dep-1", "homepage-front", deployment.StatusFailed, -3*time.Hour)
+	otherApp := deploy("dep-2", "cms-front", deployment.StatusSucceeded, -2*time.Hour)
+	otherEnv := deploy("dep-3", "homepage-front", deployment.StatusSucceeded, -2*time.Hour)
+	otherEnv.Environment = "dev"
+	failedAgain := deploy("dep-4", "homepage-front", deployment.StatusFailed, -90*time.Minute)
+	restored := deploy("dep-5", "homepage-front", deployment.StatusSucceeded, -time.Hour)
+	later := deploy("dep-6", "homepage-front", deployment.StatusSucceeded, -30*time.Minute)
+
+	got, ok := restoredAt([]Record{otherApp, otherEnv, failedAgain, restored, later}, failed)
+	if !ok || !got.Equal(restored.FinishedAt) {
+		t.Errorf("restoredAt = %s (%t), want %s", got, ok, restored.FinishedAt)
+	}
+	if _, ok := restoredAt([]Record{otherApp, otherEnv, failedAgain}, failed); ok {
+		t.Error("restoredAt found without successful deployment")
+	}
+}
+
+func TestDurationStat(t *testing.T) {
+	cases := []struct {
+		name    string
+		samples []time.Duration
+		want    *float64
+	}{
+		{name: "empty"},
+		{name: "single", samples: []time.Duration{time.Minute}, want: ptr(60)},
+		{name: "odd", samples: []time.Duration{3 * time.Second, time.Second, 2 * time.Second}, want: ptr(2)},
+		// 짝수 개인 경우 가운데 두 값의 평균
+		{name: "even", samples: []time.Duration{4 * time.Second, time.Second, 3 * time.Second, 2 * time.Second}, want: ptr(2.5)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := durationStat(tc.samples)
+			if got.Samples != len(tc.samples) {
+				t.Errorf("samples = %d, want %d", got.Samples, len(tc.samples))
+			}
+			if (got.MedianSeconds == nil) != (tc.want == nil) || (tc.want != nil && *got.MedianSeconds != *tc.want) {
+				t.Errorf("median = %v, want %v", got.MedianSeconds, tc.want)
+			}
+		})
+	}
+}
+
+func ptr(v float64) *float64 { return &v }
@@ -0,0 +1,214 @@
+// Package dora 배포 기록 기반 DORA 지표 (배포 빈도, 변경 리드 타임, 변경 실패율, 서비스 복구 시간)
+package dora
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/rs/zerolog/log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const defaultRetention = 90 * 24 * time.Hour
+
+// Record DORA 지표 계산에 사용하는 종료된 배포 기록
+type Record struct {
+	ID          string            `json:"id"`
+	Action      deployment.Action `json:"action"`
+	Application string            `json:"application"`
+	Environment string            `json:"environment"`
+	Team        string            `json:"team,omitempty"`
+	Status      deployment.Status `json:"status"`
+	// 커밋 시각 (변경 리드 타임 기준)
+	CommittedAt time.Time `json:"committed_at,omitzero"`
+	StartedAt   time.Time `json:"started_at,omitzero"`
+	FinishedAt  time.Time `json:"finished_at"`
+	// ArgoCD Sync 요청 시각. 비어있는 실패한 배포는 운영에 반영되지 않은 배포다.
+	SyncedAt time.Time `json:"synced_at,omitzero"`
+	// Preview 서비스 Health Check 실패 여부
+	HealthCheckFailed bool `json:"health_check_failed,omitempty"`
+	// 롤백 대상 배포 ID (rollback)
+	RollbackOf string `json:"rollback_of,omitempty"`
+}
+
+// Store 종료된 배포 기록 보관소. 배포 기록(Registry)보다 긴 기간의 지표를 계산하기 위해 별도 파일(JSON Lines)에 저장한다.
+type Store struct {
+	mu        sync.RWMutex
+	records   []Record
+	retention time.Duration
+	path      string
+}
+
+// Open 기록 파일에서 보관 기간 내 배포 기록을 복원한 Store 생성. 경로가 비어있는 경우 메모리에만 보관한다.
+func Open(path string, retention time.Duration) (*Store, error) {
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	s := &Store{retention: retention, path: path}
+	if path == "" {
+		return s, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("dora: failed to create history directory: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dora: failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			log.Warn().Err(err).Msgf("dora | skip invalid history record %s:%d", path, line)
+			continue
+		}
+		s.records = append(s.records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("dora: failed to read history file: %w", err)
+	}
+
+	// 보관 기간이 지난 기록을 정리해 다시 저장
+	if s.prune(time.Now()) {
+		if err := s.rewrite(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Observe 배포 종료 이벤트를 기록 (deployment.Registry.Subscribe)
+// 대체(superseded)된 배포는 실행되지 않았으므로 기록하지 않는다.
+func (s *Store) Observe(e deployment.Event) {
+	if e.Type != deployment.EventFinished || e.Deployment.Status == deployment.StatusSuperseded {
+		return
+	}
+
+	d := e.Deployment
+	s.Add(Record{
+		ID:                d.ID,
+		Action:            d.Action,
+		Application:       d.Application,
+		Environment:       d.Environment,
+		Team:              d.Team,
+		Status:            d.Status,
+		CommittedAt:       d.CommittedAt,
+		StartedAt:         d.StartedAt,
+		FinishedAt:        d.FinishedAt,
+		SyncedAt:          d.SyncStartedAt,
+		HealthCheckFailed: d.HealthCheck != nil && !d.HealthCheck.Healthy,
+		RollbackOf:        d.RollbackOf,
+	})
+}
+
+// Add 배포 기록 추가
+func (s *Store) Add(r Record) {
+	if r.FinishedAt.IsZero() {
+		r.FinishedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = append(s.records, r)
+	if s.prune(r.FinishedAt) {
+		if err := s.rewrite(); err != nil {
+			log.Error().Err(err).Msg("dora | failed to rewrite history file")
+		}
+		return
+	}
+	s.append(r)
+}
+
+// Records 보관 중인 배포 기록 (종료 시각 순)
+func (s *Store) Records() []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Record(nil), s.records...)
+}
+
+// prune 보관 기간이 지난 기록 정리 (s.mu 잠금 상태에서 호출). 기록 파일은 하루 단위로만 다시 저장한다.
+func (s *Store) prune(now time.Time) bool {
+	cutoff := now.Add(-s.retention)
+	n := 0
+	for n < len(s.records) && s.records[n].FinishedAt.Before(cutoff.Add(-24*time.Hour)) {
+		n++
+	}
+	if n == 0 {
+		return false
+	}
+	for n < len(s.records) && s.records[n].FinishedAt.Before(cutoff) {
+		n++
+	}
+	s.records = append([]Record(nil), s.records[n:]...)
+	return true
+}
+
+func (s *Store) append(r Record) {
+	if s.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		log.Error().Err(err).Msg("dora | failed to encode history record")
+		return
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		log.Error().Err(err).Msgf("dora | failed to open history file: %s", s.path)
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		log.Error().Err(err).Msgf("dora | failed to write history file: %s", s.path)
+	}
+}
+
+// rewrite 기록 파일 전체를 임시 파일에 기록한 뒤 교체
+func (s *Store) rewrite() error {
+	if s.path == "" {
+		return nil
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("dora: failed to create history file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	for _, r := range s.records {
+		if err := encoder.Encode(r); err != nil {
+			f.Close()
+			return fmt.Errorf("dora: failed to encode history record: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("dora: failed to write history file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("dora: failed to write history file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("dora: failed to replace history file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package dora
+
+import (
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"testing"
+	"time"
+)
+
+func TestObserve(t *testing.T) {
+	s, err := Open("", 0)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	synced := now.Add(-time.Minute)
+	s.Observe(deployment.Event{Type: deployment.EventFinished, Deployment: deployment.Deployment{ID: "dep-1", Action: deployment.ActionDeploy, Status: deployment.StatusFailed, SyncStartedAt: synced, FinishedAt: now}})
+	// 종료되지 않았거나 대체(superseded)된 배포는 기록하지 않는다.
+	s.Observe(deployment.Event{Type: deployment.EventStarted, Deployment: deployment.Deployment{ID: "dep-2", FinishedAt: now}})
+	s.Observe(deployment.Event{Type: deployment.EventFinished, Deployment: deployment.Deployment{ID: "dep-3", Status: deployment.StatusSuperseded, FinishedAt: now}})
+
+	records := s.Records()
+	if len(records) != 1 || records[0].ID != "dep-1" || !records[0].SyncedAt.Equal(synced) || !records[0].failedAfterSync() {
+		t.Errorf("records = %+v, want dep-1 failed after sync", records)
+	}
+}
@@ -0,0 +1,48 @@
+package dora
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule 주간 보고 시각 (요일 및 시각, time.Local 기준)
+type Schedule struct {
+	Weekday time.Weekday
+	Hour    int
+	Minute  int
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseSchedule 주간 보고 시각 (예: "mon 09:00")
+func ParseSchedule(s string) (Schedule, error) {
+	day, clock, found := strings.Cut(strings.TrimSpace(strings.ToLower(s)), " ")
+	weekday, exist := weekdays[day]
+	if !found || !exist {
+		return Schedule{}, fmt.Errorf("dora: invalid schedule %q (e.g. mon 09:00)", s)
+	}
+
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return Schedule{}, fmt.Errorf("dora: invalid schedule %q (e.g. mon 09:00)", s)
+	}
+	return Schedule{Weekday: weekday, Hour: t.Hour(), Minute: t.Minute()}, nil
+}
+
+// Next after 이후 가장 가까운 보고 시각
+func (s Schedule) Next(after time.Time) time.Time {
+	next := time.Date(after.Year(), after.Month(), after.Day(), s.Hour, s.Minute, 0, 0, after.Location())
+	next = next.AddDate(0, 0, (int(s.Weekday)-int(next.Weekday())+7)%7)
+	if !next.After(after) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+func (s Schedule) String() string {
+	return fmt.Sprintf("%s %02d:%02d", s.Weekday.String()[:3], s.Hour, s.Minute)
+}
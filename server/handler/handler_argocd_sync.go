@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"github.com/rs/zerolog/log"
+	"strings"
+	"time"
+)
+
+const defaultHelmImageParameter = "image.tag"
+
+// syncOperationError ArgoCD Sync Operation 혹은 Application Health 실패
+type syncOperationError struct {
+	Phase     string
+	Health    string
+	Message   string
+	Resources []string
+}
+
+func (e *syncOperationError) Error() string {
+	msg := fmt.Sprintf("sync operation %s (health: %s): %s", e.Phase, e.Health, e.Message)
+	if len(e.Resources) > 0 {
+		msg = fmt.Sprintf("%s [%s]", msg, strings.Join(e.Resources, "; "))
+	}
+	return msg
+}
+
+// overrideApplicationImage 배포 요청된 DockerTag를 ArgoCD Application의 kustomize images 혹은 Helm parameter로 설정
+func overrideApplicationImage(ctx context.Context, client *argocd.Client, appName, tag string, cfg config.SyncConfig) error {
+	app, err := client.GetApplication(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("overrideApplicationImage | failed to get application: %w", err)
+	}
+
+	if app.Spec.Source == nil {
+		return errors.New("overrideApplicationImage | application has no single source (spec.source)")
+	}
+
+	var source map[string]any
+	switch cfg.ImageOverride {
+	case "kustomize":
+		var images []string
+		if app.Spec.Source.Kustomize != nil {
+			images = app.Spec.Source.Kustomize.Images
+		}
+		source = map[string]any{
+			"kustomize": map[string]any{"images": upsertKustomizeImage(images, cfg.Image, tag)},
+		}
+	case "helm":
+		var parameters []argocd.HelmParameter
+		if app.Spec.Source.Helm != nil {
+			parameters = app.Spec.Source.Helm.Parameters
+		}
+		source = map[string]any{
+			"helm": map[string]any{"parameters": upsertHelmParameter(parameters, helmImageParameter(cfg), tag)},
+		}
+	default:
+		return fmt.Errorf("overrideApplicationImage | unknown image override: %s", cfg.ImageOverride)
+	}
+
+	patch, err := json.Marshal(map[string]any{"spec": map[string]any{"source": source}})
+	if err != nil {
+		return fmt.Errorf("overrideApplicationImage | failed to marshal patch: %w", err)
+	}
+
+	if _, err := client.PatchApplication(ctx, appName, patch, "merge"); err != nil {
+		return fmt.Errorf("overrideApplicationImage | failed to patch application: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Msgf("overrideApplicationImage | %s image override applied - Application: %s, Tag: %s", cfg.ImageOverride, appName, tag)
+	return nil
+}
+
+// latestHistoryID Application 최신 배포 이력 ID (배포 이력이 없거나 조회 실패 시 nil)
+func latestHistoryID(ctx context.Context, client *argocd.Client, appName string) *int64 {
+	app, err := client.GetApplication(ctx, appName)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("latestHistoryID | failed to get application: %s", appName)
+		return nil
+	}
+	if len(app.Status.History) == 0 {
+		return nil
+	}
+	id := app.Status.History[len(app.Status.History)-1].ID
+	return &id
+}
+
+// syncApplication ArgoCD Application Sync 요청
+func syncApplication(ctx context.Context, client *argocd.Client, appName string, cfg config.SyncConfig) error {
+	req := argocd.SyncRequest{
+		Revision: cfg.Revision,
+		Prune:    cfg.Prune,
+		DryRun:   cfg.DryRun,
+	}
+	for _, r := range cfg.Resources {
+		req.Resources = append(req.Resources, argocd.SyncResource{Group: r.Group, Kind: r.Kind, Name: r.Name, Namespace: r.Namespace})
+	}
+	if len(cfg.SyncOptions) > 0 {
+		req.SyncOptions = &argocd.SyncOptions{Items: cfg.SyncOptions}
+	}
+
+	if _, err := client.Sync(ctx, appName, req); err != nil {
+		return fmt.Errorf("syncApplication | failed to sync application: %w", err)
+	}
+	return nil
+}
+
+// waitForSyncOperation Sync Operation 종료 및 Application Health 안정화 대기
+// Rollout이 승인 대기(Suspended) 상태인 경우도 정상으로 판단한다. Dry Run Sync는 Operation 결과만 확인한다.
+func waitForSyncOperation(ctx context.Context, client *argocd.Client, appName string, startedAfter time.Time, cfg config.SyncConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.WaitTimeout)
+	defer cancel()
+
+	// ArgoCD 시각은 초 단위이므로 요청 시각을 초 단위로 절삭해 비교
+	startedAfter = startedAfter.Truncate(time.Second)
+	phase, health := "Unknown", "Unknown"
+
+	for {
+		app, err := client.GetApplication(ctx, appName)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("waitForSyncOperation | failed to get application: %s", appName)
+		} else if op := app.Status.OperationState; op != nil && !op.StartedAt.Before(startedAfter) {
+			phase, health = op.Phase, app.Status.Health.Status
+
+			switch op.Phase {
+			case "Failed", "Error":
+				return newSyncOperationError(app, op)
+			case "Succeeded":
+				if cfg.DryRun {
+					return nil
+				}
+
+				switch health {
+				case "Healthy", "Suspended":
+					log.Ctx(ctx).Info().Msgf("waitForSyncOperation | sync operation succeeded - Application: %s, Health: %s", appName, health)
+					return nil
+				case "Degraded", "Missing":
+					return newSyncOperationError(app, op)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+				return &syncOperationError{
+					Phase:   phase,
+					Health:  health,
+					Message: fmt.Sprintf("timed out after %s waiting for sync operation and application health", cfg.WaitTimeout),
+				}
+			}
+			return context.Cause(ctx)
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func newSyncOperationError(app *argocd.Application, op *argocd.OperationState) *syncOperationError {
+	e := &syncOperationError{
+		Phase:   op.Phase,
+		Health:  app.Status.Health.Status,
+		Message: op.Message,
+	}
+	if e.Message == "" {
+		e.Message = app.Status.Health.Message
+	}
+
+	if op.SyncResult != nil {
+		for _, r := range op.SyncResult.Resources {
+			if r.Failed() {
+				e.Resources = append(e.Resources, fmt.Sprintf("%s/%s: %s", r.Kind, r.Name, r.Message))
+			}
+		}
+	}
+	return e
+}
+
+// verifyLiveImage Sync 이후 Application에 배포 요청된 이미지 태그가 반영되었는지 확인
+func verifyLiveImage(ctx context.Context, client *argocd.Client, appName, tag string, cfg config.SyncConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.VerifyTimeout)
+	defer cancel()
+
+	var images []string
+	for {
+		app, err := client.GetApplication(ctx, appName)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("verifyLiveImage | failed to get application: %s", appName)
+		} else {
+			images = app.Status.Summary.Images
+			if containsImageTag(images, cfg.Image, tag) {
+				log.Ctx(ctx).Info().Msgf("verifyLiveImage | live image matched - Application: %s, Tag: %s", appName, tag)
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+				return fmt.Errorf("verifyLiveImage | live image does not match tag %s: %v", tag, images)
+			}
+			return context.Cause(ctx)
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func helmImageParameter(cfg config.SyncConfig) string {
+	if cfg.HelmParameter != "" {
+		return cfg.HelmParameter
+	}
+	return defaultHelmImageParameter
+}
+
+// upsertKustomizeImage kustomize images 목록에서 같은 이미지 항목을 image:tag로 교체하거나 추가
+func upsertKustomizeImage(images []string, image, tag string) []string {
+	entry := fmt.Sprintf("%s:%s", image, tag)
+	result := make([]string, 0, len(images)+1)
+	replaced := false
+
+	for _, i := range images {
+		if kustomizeImageName(i) == image {
+			if !replaced {
+				result = append(result, entry)
+				replaced = true
+			}
+			continue
+		}
+		result = append(result, i)
+	}
+
+	if !replaced {
+		result = append(result, entry)
+	}
+	return result
+}
+
+func upsertHelmParameter(parameters []argocd.HelmParameter, name, value string) []argocd.HelmParameter {
+	result := make([]argocd.HelmParameter, 0, len(parameters)+1)
+	for _, p := range parameters {
+		if p.Name != name {
+			result = append(result, p)
+		}
+	}
+	return append(result, argocd.HelmParameter{Name: name, Value: value, ForceString: true})
+}
+
+// kustomizeImageName "name=newName:tag", "name:tag", "name@digest" 형식에서 이미지 이름 추출
+func kustomizeImageName(entry string) string {
+	if i := strings.Index(entry, "="); i >= 0 {
+		return entry[:i]
+	}
+	if i := strings.Index(entry, "@"); i >= 0 {
+		entry = entry[:i]
+	}
+	if i := strings.LastIndex(entry, ":"); i > strings.LastIndex(entry, "/") {
+		entry = entry[:i]
+	}
+	return entry
+}
+
+// containsImageTag Live 이미지 목록에 배포 요청 태그가 포함되어 있는지 확인 (image 미지정 시 태그만 비교)
+func containsImageTag(images []string, image, tag string) bool {
+	for _, i := range images {
+		if image != "" && kustomizeImageName(i) != image {
+			continue
+		}
+		if strings.HasSuffix(i, ":"+tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/analysis"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"github.com/antonio-kim-1994/devops-relay/server/rollouts"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"strings"
+	"time"
+)
+
+// canaryAnalysisTimeout Canary 분석 전체 조회 제한 시간
+const canaryAnalysisTimeout = time.Minute
+
+// runCanaryAnalysis 승인 요청 전 preview/stable 메트릭 쿼리를 조회해 기준값과 비교
+func runCanaryAnalysis(ctx context.Context, s ServiceInfo, cfg config.AnalysisConfig) analysis.Result {
+	provider, exist := metricProviders[cfg.Provider]
+	if !exist {
+		log.Ctx(ctx).Error().Msgf("runCanaryAnalysis | metric provider %s is not configured: %s", cfg.Provider, s.ApplicationName)
+		return analysis.Result{
+			Provider: cfg.Provider,
+			Checks:   []analysis.Check{{Name: cfg.Provider, Error: "metric provider is not configured"}},
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, canaryAnalysisTimeout)
+	defer cancel()
+
+	result := analysis.Run(ctx, provider, cfg, analysis.Vars{
+		Application: s.ApplicationName,
+		Namespace:   s.ApplicationNamespace,
+		Environment: s.Branch,
+		DockerTag:   s.DockerTag,
+	})
+	log.Ctx(ctx).Info().Msgf("runCanaryAnalysis | canary analysis of %s (%s) passed: %t", s.ApplicationName, s.DockerTag, result.Passed())
+	return result
+}
+
+// abortFailedCanary Canary 분석 실패로 승인 요청을 차단한 경우 Rollout을 중단해 stable로 복구
+func abortFailedCanary(ctx context.Context, argo *argocd.Instance, s ServiceInfo) error {
+	target, err := resolveRollout(ctx, argo, s.ApplicationName, s.ApplicationNamespace)
+	if err != nil {
+		return err
+	}
+	return runRolloutAction(ctx, argo, target, rollouts.ActionAbort, 0, automationActor, s.Branch, "canary analysis failed")
+}
+
+// analysisTableBlock Canary 분석 결과 표 (쿼리, preview, stable, 기준, 결과)
+func analysisTableBlock(result analysis.Result) slack.Block {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-20s %12s %12s  %-22s %s\n", "QUERY", "PREVIEW", "STABLE", "THRESHOLD", "RESULT")
+	for _, c := range result.Checks {
+		value, baseline, status := fmt.Sprintf("%.4g", c.Value), "-", "PASS"
+		if c.Baseline != nil {
+			baseline = fmt.Sprintf("%.4g", *c.Baseline)
+		}
+		if !c.Passed {
+			status = "FAIL"
+		}
+		if c.Error != "" {
+			value, status = "-", "ERROR"
+		}
+		fmt.Fprintf(&b, "%-20s %12s %12s  %-22s %s\n", c.Name, value, baseline, c.Threshold, status)
+	}
+
+	title := fmt.Sprintf(":white_check_mark: *Canary 분석 통과* (%s)", result.Provider)
+	if !result.Passed() {
+		title = fmt.Sprintf(":x: *Canary 분석 실패* (%s)", result.Provider)
+	}
+
+	text := fmt.Sprintf("%s\n```%s```", title, b.String())
+	for _, c := range result.Checks {
+		if c.Error != "" {
+			text += fmt.Sprintf("\n> `%s`: %s", c.Name, c.Error)
+		}
+	}
+	return slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil)
+}
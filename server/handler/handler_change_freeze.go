@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/audit"
+	"github.com/antonio-kim-1994/devops-relay/server/calendar"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+const (
+	freezeTimeLayout = "2006-01-02 15:04 MST"
+	// breakGlassHeader break-glass 인증 토큰 헤더 (BREAK_GLASS_TOKEN)
+	breakGlassHeader = "Break-Glass-Auth"
+)
+
+// verifyBreakGlassToken break-glass 인증 토큰 확인
+// operator는 요청 본문의 값이므로 관리자 여부와 별도로 관리자에게만 발급한 토큰을 확인한다.
+func verifyBreakGlassToken(token string) bool {
+	expected := relayConfig.BreakGlassToken
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// checkDeployFreeze 배포 동결 기간 확인
+// 동결 기간인 경우 break-glass 인증 토큰을 확인한 관리자 요청만 허용하며, 차단 사유를 반환한다.
+func checkDeployFreeze(s ServiceInfo, verified bool) (*calendar.Freeze, string) {
+	freeze := changeCalendar.ActiveFreeze(s.Branch, s.ApplicationName, time.Now())
+	if freeze == nil {
+		return nil, ""
+	}
+
+	window := fmt.Sprintf("%s (%s ~ %s)", freeze.Window.Name, freeze.Start.Format(freezeTimeLayout), freeze.End.Format(freezeTimeLayout))
+
+	if !s.BreakGlass {
+		return freeze, fmt.Sprintf("deploy blocked by freeze window %s: %s", window, freeze.Window.Reason)
+	}
+
+	if !verified {
+		log.Warn().Msgf("checkDeployFreeze | break-glass requested without valid %s token: %s", breakGlassHeader, s.Operator)
+		return freeze, fmt.Sprintf("deploy blocked by freeze window %s: break-glass override requires a valid break-glass token", window)
+	}
+
+	if !relayConfig.IsAdmin(s.Operator) {
+		log.Warn().Msgf("checkDeployFreeze | break-glass requested by non-admin user: %s", s.Operator)
+		return freeze, fmt.Sprintf("deploy blocked by freeze window %s: break-glass override requires an admin (requested by %s)", window, s.Operator)
+	}
+
+	if s.BreakGlassReason == "" {
+		return freeze, fmt.Sprintf("deploy blocked by freeze window %s: break-glass override requires a reason", window)
+	}
+
+	err := audit.Record(audit.Entry{
+		Action:      "freeze.break_glass",
+		Actor:       s.Operator,
+		Application: s.ApplicationName,
+		Environment: s.Branch,
+		Reason:      s.BreakGlassReason,
+		Detail: map[string]string{
+			"window":        freeze.Window.Name,
+			"repo":          fmt.Sprintf("%s/%s", s.Org, s.Repo),
+			"docker_tag":    s.DockerTag,
+			"deployment_id": s.DeploymentID,
+		},
+	})
+	if err != nil {
+		// 감사 기록이 남지 않는 긴급 배포는 허용하지 않는다.
+		log.Error().Err(err).Msg("checkDeployFreeze | failed to record break-glass audit entry")
+		return freeze, fmt.Sprintf("deploy blocked by freeze window %s: failed to record break-glass audit entry", window)
+	}
+
+	log.Warn().Msgf("checkDeployFreeze | break-glass override by %s during freeze window %s", s.Operator, window)
+	return nil, ""
+}
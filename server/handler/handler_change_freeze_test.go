@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/antonio-kim-1994/devops-relay/server/audit"
+	"github.com/antonio-kim-1994/devops-relay/server/calendar"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupFreeze 운영 환경 전체에 항상 적용되는 동결 기간과 break-glass 설정
+func setupFreeze(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "change_calendar.yaml")
+	content := `
+windows:
+  - name: always
+    reason: 테스트 동결 기간
+    environments: [prod]
+    recurrence: {type: daily, start_time: "00:00", duration: 24h}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	c, err := calendar.Load(path, time.UTC)
+	if err != nil {
+		t.Fatalf("calendar.Load: %v", err)
+	}
+
+	auditPath := filepath.Join(dir, "audit", "audit.jsonl")
+	if err := audit.Setting(auditPath); err != nil {
+		t.Fatalf("audit.Setting: %v", err)
+	}
+
+	prevCalendar, prevConfig := changeCalendar, relayConfig
+	changeCalendar = c
+	relayConfig = &config.Config{Admins: []string{"devops-admin"}, BreakGlassToken: "break-glass-token"}
+	t.Cleanup(func() {
+		changeCalendar, relayConfig = prevCalendar, prevConfig
+		_ = audit.Setting("")
+	})
+	return auditPath
+}
+
+func TestVerifyBreakGlassToken(t *testing.T) {
+	setupFreeze(t)
+
+	cases := []struct {
+		name     string
+		token    string
+		expected string
+		want     bool
+	}{
+		{name: "valid", token: "break-glass-token", expected: "break-glass-token", want: true},
+		{name: "invalid", token: "guess", expected: "break-glass-token", want: false},
+		{name: "missing", token: "", expected: "break-glass-token", want: false},
+		// BREAK_GLASS_TOKEN 미설정 시 빈 토큰으로 통과할 수 없다.
+		{name: "not configured", token: "", expected: "", want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			relayConfig.BreakGlassToken = tc.expected
+			if got := verifyBreakGlassToken(tc.token); got != tc.want {
+				t.Errorf("verifyBreakGlassToken(%q) = %t, want %t", tc.token, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheckDeployFreeze(t *testing.T) {
+	cases := []struct {
+		name     string
+		service  ServiceInfo
+		verified bool
+		blocked  string
+	}{
+		{
+			name:    "not frozen environment",
+			service: ServiceInfo{Branch: "dev", ApplicationName: "homepage-front", Operator: "dev-user"},
+		},
+		{
+			name:    "frozen",
+			service: ServiceInfo{Branch: "prod", ApplicationName: "homepage-front", Operator: "devops-admin"},
+			blocked: "테스트 동결 기간",
+		},
+		{
+			// 요청 본문의 operator가 관리자여도 토큰이 없으면 차단한다.
+			name:    "break-glass without token",
+			service: ServiceInfo{Branch: "prod", ApplicationName: "homepage-front", Operator: "devops-admin", BreakGlass: true, BreakGlassReason: "장애 대응"},
+			blocked: "requires a valid break-glass token",
+		},
+		{
+			name:     "break-glass by non-admin",
+			service:  ServiceInfo{Branch: "prod", ApplicationName: "homepage-front", Operator: "dev-user", BreakGlass: true, BreakGlassReason: "장애 대응"},
+			verified: true,
+			blocked:  "requires an admin (requested by dev-user)",
+		},
+		{
+			name:     "break-glass without reason",
+			service:  ServiceInfo{Branch: "prod", ApplicationName: "homepage-front", Operator: "devops-admin", BreakGlass: true},
+			verified: true,
+			blocked:  "requires a reason",
+		},
+		{
+			name:     "break-glass",
+			service:  ServiceInfo{Branch: "prod", ApplicationName: "homepage-front", Operator: "DevOps-Admin", BreakGlass: true, BreakGlassReason: "장애 대응", DeploymentID: "dep-1"},
+			verified: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			auditPath := setupFreeze(t)
+
+			freeze, reason := checkDeployFreeze(tc.service, tc.verified)
+			if tc.blocked == "" {
+				if freeze != nil {
+					t.Fatalf("blocked: %s", reason)
+				}
+			} else if freeze == nil || !strings.Contains(reason, tc.blocked) {
+				t.Fatalf("freeze = %+v, reason = %q, want %q", freeze, reason, tc.blocked)
+			}
+
+			// 허용된 break-glass 배포만 감사 로그를 남긴다.
+			data, _ := os.ReadFile(auditPath)
+			if !tc.service.BreakGlass || tc.blocked != "" {
+				if len(data) != 0 {
+					t.Errorf("audit = %s, want none", data)
+				}
+				return
+			}
+			var entry audit.Entry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				t.Fatalf("audit entry %q: %v", data, err)
+			}
+			if entry.Action != "freeze.break_glass" || entry.Actor != "DevOps-Admin" || entry.Reason != "장애 대응" ||
+				entry.Detail["window"] != "always" || entry.Detail["deployment_id"] != "dep-1" {
+				t.Errorf("audit entry = %+v", entry)
+			}
+		})
+	}
+}
+
+func TestCheckDeployFreezeAuditFailure(t *testing.T) {
+	setupFreeze(t)
+	// 감사 로그 경로가 디렉토리인 경우 기록에 실패한다.
+	if err := audit.Setting(t.TempDir()); err != nil {
+		t.Fatalf("audit.Setting: %v", err)
+	}
+
+	s := ServiceInfo{Branch: "prod", ApplicationName: "homepage-front", Operator: "devops-admin", BreakGlass: true, BreakGlassReason: "장애 대응"}
+	if freeze, reason := checkDeployFreeze(s, true); freeze == nil || !strings.Contains(reason, "failed to record break-glass audit entry") {
+		t.Errorf("freeze = %+v, reason = %q, want blocked without audit entry", freeze, reason)
+	}
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/datadog"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/logging"
+	"strings"
+)
+
+// teamOf 애플리케이션 담당 팀. 애플리케이션 설정의 team을 우선 사용한다.
+func teamOf(application, requested string) string {
+	if team := applications.Get(application).Team; team != "" {
+		return team
+	}
+	return requested
+}
+
+// sendDatadogEvent 배포 lifecycle 이벤트를 Datadog Event(배포 마커) 및 배포 메트릭으로 전송 대기열에 등록
+// 배포 요청 등록 및 진행 단계 변경은 배포 마커로 전송하지 않는다.
+func sendDatadogEvent(client *datadog.Client, e deployment.Event) {
+	if e.Type == deployment.EventQueued || e.Type == deployment.EventStageChanged {
+		return
+	}
+
+	d := e.Deployment
+	tags := deploymentTags(d)
+	eventTags := append(append([]string{}, tags...), "event:"+string(e.Type), "deployment_id:"+d.ID)
+	if e.Type == deployment.EventFinished {
+		eventTags = append(eventTags, "status:"+string(d.Status))
+	}
+
+	title, alert := datadogEventTitle(e)
+	client.Event(datadog.Event{
+		Title:          title,
+		Text:           datadogEventText(e),
+		AlertType:      alert,
+		AggregationKey: d.ID,
+		Tags:           eventTags,
+		Time:           e.Time,
+	})
+
+	// 배포 ID는 메트릭 태그에서 제외 (태그 cardinality)
+	client.Metric(datadog.Metric{
+		Name:  "devops_relay.deployment.events",
+		Type:  datadog.Count,
+		Value: 1,
+		Tags:  append(append([]string{}, tags...), "event:"+string(e.Type)),
+		Time:  e.Time,
+	})
+
+	switch e.Type {
+	case deployment.EventApproved:
+		if !d.ApprovalRequestedAt.IsZero() {
+			client.Metric(datadog.Metric{
+				Name:  "devops_relay.deployment.approval_wait",
+				Type:  datadog.Gauge,
+				Value: d.ApprovedAt.Sub(d.ApprovalRequestedAt).Seconds(),
+				Tags:  tags,
+				Time:  e.Time,
+			})
+		}
+	case deployment.EventFinished:
+		outcome := append(append([]string{}, tags...), "status:"+string(d.Status))
+		client.Metric(datadog.Metric{
+			Name:  "devops_relay.deployment.count",
+			Type:  datadog.Count,
+			Value: 1,
+			Tags:  outcome,
+			Time:  e.Time,
+		})
+
+		// 배포 잠금 대기 시간을 제외한 파이프라인 시작부터 종료까지의 시간
+		if !d.StartedAt.IsZero() {
+			client.Metric(datadog.Metric{
+				Name:  "devops_relay.deployment.duration",
+				Type:  datadog.Gauge,
+				Value: d.FinishedAt.Sub(d.StartedAt).Seconds(),
+				Tags:  outcome,
+				Time:  e.Time,
+			})
+		}
+	}
+}
+
+// deploymentTags Datadog Unified Service Tagging(env, service, version) 및 배포 정보 태그
+func deploymentTags(d deployment.Deployment) []string {
+	tags := []string{
+		"env:" + d.Environment,
+		"service:" + d.Application,
+		"action:" + string(d.Action),
+	}
+	if version := deploymentVersion(d); version != "" {
+		tags = append(tags, "version:"+version)
+	}
+	if d.Team != "" {
+		tags = append(tags, "team:"+d.Team)
+	}
+	if d.Org != "" {
+		tags = append(tags, "org:"+d.Org)
+	}
+	if d.Repo != "" {
+		tags = append(tags, "repo:"+d.Repo)
+	}
+	return tags
+}
+
+// deploymentVersion 배포 이미지 태그. 롤백은 ArgoCD 배포 이력 revision을 사용한다.
+func deploymentVersion(d deployment.Deployment) string {
+	if d.DockerTag != "" {
+		return d.DockerTag
+	}
+	return d.Revision
+}
+
+func datadogEventTitle(e deployment.Event) (string, datadog.AlertType) {
+	d := e.Deployment
+	subject := fmt.Sprintf("[%s] %s %s", d.Environment, d.Application, d.Action)
+
+	switch e.Type {
+	case deployment.EventStarted:
+		return subject + " started", datadog.AlertInfo
+	case deployment.EventHealthCheckFailed:
+		return subject + " health check failed", datadog.AlertError
+	case deployment.EventApprovalRequested:
+		return subject + " awaiting approval", datadog.AlertInfo
+	case deployment.EventApproved:
+		return fmt.Sprintf("%s approved by %s", subject, d.Approver), datadog.AlertSuccess
+	}
+
+	switch d.Status {
+	case deployment.StatusSucceeded, deployment.StatusApproved:
+		return subject + " " + string(d.Status), datadog.AlertSuccess
+	case deployment.StatusRejected, deployment.StatusSuperseded:
+		return subject + " " + string(d.Status), datadog.AlertWarning
+	default:
+		return subject + " " + string(d.Status), datadog.AlertError
+	}
+}
+
+// datadogEventText 이벤트 본문 (Datadog markdown). 커밋 메시지 등 사용자 입력은 민감 정보를 마스킹한다.
+func datadogEventText(e deployment.Event) string {
+	d := e.Deployment
+	lines := []string{
+		fmt.Sprintf("**Deployment ID**: `%s`", d.ID),
+		fmt.Sprintf("**Operator**: %s", d.Operator),
+	}
+	if version := deploymentVersion(d); version != "" {
+		lines = append(lines, fmt.Sprintf("**Version**: `%s`", version))
+	}
+	if d.Stage != "" {
+		lines = append(lines, fmt.Sprintf("**Stage**: %s", d.Stage))
+	}
+	if d.RollbackOf != "" {
+		lines = append(lines, fmt.Sprintf("**Rollback of**: `%s`", d.RollbackOf))
+	}
+	if d.Approver != "" && (e.Type == deployment.EventApproved || e.Type == deployment.EventFinished) {
+		lines = append(lines, fmt.Sprintf("**Approver**: %s", d.Approver))
+	}
+	if e.Type == deployment.EventHealthCheckFailed && d.HealthCheck != nil {
+		lines = append(lines, fmt.Sprintf("**Health check**: %d attempts", len(d.HealthCheck.Attempts)))
+	}
+	if d.Error != "" {
+		lines = append(lines, fmt.Sprintf("**Error**: %s", d.Error))
+	}
+	if d.CommitMessage != "" {
+		lines = append(lines, fmt.Sprintf("**Commit**: %s", d.CommitMessage))
+	}
+	return logging.Redact("%%%\n" + strings.Join(lines, "\n") + "\n%%%")
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/analysis"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/rs/zerolog/log"
+	"slices"
+	"time"
+)
+
+// deployStages 배포 요청 파이프라인 단계 순서
+var deployStages = []deployment.Stage{
+	deployment.StageSync,
+	deployment.StageSyncWait,
+	deployment.StageVerify,
+	deployment.StageHealthCheck,
+	deployment.StageAnalysis,
+	deployment.StageApproval,
+}
+
+// deployPipeline GitHub 배포 요청 처리 (Sync, Health Check, Canary 분석, 승인 요청)
+type deployPipeline struct {
+	id   string
+	s    ServiceInfo
+	app  config.ApplicationConfig
+	argo *argocd.Instance
+	// 재개 시작 단계 (신규 배포는 빈 값)
+	resumeFrom    deployment.Stage
+	syncStartedAt time.Time
+	canary        *analysis.Result
+}
+
+// runDeployPipeline 배포 요청 파이프라인 실행. 서버 재기동으로 재개된 경우 마지막 단계부터 진행한다.
+func runDeployPipeline(ctx, _ context.Context, d deployment.Deployment) (deployment.Status, error) {
+	var s ServiceInfo
+	if err := json.Unmarshal(d.Request, &s); err != nil {
+		return deployment.StatusFailed, fmt.Errorf("runDeployPipeline | failed to decode deploy request: %w", err)
+	}
+
+	argo, err := argoInstances.Get(d.ArgoCD)
+	if err != nil {
+		return deployment.StatusFailed, fmt.Errorf("runDeployPipeline | failed to get argocd instance: %w", err)
+	}
+
+	p := deployPipeline{
+		id:            d.ID,
+		s:             s,
+		app:           applications.Get(s.ApplicationName),
+		argo:          argo,
+		resumeFrom:    d.Stage,
+		syncStartedAt: d.SyncStartedAt,
+		canary:        d.Analysis,
+	}
+	return p.run(ctx)
+}
+
+func (p *deployPipeline) run(ctx context.Context) (deployment.Status, error) {
+	s := p.s
+
+	if p.pending(deployment.StageSync) {
+		p.enter(deployment.StageSync)
+
+		// 배포 요청 DockerTag를 Application에 반영
+		// Application spec 변경은 Dry Run과 무관하게 유지되어 이후 auto-sync로 배포되므로 Dry Run인 경우 반영하지 않는다.
+		if p.app.Sync.ImageOverride != "" && p.app.Sync.DryRun {
+			log.Ctx(ctx).Info().Msgf("deployPipeline | dry-run sync skips %s image override - Application: %s, Tag: %s", p.app.Sync.ImageOverride, s.ApplicationName, s.DockerTag)
+		} else if p.app.Sync.ImageOverride != "" {
+			if err := overrideApplicationImage(ctx, p.argo.Client, s.ApplicationName, s.DockerTag, p.app.Sync); err != nil {
+				return deployment.StatusFailed, fmt.Errorf("deployPipeline | failed to override application image: %w", err)
+			}
+		}
+
+		// Sync에 실패한 배포는 ArgoCD 배포 이력이 추가되지 않으므로 롤백 대상으로 Sync 직전 배포 이력을 기록한다.
+		previous := latestHistoryID(ctx, p.argo.Client, s.ApplicationName)
+
+		startedAt := time.Now()
+		if err := syncApplication(ctx, p.argo.Client, s.ApplicationName, p.app.Sync); err != nil {
+			return deployment.StatusFailed, fmt.Errorf("deployPipeline | failed to send sync request: %w", err)
+		}
+		log.Ctx(ctx).Info().Msgf("SyncApplication | sync request to argocd succeeded - Application: %s, Namespace: %s, ArgoCD: %s", s.ApplicationName, s.ApplicationNamespace, p.argo.Name)
+
+		// Sync 요청 이후 재개 시 Sync를 다시 요청하지 않고 Operation 종료를 확인한다.
+		p.syncStartedAt = startedAt
+		deployments.Update(p.id, func(d *deployment.Deployment) {
+			d.Stage = deployment.StageSyncWait
+			d.SyncStartedAt = startedAt
+			d.PreviousHistoryID = previous
+		})
+	}
+
+	// Sync Operation 종료 및 Application Health 확인
+	if p.pending(deployment.StageSyncWait) {
+		if err := waitForSyncOperation(ctx, p.argo.Client, s.ApplicationName, p.syncStartedAt, p.app.Sync); err != nil {
+			if ctx.Err() != nil {
+				return deployment.StatusFailed, err
+			}
+			p.notifySyncFailure(ctx, err)
+			return deployment.StatusFailed, fmt.Errorf("deployPipeline | sync operation failed: %w", err)
+		}
+	}
+
+	// Dry Run Sync는 실제 리소스가 변경되지 않으므로 이후 단계를 진행하지 않는다.
+	if p.app.Sync.DryRun {
+		log.Ctx(ctx).Info().Msgf("SyncApplication | dry-run sync succeeded - Application: %s", s.ApplicationName)
+		return deployment.StatusSucceeded, nil
+	}
+
+	// Live 이미지 태그 확인
+	if p.pending(deployment.StageVerify) && p.app.Sync.ImageOverride != "" {
+		p.enter(deployment.StageVerify)
+		if err := verifyLiveImage(ctx, p.argo.Client, s.ApplicationName, s.DockerTag, p.app.Sync); err != nil {
+			return deployment.StatusFailed, fmt.Errorf("deployPipeline | live image does not match the requested docker tag: %w", err)
+		}
+	}
+
+	if p.pending(deployment.StageHealthCheck) {
+		p.enter(deployment.StageHealthCheck)
+		h := serviceHealthCheck(ctx, s.ApplicationName, s.ApplicationNamespace)
+		if ctx.Err() != nil {
+			return deployment.StatusFailed, context.Cause(ctx)
+		}
+		deployments.SetHealthCheck(p.id, h)
+		if !h.Healthy {
+			report := collectDiagnostics(ctx, p.argo, s.ApplicationName, s.ApplicationNamespace, p.id)
+			err := sendHealthCheckFailMessage(ctx, s.ApplicationName, s.SlackWebhookUrl, h, report, rollbackButton(s.Org, s.Branch, s.ApplicationName, s.ApplicationNamespace, p.id))
+			if err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("SyncApplication | failed to send health check fail message")
+			}
+			uploadDiagnostics(ctx, report, s.ApplicationName, p.id)
+			return deployment.StatusFailed, fmt.Errorf("deployPipeline | server health check failed after %d attempts", len(h.Attempts))
+		}
+	}
+
+	if s.Branch != "prod" {
+		if err := sendUpdateSuccessMessage(ctx, s, p.id); err != nil {
+			return deployment.StatusFailed, fmt.Errorf("deployPipeline | failed to send update success message: %w", err)
+		}
+		return deployment.StatusSucceeded, nil
+	}
+
+	// 메트릭 기반 Canary 분석. block 설정 시 실패한 배포는 승인 요청 없이 중단한다.
+	if p.pending(deployment.StageAnalysis) && p.app.Analysis.Enabled {
+		p.enter(deployment.StageAnalysis)
+		result := runCanaryAnalysis(ctx, s, p.app.Analysis)
+		if ctx.Err() != nil {
+			return deployment.StatusFailed, context.Cause(ctx)
+		}
+		p.canary = &result
+		deployments.Update(p.id, func(d *deployment.Deployment) { d.Analysis = &result })
+
+		if result.Abort(p.app.Analysis) {
+			p.notifyCanaryFailure(ctx, result)
+			return deployment.StatusFailed, errors.New("deployPipeline | canary analysis failed")
+		}
+	}
+
+	p.enter(deployment.StageApproval)
+	deployments.AwaitApproval(p.id)
+	if err := sendDeployRequestMessage(ctx, s, p.id, p.app.SlackChannel, p.canary); err != nil {
+		return deployment.StatusFailed, fmt.Errorf("deployPipeline | failed to send deploy request: %w", err)
+	}
+	return deployment.StatusAwaitingApproval, nil
+}
+
+// pending 재개 시작 단계 이후의 단계인지 여부
+func (p *deployPipeline) pending(stage deployment.Stage) bool {
+	return slices.Index(deployStages, stage) >= slices.Index(deployStages, p.resumeFrom)
+}
+
+// enter 진행 단계 기록
+func (p *deployPipeline) enter(stage deployment.Stage) {
+	deployments.Update(p.id, func(d *deployment.Deployment) { d.Stage = stage })
+}
+
+// notifySyncFailure Sync Operation 실패 사유 및 실패 리소스 알림
+func (p *deployPipeline) notifySyncFailure(ctx context.Context, err error) {
+	s := p.s
+	detail := fmt.Sprintf("*%s* ArgoCD Sync에 실패했습니다.\n> %v", s.ApplicationName, err)
+	var opErr *syncOperationError
+	if errors.As(err, &opErr) {
+		detail = fmt.Sprintf("*%s* ArgoCD Sync에 실패했습니다.\n> *Phase*: `%s` | *Health*: `%s`\n> %s", s.ApplicationName, opErr.Phase, opErr.Health, opErr.Message)
+		for _, r := range opErr.Resources {
+			detail += fmt.Sprintf("\n> • `%s`", r)
+		}
+	}
+
+	notifyErr := sendDeployNoticeMessage(ctx, s,
+		fmt.Sprintf(":x: *`%s` ArgoCD Sync 실패* :x:", s.Branch),
+		detail,
+		":pushpin: *배포 과정에 장애가 발생한 경우 DevOps 팀에 문의주시기 바랍니다.*",
+		rollbackButton(s.Org, s.Branch, s.ApplicationName, s.ApplicationNamespace, p.id),
+	)
+	if notifyErr != nil {
+		log.Ctx(ctx).Error().Err(notifyErr).Msg("SyncApplication | failed to send sync fail message")
+	}
+}
+
+// notifyCanaryFailure Canary 분석 실패 시 Rollout 중단 및 분석 결과 알림
+func (p *deployPipeline) notifyCanaryFailure(ctx context.Context, result analysis.Result) {
+	s := p.s
+	detail := fmt.Sprintf("*%s* Canary 메트릭 분석 기준을 통과하지 못해 배포를 중단했습니다.", s.ApplicationName)
+	if err := abortFailedCanary(ctx, p.argo, s); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("SyncApplication | failed to abort canary of %s", s.ApplicationName)
+		detail = fmt.Sprintf("*%s* Canary 메트릭 분석 기준을 통과하지 못했으나 Rollout 중단에 실패했습니다. 즉시 확인이 필요합니다.\n> %v", s.ApplicationName, err)
+	}
+
+	notifyErr := sendDeployNoticeMessage(ctx, s,
+		fmt.Sprintf(":x: *`%s` Canary 분석 실패* :x:", s.Branch),
+		detail,
+		":pushpin: *배포 과정에 장애가 발생한 경우 DevOps 팀에 문의주시기 바랍니다.*",
+		analysisTableBlock(result),
+	)
+	if notifyErr != nil {
+		log.Ctx(ctx).Error().Err(notifyErr).Msg("SyncApplication | failed to send canary analysis fail message")
+	}
+}
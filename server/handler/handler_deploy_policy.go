@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/antonio-kim-1994/devops-relay/server/policy"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// evaluateDeployPolicy ArgoCD Sync 이전 배포 정책 평가
+// operator는 요청 본문의 값이므로 break-glass 인증 토큰을 확인한 경우에만 관리자로 평가한다.
+func evaluateDeployPolicy(s ServiceInfo, verified bool) policy.Decision {
+	// 정책에서 JSON 필드명(org, docker_tag, ...)으로 접근할 수 있도록 변환
+	request := map[string]any{}
+	data, err := json.Marshal(s)
+	if err == nil {
+		err = json.Unmarshal(data, &request)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("evaluateDeployPolicy | failed to convert service info")
+	}
+
+	return policyEngine.Evaluate(policy.Input{
+		Request:     request,
+		Environment: s.Branch,
+		Requester: policy.Requester{
+			Login: s.Operator,
+			Admin: verified && relayConfig.IsAdmin(s.Operator),
+		},
+		Now: time.Now(),
+	})
+}
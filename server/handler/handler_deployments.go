@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// HandleDeploymentList 진행 중인 배포 목록 및 파이프라인 워커 상태 조회
+func HandleDeploymentList(c *gin.Context) {
+	inFlight := deployments.InFlight()
+	if inFlight == nil {
+		inFlight = []deployment.Deployment{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deployments": inFlight,
+		"pipeline":    pipelines.Stats(),
+		"status":      "success",
+	})
+}
+
+// HandleDeploymentGet 배포 기록 조회 (진행 단계, Health Check 및 Canary 분석 결과 포함)
+func HandleDeploymentGet(c *gin.Context) {
+	id := c.Param("id")
+	d, exist := deployments.Get(id)
+	if !exist {
+		c.JSON(http.StatusNotFound, gin.H{
+			"message":       "deployment not found",
+			"deployment_id": id,
+			"status":        "failed",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deployment": d,
+		"status":     "success",
+	})
+}
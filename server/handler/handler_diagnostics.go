@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/diagnostics"
+	"github.com/antonio-kim-1994/devops-relay/server/logging"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"go.opentelemetry.io/otel/attribute"
+	"text/template"
+	"time"
+)
+
+// diagnosticsTimeout 진단 정보 수집 제한 시간
+const diagnosticsTimeout = 30 * time.Second
+
+// collectDiagnostics Health Check 실패 시 Preview ReplicaSet의 Pod 상태, 이벤트, 컨테이너 로그 수집
+// 수집한 정보는 배포 기록에 저장하며, 비활성화되었거나 수집에 실패한 경우 nil을 반환한다.
+func collectDiagnostics(ctx context.Context, argo *argocd.Instance, appName, namespace, deploymentID string) *diagnostics.Report {
+	cfg := applications.Get(appName).Diagnostics
+	collector, exist := diagnosticCollectors[argo.Name]
+	if !cfg.Enabled || !exist {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, diagnosticsTimeout)
+	defer cancel()
+
+	target, err := diagnosticTarget(ctx, argo, appName, namespace, cfg)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("collectDiagnostics | failed to resolve diagnostic target of %s", appName)
+		return nil
+	}
+
+	report, err := collector.Collect(ctx, target, diagnostics.Options{LogLines: cfg.LogLines, Events: cfg.Events})
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("collectDiagnostics | failed to collect diagnostics of %s", appName)
+		return nil
+	}
+	// 컨테이너 로그, 이벤트에 포함된 토큰 등 비밀 정보는 배포 기록 저장 및 Slack 전송 전에 마스킹한다.
+	report.Redact(logging.Redact)
+
+	log.Ctx(ctx).Info().Msgf("collectDiagnostics | collected diagnostics of %s: %d pods, %d events (%s)", appName, len(report.Pods), len(report.Events), target.Selector)
+	if deploymentID != "" {
+		deployments.Update(deploymentID, func(d *deployment.Deployment) { d.Diagnostics = report })
+	}
+	return report
+}
+
+// diagnosticTarget Rollout의 현재(Preview) ReplicaSet Pod. Rollout을 조회할 수 없는 경우 diagnostics.selector를 사용한다.
+func diagnosticTarget(ctx context.Context, argo *argocd.Instance, appName, namespace string, cfg config.DiagnosticsConfig) (diagnostics.Target, error) {
+	if rollout, err := resolveRollout(ctx, argo, appName, namespace); err == nil {
+		status, err := rolloutController(argo).Status(ctx, rollout)
+		if err == nil && status.CurrentPodHash != "" {
+			return diagnostics.Target{
+				Namespace:  rollout.Namespace,
+				Selector:   fmt.Sprintf("rollouts-pod-template-hash=%s", status.CurrentPodHash),
+				ReplicaSet: fmt.Sprintf("%s-%s", rollout.Name, status.CurrentPodHash),
+			}, nil
+		}
+	}
+
+	tmpl, err := template.New("selector").Option("missingkey=error").Parse(cfg.Selector)
+	if err != nil {
+		return diagnostics.Target{}, fmt.Errorf("diagnosticTarget | invalid diagnostics.selector: %w", err)
+	}
+	var selector bytes.Buffer
+	if err := tmpl.Execute(&selector, map[string]string{"Application": appName}); err != nil {
+		return diagnostics.Target{}, fmt.Errorf("diagnosticTarget | failed to render diagnostics.selector: %w", err)
+	}
+	return diagnostics.Target{Namespace: namespace, Selector: selector.String()}, nil
+}
+
+// uploadDiagnostics 전체 진단 정보를 Slack 파일로 업로드
+// 승인 요청 메시지가 있는 경우 해당 메시지의 스레드에, 그 외에는 애플리케이션 slack_channel에 업로드한다.
+// Slack Bot이 설정되지 않은 경우 진단 정보는 배포 기록(GET /deployments/{id})으로만 확인할 수 있다.
+func uploadDiagnostics(ctx context.Context, report *diagnostics.Report, appName, deploymentID string) {
+	if report == nil || slackClient == nil {
+		return
+	}
+
+	channel, thread := applications.Get(appName).SlackChannel, ""
+	if d, exist := deployments.Get(deploymentID); exist && d.SlackChannel != "" {
+		channel, thread = d.SlackChannel, d.SlackTimestamp
+	}
+	if channel == "" {
+		return
+	}
+
+	content := logging.Redact(report.Text())
+	ctx, span := tracing.Start(context.WithoutCancel(ctx), "slack files.upload", attribute.String("slack.channel", channel))
+	_, err := slackClient.UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+		Channel:         channel,
+		ThreadTimestamp: thread,
+		Content:         content,
+		FileSize:        len(content),
+		Filename:        fmt.Sprintf("%s-diagnostics-%s.txt", appName, report.CollectedAt.Format("20060102-150405")),
+		Title:           fmt.Sprintf("%s Health Check 실패 진단 정보", appName),
+		InitialComment:  fmt.Sprintf(":mag: *%s* Health Check 실패 진단 정보 (Pod 상태, 이벤트, 컨테이너 로그)", appName),
+	})
+	tracing.End(span, err)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("uploadDiagnostics | failed to upload diagnostics of %s", appName)
+	}
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/dora"
+	"github.com/antonio-kim-1994/devops-relay/server/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDORAWindow   = 30 * 24 * time.Hour
+	defaultDORASchedule = "mon 09:00"
+	doraDigestWindow    = 7 * 24 * time.Hour
+)
+
+// setupDORA 종료된 배포 기록 보관소, Prometheus DORA gauge 및 주간 보고 설정
+func setupDORA(cfg config.DORAConfig) error {
+	var retention time.Duration
+	if cfg.Retention != "" {
+		var err error
+		if retention, err = dora.ParseWindow(cfg.Retention); err != nil {
+			return fmt.Errorf("setupDORA | invalid DORA_RETENTION: %w", err)
+		}
+	}
+
+	window := defaultDORAWindow
+	if cfg.Window != "" {
+		var err error
+		if window, err = dora.ParseWindow(cfg.Window); err != nil {
+			return fmt.Errorf("setupDORA | invalid DORA_WINDOW: %w", err)
+		}
+	}
+
+	store, err := dora.Open(cfg.HistoryPath, retention)
+	if err != nil {
+		return fmt.Errorf("setupDORA | failed to load dora history: %w", err)
+	}
+	doraStore = store
+	deployments.Subscribe(store.Observe)
+
+	metrics.RegisterDORA(dora.FormatWindow(window), func() []metrics.DORA {
+		groups := store.Groups(window, time.Now())
+		stats := make([]metrics.DORA, 0, len(groups))
+		for _, m := range groups {
+			stats = append(stats, metrics.DORA{
+				Team:                 m.Team,
+				Application:          m.Application,
+				Environment:          m.Environment,
+				DeploymentsPerDay:    m.DeploymentFrequency.PerDay,
+				LeadTimeSeconds:      m.LeadTime.MedianSeconds,
+				ChangeFailureRate:    m.ChangeFailureRate.Rate,
+				TimeToRestoreSeconds: m.TimeToRestore.MedianSeconds,
+			})
+		}
+		return stats
+	})
+
+	if cfg.DigestChannel == "" {
+		return nil
+	}
+	if slackClient == nil {
+		return fmt.Errorf("setupDORA | DORA_DIGEST_CHANNEL requires SLACK_BOT_TOKEN")
+	}
+
+	spec := cfg.DigestSchedule
+	if spec == "" {
+		spec = defaultDORASchedule
+	}
+	schedule, err := dora.ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("setupDORA | invalid DORA_DIGEST_SCHEDULE: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopDORADigest = cancel
+	go runDORADigest(ctx, schedule, cfg.DigestChannel)
+	return nil
+}
+
+// HandleDORAMetrics GET /metrics/dora?team=&app=&env=&window=
+// 종료된 배포 기록 기준 DORA 지표 조회. env 미지정 시 운영(prod), window 미지정 시 30d 기준으로 계산한다.
+func HandleDORAMetrics(c *gin.Context) {
+	window := defaultDORAWindow
+	if w := c.Query("window"); w != "" {
+		var err error
+		if window, err = dora.ParseWindow(w); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": "invalid window (e.g. 7d, 30d, 12h)",
+				"status":  "failed",
+			})
+			return
+		}
+	}
+
+	env := c.DefaultQuery("env", "prod")
+	if env == "all" {
+		env = ""
+	}
+
+	m := doraStore.Compute(dora.Filter{
+		Team:        c.Query("team"),
+		Application: c.Query("app"),
+		Environment: env,
+		Window:      window,
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"dora":   m,
+		"status": "success",
+	})
+}
+
+// runDORADigest 보고 시각마다 팀별 주간 DORA 지표를 Slack 채널에 전송
+func runDORADigest(ctx context.Context, schedule dora.Schedule, channel string) {
+	log.Info().Msgf("runDORADigest | weekly dora digest scheduled: %s (%s)", schedule, channel)
+	for {
+		next := schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := sendDORADigest(ctx, channel, next); err != nil {
+			log.Error().Err(err).Msgf("runDORADigest | failed to send dora digest to %s", channel)
+		}
+	}
+}
+
+// sendDORADigest 운영 환경 팀별 주간 DORA 지표 Slack 메시지 전송
+func sendDORADigest(ctx context.Context, channel string, now time.Time) error {
+	teams := map[string]bool{}
+	for _, r := range doraStore.Records() {
+		if r.Action == deployment.ActionDeploy && r.Environment == "prod" {
+			teams[r.Team] = true
+		}
+	}
+	names := make([]string, 0, len(teams))
+	for team := range teams {
+		names = append(names, team)
+	}
+	sort.Strings(names)
+
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", "주간 DORA 지표", false, false)),
+		slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn",
+			fmt.Sprintf("운영(prod) 배포 기준 | %s ~ %s", now.Add(-doraDigestWindow).Format("2006-01-02 15:04"), now.Format("2006-01-02 15:04")), false, false)),
+	}
+
+	reported := 0
+	for _, team := range names {
+		m := doraStore.Compute(dora.Filter{Team: team, Environment: "prod", Window: doraDigestWindow, Now: now})
+		if m.ChangeFailureRate.Deployments == 0 && m.TimeToRestore.Unresolved == 0 {
+			continue
+		}
+		reported++
+
+		title := team
+		if title == "" {
+			title = "(팀 미지정)"
+		}
+		blocks = append(blocks,
+			slack.NewDividerBlock(),
+			slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*%s*", title), false, false), []*slack.TextBlockObject{
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*배포 빈도*\n%d회 (일 %.1f회)", m.DeploymentFrequency.Deployments, m.DeploymentFrequency.PerDay), false, false),
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*변경 리드 타임*\n%s", formatMedian(m.LeadTime)), false, false),
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*변경 실패율*\n%.1f%% (%d/%d)", m.ChangeFailureRate.Rate*100, m.ChangeFailureRate.Failures, m.ChangeFailureRate.Deployments), false, false),
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*서비스 복구 시간*\n%s", formatRestore(m.TimeToRestore)), false, false),
+			}, nil),
+		)
+	}
+	if reported == 0 {
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", "지난 한 주 동안 운영 배포가 없습니다.", false, false), nil, nil))
+	}
+
+	_, _, err := postSlackMessage(ctx, channel, slack.Blocks{BlockSet: blocks}, "주간 DORA 지표")
+	return err
+}
+
+func formatMedian(stat dora.DurationStat) string {
+	if stat.MedianSeconds == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s (중앙값, %d건)", formatDuration(time.Duration(*stat.MedianSeconds*float64(time.Second))), stat.Samples)
+}
+
+func formatRestore(stat dora.DurationStat) string {
+	text := formatMedian(stat)
+	if stat.Unresolved > 0 {
+		text = fmt.Sprintf("%s, 미복구 %d건", text, stat.Unresolved)
+	}
+	return text
+}
+
+// formatDuration 일/시간/분 단위 표시 (예: 1d 3h, 2h 15m, 45m)
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := d / (24 * time.Hour)
+	hours := (d % (24 * time.Hour)) / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes > 0 && days == 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	if len(parts) == 0 {
+		return "1m 미만"
+	}
+	return strings.Join(parts, " ")
+}
@@ -1,23 +1,24 @@
 package handler
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/requestid"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
-	"io"
 	"net/http"
-	"os"
+	"strings"
+	"time"
 )
 
-const argoUrl = "http://argocd-server.argocd.svc.cluster.local"
-
 func HandleGithubRequest(c *gin.Context) {
+	ctx := c.Request.Context()
 	var s ServiceInfo
 	if err := c.ShouldBindJSON(&s); err != nil {
-		log.Error().Err(err).Msgf("HandleGithubRequest | failed to bind service info")
+		log.Ctx(ctx).Error().Err(err).Msgf("HandleGithubRequest | failed to bind service info")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "failed to get service info",
 			"status":  "failed",
@@ -25,142 +26,151 @@ func HandleGithubRequest(c *gin.Context) {
 		return
 	}
 
-	token, err := getArgoCDAdminToken()
-	if err != nil {
-		log.Error().Err(err).Msg("SyncApplication | failed to get ArgoCD admin token")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "failed to get ArgoCD admin token",
-			"error":   fmt.Sprintf("%v", err),
+	// Gateway가 발급한 배포 ID를 사용해 Gateway, Server 로그를 같은 배포 ID로 연결
+	if s.DeploymentID == "" {
+		s.DeploymentID = deployment.NewID()
+	} else if !deployment.ValidID(s.DeploymentID) {
+		log.Ctx(ctx).Error().Msgf("HandleGithubRequest | invalid deployment id: %q", s.DeploymentID)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "invalid deployment id",
 			"status":  "failed",
 		})
 		return
 	}
-
-	path := fmt.Sprintf("api/v1/applications/%s/sync", s.ApplicationName)
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", argoUrl, path), nil)
-	if err != nil {
-		log.Error().Err(err).Msg("SyncApplication | failed to create request")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "failed to create request",
-			"error":   fmt.Sprintf("%v", err),
-			"status":  "failed",
+	ctx = log.Ctx(ctx).With().Str("deployment_id", s.DeploymentID).Logger().WithContext(ctx)
+
+	// 배포 동결 기간 확인
+	verified := verifyBreakGlassToken(c.GetHeader(breakGlassHeader))
+	if freeze, reason := checkDeployFreeze(s, verified); freeze != nil {
+		log.Ctx(ctx).Warn().Msgf("HandleGithubRequest | %s - Application: %s, Branch: %s", reason, s.ApplicationName, s.Branch)
+		err := sendDeployNoticeMessage(ctx, s,
+			fmt.Sprintf(":snowflake: *`%s` 배포 동결 기간* :snowflake:", s.Branch),
+			fmt.Sprintf("배포 동결 기간으로 *%s* 배포가 차단되었습니다.\n> %s", s.ApplicationName, reason),
+			":pushpin: *긴급 배포가 필요한 경우 관리자(@devops)에 break-glass 배포를 요청하세요.*",
+		)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("HandleGithubRequest | failed to send deploy freeze message")
+		}
+		c.JSON(http.StatusLocked, gin.H{
+			"message": reason,
+			"status":  "blocked",
 		})
 		return
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
-	req.Header.Add("Content-Type", "application/json")
+	// 배포 정책 평가
+	if decision := evaluateDeployPolicy(s, verified); !decision.Allowed {
+		log.Ctx(ctx).Warn().Msgf("HandleGithubRequest | deploy denied by policy - Application: %s, Branch: %s, Reason: %s", s.ApplicationName, s.Branch, decision.Reason())
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Error().Err(err).Msg("SyncApplication | failed to send request")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "failed to send request",
-			"error":   fmt.Sprintf("%v", err),
-			"status":  "failed",
+		var detail strings.Builder
+		detail.WriteString(fmt.Sprintf("배포 정책에 의해 *%s* 배포가 거부되었습니다.", s.ApplicationName))
+		for _, denial := range decision.Denials {
+			detail.WriteString(fmt.Sprintf("\n> `%s` %s", denial.Policy, denial.Message))
+		}
+
+		err := sendDeployNoticeMessage(ctx, s,
+			fmt.Sprintf(":no_entry_sign: *`%s` 배포 정책 위반* :no_entry_sign:", s.Branch),
+			detail.String(),
+			":pushpin: *배포 정책 문의는 DevOps 팀에 문의주시기 바랍니다.*",
+		)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("HandleGithubRequest | failed to send deploy policy denied message")
+		}
+		c.JSON(http.StatusForbidden, gin.H{
+			"message": fmt.Sprintf("deploy denied by policy: %s", decision.Reason()),
+			"denials": decision.Denials,
+			"status":  "denied",
 		})
 		return
 	}
-	defer resp.Body.Close()
 
-	_, err = io.ReadAll(resp.Body)
+	app := applications.Get(s.ApplicationName)
+	argo, err := argoInstances.Get(app.ArgoCD)
 	if err != nil {
-		log.Error().Err(err).Msg("SyncApplication | failed to read response body")
+		log.Ctx(ctx).Error().Err(err).Msgf("HandleGithubRequest | failed to get argocd instance - Application: %s", s.ApplicationName)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "failed to read response body",
+			"message": "failed to get argocd instance",
 			"error":   fmt.Sprintf("%v", err),
 			"status":  "failed",
 		})
 		return
 	}
 
-	log.Info().Msgf("SyncApplication | sync request to argocd succeeded - Application: %s, Namespace: %s ", s.ApplicationName, s.ApplicationNamespace)
-
-	healthCheckResult, h := serviceHealthCheck(s.ApplicationName, s.ApplicationNamespace)
-	if !healthCheckResult {
-		err := sendHealthCheckFailMessage(s.ApplicationName, s.SlackWebhookUrl, *h)
-		log.Err(err).Msg("SyncApplication | Failed to check health check")
+	request, err := json.Marshal(s)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("HandleGithubRequest | failed to encode service info - Application: %s", s.ApplicationName)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "server health check failed",
-			"error":   fmt.Sprintf("%v", err),
+			"message": "failed to encode service info",
 			"status":  "failed",
 		})
 		return
 	}
 
-	switch s.Branch {
-	case "prod":
-		err := sendDeployRequestMessage(s)
-		if err != nil {
-			log.Error().Err(err).Msg("SyncApplication | Failed to send deploy request")
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": "failed to send deploy request",
-				"error":   fmt.Sprintf("%v", err),
-				"status":  "failed",
-			})
-			return
-		}
-	default:
-		err := sendUpdateSuccessMessage(s)
-		if err != nil {
-			log.Error().Err(err).Msg("SyncApplication | Failed to send update success message")
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": "failed to send update success message",
-				"error":   fmt.Sprintf("%v", err),
-				"status":  "failed",
-			})
-			return
-		}
-	}
-	return
-}
-
-func getArgoCDAdminToken() (string, error) {
-	url := fmt.Sprintf("%s/api/v1/session", argoUrl)
-	username := os.Getenv("ARGO_ADMIN_USERNAME")
-	password := os.Getenv("ARGO_ADMIN_PASSWORD")
-
-	payload := map[string]string{
-		"username": username,
-		"password": password,
-	}
-
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return "", errors.New(fmt.Sprintf("getAdminToken | failed to marshal payload to json: %s", err))
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return "", errors.New(fmt.Sprintf("getAdminToken | failed to create request: %s", err))
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", errors.New(fmt.Sprintf("getAdminToken | failed to send request: %s", err))
+	d, err := deployments.Register(deployment.Deployment{
+		ID:            s.DeploymentID,
+		Action:        deployment.ActionDeploy,
+		Application:   s.ApplicationName,
+		Namespace:     s.ApplicationNamespace,
+		Environment:   s.Branch,
+		Org:           s.Org,
+		Repo:          s.Repo,
+		DockerTag:     s.DockerTag,
+		Operator:      s.Operator,
+		CommitMessage: s.CommitMessage,
+		ArgoCD:        argo.Name,
+		Team:          teamOf(s.ApplicationName, s.Team),
+		CommittedAt:   commitTime(s),
+		RequestID:     requestid.From(ctx),
+		TraceParent:   tracing.TraceParent(ctx),
+		Request:       request,
+	})
+	if errors.Is(err, deployment.ErrExists) {
+		// GitHub Actions 재시도 등 이미 등록된 배포 ID는 기존 배포를 다시 실행하지 않는다.
+		log.Ctx(ctx).Warn().Msgf("HandleGithubRequest | deployment %s already registered (%s)", d.ID, d.Status)
+		c.JSON(http.StatusConflict, gin.H{
+			"message":       fmt.Sprintf("%s | deployment already registered", s.ApplicationName),
+			"deployment_id": d.ID,
+			"status":        string(d.Status),
+		})
+		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", errors.New(fmt.Sprintf("getAdminToken | failed to read response body: %s", err))
+	// 배포 잠금 획득부터 승인 요청까지 워커에서 실행하고 배포 ID를 즉시 반환
+	if err := startPipeline(d.ID, nil, runDeployPipeline); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("HandleGithubRequest | failed to start deploy pipeline - Application: %s, Branch: %s", s.ApplicationName, s.Branch)
+		deployments.Update(d.ID, func(d *deployment.Deployment) { d.Error = err.Error() })
+		deployments.Finish(d.ID, deployment.StatusFailed)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"message":       "failed to start deploy pipeline",
+			"error":         fmt.Sprintf("%v", err),
+			"deployment_id": d.ID,
+			"status":        "failed",
+		})
+		return
 	}
 
-	log.Debug().Msgf("getAdminToken | response status: %s, response body: %s", string(resp.Status), string(body))
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":       fmt.Sprintf("%s | deployment accepted", s.ApplicationName),
+		"deployment_id": d.ID,
+		"status":        "accepted",
+	})
+}
 
-	var response map[string]string
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return "", errors.New(fmt.Sprintf("getAdminToken | failed to unmarshal response body: %s", err))
-	}
+// commitTimeLayouts GitHub Actions에서 전달하는 커밋 시각 형식
+var commitTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
 
-	token, ok := response["token"]
-	if !ok {
-		return "", errors.New(fmt.Sprintf("getAdminToken | failed to get token from response: %s", err))
+// commitTime 배포 대상 커밋 시각 (DORA 변경 리드 타임 기준)
+// commit_timestamp를 우선 사용하며, date는 시간대가 없는 경우 서버 시간대(TIMEZONE)로 해석한다. 해석할 수 없는 경우 빈 값을 반환한다.
+func commitTime(s ServiceInfo) time.Time {
+	for _, value := range []string{s.CommitTimestamp, s.Date} {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		for _, layout := range commitTimeLayouts {
+			if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				return t
+			}
+		}
 	}
-
-	return token, nil
+	return time.Time{}
 }
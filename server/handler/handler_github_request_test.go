@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommitTime(t *testing.T) {
+	// date는 서버 시간대(TIMEZONE)로 해석한다.
+	kst := time.FixedZone("KST", 9*60*60)
+	prevLocal := time.Local
+	time.Local = kst
+	t.Cleanup(func() { time.Local = prevLocal })
+
+	cases := []struct {
+		name            string
+		commitTimestamp string
+		date            string
+		want            time.Time
+	}{
+		{name: "commit timestamp", commitTimestamp: "2026-10-19T00:30:00Z", date: "2026-10-18", want: time.Date(2026, 10, 19, 0, 30, 0, 0, time.UTC)},
+		{name: "commit timestamp with offset", commitTimestamp: " 2026-10-19T09:30:00+09:00 ", want: time.Date(2026, 10, 19, 0, 30, 0, 0, time.UTC)},
+		{name: "date rfc3339", date: "2026-10-19T09:30:00+09:00", want: time.Date(2026, 10, 19, 9, 30, 0, 0, kst)},
+		{name: "date without zone", date: "2026-10-19T09:30:00", want: time.Date(2026, 10, 19, 9, 30, 0, 0, kst)},
+		{name: "date with space", date: "2026-10-19 09:30:00", want: time.Date(2026, 10, 19, 9, 30, 0, 0, kst)},
+		{name: "date only", date: "2026-10-19", want: time.Date(2026, 10, 19, 0, 0, 0, 0, kst)},
+		// 해석할 수 없는 commit_timestamp는 date를 사용한다.
+		{name: "invalid commit timestamp", commitTimestamp: "yesterday", date: "2026-10-19", want: time.Date(2026, 10, 19, 0, 0, 0, 0, kst)},
+		{name: "invalid", commitTimestamp: "yesterday", date: "19/10/2026"},
+		{name: "empty"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := commitTime(ServiceInfo{CommitTimestamp: tc.commitTimestamp, Date: tc.date})
+			if !got.Equal(tc.want) || got.IsZero() != tc.want.IsZero() {
+				t.Errorf("commitTime(%q, %q) = %s, want %s", tc.commitTimestamp, tc.date, got, tc.want)
+			}
+		})
+	}
+}
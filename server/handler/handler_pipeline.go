@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/pipeline"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// pipelineFunc 배포 잠금을 획득한 이후 워커에서 실행되는 배포 단계
+// ctx는 배포 대체 및 서버 종료 시 취소되며, lock은 배포 잠금 context로 워커를 점유하지 않는 후속 작업(Rollout 추적)에 사용한다.
+// 배포를 종료하지 않고 잠금을 유지하는 경우 StatusAwaitingApproval 혹은 StatusRunning을 반환한다.
+type pipelineFunc func(ctx, lock context.Context, d deployment.Deployment) (deployment.Status, error)
+
+// startPipeline 배포 파이프라인을 워커 풀에 등록
+// lock이 nil인 경우 애플리케이션 deploy_lock 정책으로 배포 잠금을 획득한 뒤 등록한다.
+func startPipeline(id string, lock context.Context, run pipelineFunc) error {
+	if lock == nil {
+		d, exist := deployments.Get(id)
+		if !exist {
+			return fmt.Errorf("startPipeline | %w: %s", deployment.ErrNotFound, id)
+		}
+		return queuePipeline(id, deployment.LockPolicy(applications.Get(d.Application).DeployLock), run, notifyLockFailure)
+	}
+	return pipelines.Submit(func(workerCtx context.Context) {
+		executePipeline(workerCtx, id, lock, run)
+	})
+}
+
+// queuePipeline 워커를 점유하지 않는 작업에서 배포 잠금을 획득(대기)한 뒤 파이프라인을 워커 풀에 등록
+// 잠금 대기가 워커를 점유하면 잠금을 보유한 배포의 승인 처리가 실행되지 못하므로 워커 밖에서 대기한다.
+// 잠금 획득 혹은 워커 풀 등록에 실패한 경우 배포를 실패로 종료하고 failed를 호출한다.
+func queuePipeline(id string, policy deployment.LockPolicy, run pipelineFunc, failed func(ctx context.Context, d deployment.Deployment, err error)) error {
+	return pipelines.Go(func(taskCtx context.Context) {
+		d, exist := deployments.Get(id)
+		if !exist {
+			log.Warn().Msgf("queuePipeline | deployment %s not found", id)
+			return
+		}
+		ctx := withDeploymentLogger(tracing.WithTraceParent(taskCtx, d.TraceParent), d)
+
+		lock, superseded, err := deployments.Acquire(ctx, id, policy, applications.Get(d.Application).QueueTimeout)
+		for _, old := range superseded {
+			log.Ctx(ctx).Info().Msgf("queuePipeline | deployment %s superseded by %s", old.ID, id)
+			markApprovalObsolete(ctx, old)
+		}
+		if err != nil {
+			if errors.Is(err, pipeline.ErrShutdown) {
+				log.Ctx(ctx).Warn().Msgf("queuePipeline | deployment %s interrupted while waiting for deploy lock, resume on restart", id)
+				return
+			}
+			if errors.Is(err, deployment.ErrSuperseded) {
+				log.Ctx(ctx).Info().Msgf("queuePipeline | deployment %s superseded while waiting for deploy lock", id)
+			} else {
+				log.Ctx(ctx).Error().Err(err).Msgf("queuePipeline | failed to acquire deploy lock - Application: %s, Environment: %s", d.Application, d.Environment)
+				deployments.Update(id, func(d *deployment.Deployment) { d.Error = err.Error() })
+			}
+			// 대체한 배포 ID(SupersededBy) 등 대기 중 변경된 배포 기록으로 알림
+			if current, exist := deployments.Get(id); exist {
+				d = current
+			}
+			failed(ctx, d, err)
+			return
+		}
+
+		err = pipelines.Submit(func(workerCtx context.Context) {
+			executePipeline(workerCtx, id, lock, run)
+		})
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("queuePipeline | failed to submit deployment %s", id)
+			deployments.Update(id, func(d *deployment.Deployment) { d.Error = err.Error() })
+			deployments.Finish(id, deployment.StatusFailed)
+			failed(ctx, d, err)
+		}
+	})
+}
+
+func executePipeline(workerCtx context.Context, id string, lock context.Context, run pipelineFunc) {
+	d, exist := deployments.Get(id)
+	if !exist {
+		log.Warn().Msgf("executePipeline | deployment %s not found", id)
+		return
+	}
+
+	// 배포를 시작한 요청(GitHub 배포 요청, Slack 승인)의 trace에 파이프라인 span 연결
+	workerCtx, span := tracing.Start(tracing.WithTraceParent(workerCtx, d.TraceParent), fmt.Sprintf("deployment %s", d.Action),
+		attribute.String("deployment.id", id),
+		attribute.String("deployment.application", d.Application),
+		attribute.String("deployment.environment", d.Environment),
+		attribute.String("deployment.stage", string(d.Stage)),
+	)
+	workerCtx = withDeploymentLogger(workerCtx, d)
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := withDeployment(workerCtx, lock)
+	defer cancel()
+
+	var result deployment.Status
+	result, err = run(ctx, lock, d)
+	if cause := context.Cause(ctx); ctx.Err() != nil {
+		if errors.Is(cause, pipeline.ErrShutdown) {
+			current, _ := deployments.Get(id)
+			log.Ctx(ctx).Warn().Msgf("executePipeline | deployment %s interrupted at stage %s, resume on restart", id, current.Stage)
+			return
+		}
+		log.Ctx(ctx).Warn().Err(cause).Msgf("executePipeline | deployment %s stopped", id)
+		result, err = deployment.StatusFailed, cause
+	}
+
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("executePipeline | deployment %s failed - Application: %s, Environment: %s", id, d.Application, d.Environment)
+		deployments.Update(id, func(d *deployment.Deployment) { d.Error = err.Error() })
+	}
+
+	switch result {
+	case deployment.StatusAwaitingApproval, deployment.StatusRunning:
+		return
+	}
+	deployments.Finish(id, result)
+}
+
+// withDeploymentLogger 배포 ID와 배포를 시작한 요청 ID가 포함된 로거(log.Ctx)를 설정한 context
+func withDeploymentLogger(ctx context.Context, d deployment.Deployment) context.Context {
+	logger := log.With().
+		Str("deployment_id", d.ID).
+		Str("request_id", d.RequestID).
+		Str("application", d.Application).
+		Str("environment", d.Environment).
+		Ctx(ctx).
+		Logger()
+	return logger.WithContext(ctx)
+}
+
+// withRequestScope ctx의 취소/기한은 유지하고 from의 trace span과 로거(log.Ctx)를 이어받은 context
+// 요청과 수명이 다른 context(배포 잠금, 워커)에서 요청 trace 및 로그 필드를 이어갈 때 사용한다.
+func withRequestScope(ctx, from context.Context) context.Context {
+	return zerolog.Ctx(from).WithContext(tracing.WithSpan(ctx, from))
+}
+
+// withDeployment 워커 context(서버 종료)와 배포 잠금 context(배포 대체)가 모두 반영되는 context
+func withDeployment(ctx, lock context.Context) (context.Context, context.CancelFunc) {
+	merged, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(lock, func() { cancel(context.Cause(lock)) })
+	return merged, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// notifyLockFailure 배포 잠금 대기 시간 초과, 신규 배포 요청으로 대체 혹은 워커 풀 등록 실패 알림
+func notifyLockFailure(ctx context.Context, d deployment.Deployment, lockErr error) {
+	var s ServiceInfo
+	if err := json.Unmarshal(d.Request, &s); err != nil || s.SlackWebhookUrl == "" {
+		return
+	}
+
+	title := fmt.Sprintf(":hourglass: *`%s` 배포 대기 시간 초과* :hourglass:", s.Branch)
+	text := fmt.Sprintf("진행 중인 배포가 종료되지 않아 *%s* 배포를 시작하지 못했습니다.\n> %v", s.ApplicationName, lockErr)
+	footer := ":pushpin: *진행 중인 배포 확인 후 다시 요청하세요.*"
+	switch {
+	case errors.Is(lockErr, deployment.ErrSuperseded):
+		title = fmt.Sprintf(":fast_forward: *`%s` 배포 대체* :fast_forward:", s.Branch)
+		text = fmt.Sprintf("배포 대기 중 신규 배포 요청(`%s`)으로 대체되어 *%s* 배포를 시작하지 않았습니다.", d.SupersededBy, s.ApplicationName)
+		footer = ":pushpin: *신규 배포 요청의 진행 상황을 확인하세요.*"
+	case errors.Is(lockErr, pipeline.ErrQueueFull):
+		title = fmt.Sprintf(":hourglass: *`%s` 배포 대기열 초과* :hourglass:", s.Branch)
+		text = fmt.Sprintf("배포 파이프라인 대기열이 가득 차 *%s* 배포를 시작하지 못했습니다.\n> %v", s.ApplicationName, lockErr)
+	}
+
+	err := sendDeployNoticeMessage(ctx, s, title, text, footer)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("notifyLockFailure | failed to send deploy lock failure message")
+	}
+}
+
+// resumeDeployments 서버 종료로 중단된 배포 파이프라인 재개
+// 승인 대기 중인 배포는 배포 잠금만 복원되며, 재개할 수 없는 작업(롤백)은 실패로 종료한다.
+func resumeDeployments() {
+	for _, d := range deployments.InFlight() {
+		if d.Status != deployment.StatusQueued {
+			continue
+		}
+
+		var run pipelineFunc
+		switch {
+		case d.Action == deployment.ActionDeploy && (d.Stage == deployment.StagePromote || d.Stage == deployment.StageRolloutWatch):
+			run = runApprovalPipeline
+		case d.Action == deployment.ActionDeploy && d.Request != nil:
+			run = runDeployPipeline
+		default:
+			log.Warn().Msgf("resumeDeployments | deployment %s (%s) cannot be resumed", d.ID, d.Action)
+			deployments.Update(d.ID, func(d *deployment.Deployment) { d.Error = "interrupted by server restart" })
+			deployments.Finish(d.ID, deployment.StatusFailed)
+			continue
+		}
+
+		log.Info().Msgf("resumeDeployments | resume deployment %s from stage %q - Application: %s, Environment: %s", d.ID, d.Stage, d.Application, d.Environment)
+		if err := startPipeline(d.ID, nil, run); err != nil {
+			log.Error().Err(err).Msgf("resumeDeployments | failed to resume deployment %s", d.ID)
+			deployments.Update(d.ID, func(d *deployment.Deployment) { d.Error = err.Error() })
+			deployments.Finish(d.ID, deployment.StatusFailed)
+		}
+	}
+}
+
+// Shutdown 진행 중인 배포 파이프라인 중단. 배포 상태는 상태 파일에 유지되어 재기동 시 재개된다.
+func Shutdown(ctx context.Context) error {
+	if stopDORADigest != nil {
+		stopDORADigest()
+	}
+	if pipelines == nil {
+		return nil
+	}
+	if err := pipelines.Shutdown(ctx); err != nil {
+		return fmt.Errorf("Shutdown | failed to stop deploy pipelines: %w", err)
+	}
+	// 파이프라인 종료 이후 발생한 이벤트까지 전송
+	if datadogEvents != nil {
+		if err := datadogEvents.Close(ctx); err != nil {
+			return fmt.Errorf("Shutdown | failed to flush datadog events: %w", err)
+		}
+	}
+	if webhooks != nil {
+		if err := webhooks.Close(ctx); err != nil {
+			return fmt.Errorf("Shutdown | failed to stop webhook deliveries: %w", err)
+		}
+	}
+	return nil
+}
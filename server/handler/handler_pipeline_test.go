@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/pipeline"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newSlackWebhook Slack Webhook 요청 본문을 기록하는 테스트 서버
+func newSlackWebhook(t *testing.T) (string, func() string) {
+	t.Helper()
+	bodies := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies <- string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL, func() string {
+		select {
+		case body := <-bodies:
+			return body
+		default:
+			return ""
+		}
+	}
+}
+
+func TestNotifyLockFailure(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     error
+		want    []string
+		notWant string
+	}{
+		{
+			name:    "superseded",
+			err:     deployment.ErrSuperseded,
+			want:    []string{"배포 대체", "신규 배포 요청(`dep-new`)으로 대체"},
+			notWant: "대기 시간 초과",
+		},
+		{
+			name: "queue timeout",
+			err:  fmt.Errorf("%w: dep-running", deployment.ErrQueueTimeout),
+			want: []string{"배포 대기 시간 초과", "진행 중인 배포가 종료되지 않아", "dep-running"},
+		},
+		{
+			name: "queue full",
+			err:  pipeline.ErrQueueFull,
+			want: []string{"배포 대기열 초과"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			url, received := newSlackWebhook(t)
+			request, _ := json.Marshal(ServiceInfo{Org: "org-a", Repo: "homepage-front", Branch: "prod", ApplicationName: "homepage-front", SlackWebhookUrl: url})
+			d := deployment.Deployment{ID: "dep-old", SupersededBy: "dep-new", Request: request}
+
+			notifyLockFailure(context.Background(), d, tc.err)
+
+			body := received()
+			for _, want := range tc.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("message = %s, want %q", body, want)
+				}
+			}
+			if tc.notWant != "" && strings.Contains(body, tc.notWant) {
+				t.Errorf("message = %s, want without %q", body, tc.notWant)
+			}
+		})
+	}
+}
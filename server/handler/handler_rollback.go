@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"github.com/antonio-kim-1994/devops-relay/server/audit"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/requestid"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	errNoRollbackRevision = errors.New("no revision to roll back to")
+	errRollbackLock       = errors.New("failed to acquire deploy lock")
+)
+
+// rollbackTarget 롤백 대상 애플리케이션
+type rollbackTarget struct {
+	Application string
+	Namespace   string
+	Environment string
+	Org         string
+	Repo        string
+	// 배포 기록의 ArgoCD 인스턴스 (미설정 시 애플리케이션 설정 기준)
+	ArgoCD string
+	// 배포 기록의 담당 팀
+	Team string
+	// 롤백 요청 기준 배포 ID (애플리케이션 기준 롤백 시 빈 값)
+	DeploymentID string
+	// 배포 기록의 Sync 직전 ArgoCD 배포 이력 ID
+	PreviousHistoryID *int64
+}
+
+// HandleDeploymentRollback POST /deployments/:id/rollback
+// 배포 기록의 애플리케이션을 이전 배포 이력으로 롤백한다.
+func HandleDeploymentRollback(c *gin.Context) {
+	var req RollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Ctx(c.Request.Context()).Error().Err(err).Msg("HandleDeploymentRollback | failed to bind rollback request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "failed to get rollback request",
+			"status":  "failed",
+		})
+		return
+	}
+
+	id := c.Param("id")
+	d, exist := deployments.Get(id)
+	if !exist {
+		c.JSON(http.StatusNotFound, gin.H{
+			"message":       "deployment not found",
+			"deployment_id": id,
+			"status":        "failed",
+		})
+		return
+	}
+
+	target := rollbackTarget{
+		Application:       d.Application,
+		Namespace:         d.Namespace,
+		Environment:       d.Environment,
+		Org:               d.Org,
+		Repo:              d.Repo,
+		ArgoCD:            d.ArgoCD,
+		Team:              d.Team,
+		DeploymentID:      d.ID,
+		PreviousHistoryID: d.PreviousHistoryID,
+	}
+	respondRollback(c, target, req)
+}
+
+// HandleApplicationRollback POST /apps/:app/rollback
+// 배포 기록이 없는 경우(Server 재기동 등) 애플리케이션 이름으로 롤백한다.
+func HandleApplicationRollback(c *gin.Context) {
+	var req RollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Ctx(c.Request.Context()).Error().Err(err).Msg("HandleApplicationRollback | failed to bind rollback request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "failed to get rollback request",
+			"status":  "failed",
+		})
+		return
+	}
+
+	if req.Environment == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "environment is required",
+			"status":  "failed",
+		})
+		return
+	}
+
+	target := rollbackTarget{
+		Application: c.Param("app"),
+		Namespace:   req.Namespace,
+		Environment: req.Environment,
+	}
+	respondRollback(c, target, req)
+}
+
+// respondRollback 롤백을 워커 풀에 등록하고 배포 ID를 즉시 반환. 진행 상황 및 결과는 Slack으로 전달한다.
+func respondRollback(c *gin.Context, target rollbackTarget, req RollbackRequest) {
+	ctx := c.Request.Context()
+	notify := func(ctx context.Context, text string) {
+		if err := sendRollbackMessage(ctx, target, req.SlackWebhookUrl, text); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("respondRollback | failed to send rollback message: %s", target.Application)
+		}
+	}
+
+	d, err := startRollback(ctx, target, req.HistoryID, req.Operator, req.Reason, notify)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("respondRollback | failed to start rollback of %s", target.Application)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"message":       "failed to start rollback",
+			"error":         fmt.Sprintf("%v", err),
+			"deployment_id": d.ID,
+			"status":        "failed",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":       fmt.Sprintf("%s | rollback accepted", target.Application),
+		"deployment_id": d.ID,
+		"status":        "accepted",
+	})
+}
+
+// handleSlackRollback Slack 롤백 버튼 처리
+func handleSlackRollback(c *gin.Context, r SlackResponse) {
+	target := rollbackTarget{
+		Application:  r.Button.ApplicationName,
+		Namespace:    r.Button.ApplicationNamespace,
+		Environment:  r.Button.Branch,
+		Org:          r.Button.Org,
+		DeploymentID: r.Button.DeploymentID,
+	}
+	if d, exist := deployments.Get(r.Button.DeploymentID); exist {
+		target.Repo = d.Repo
+		target.ArgoCD = d.ArgoCD
+		target.Team = d.Team
+		target.PreviousHistoryID = d.PreviousHistoryID
+	}
+
+	ctx := c.Request.Context()
+	notify := func(ctx context.Context, text string) {
+		reply := slackResponseForm{url: r.ResponseURL, msg: generateSlackTextBlock(text)}
+		if err := reply.sendResponseToSlack(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("handleSlackRollback | failed to send rollback message: %s", target.Application)
+		}
+	}
+
+	d, err := startRollback(ctx, target, nil, r.User.Name, "Slack rollback button", notify)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("handleSlackRollback | failed to start rollback of %s", target.Application)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"message":       "failed to start rollback",
+			"error":         fmt.Sprintf("%v", err),
+			"deployment_id": d.ID,
+			"status":        "failed",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":       fmt.Sprintf("%s | rollback accepted", target.Application),
+		"deployment_id": d.ID,
+		"status":        "accepted",
+	})
+}
+
+// startRollback ArgoCD 배포 이력 기준 롤백 배포 기록을 등록하고 워커 풀에서 롤백 수행
+// 롤백은 긴급 조치이므로 같은 애플리케이션/환경의 진행 중인 배포를 대체하며, 배포 잠금은 워커 밖에서 획득한다.
+// ctx는 trace 연결 및 요청 ID에만 사용하며, 진행 상황은 롤백 작업의 context로 notify에 전달한다.
+func startRollback(ctx context.Context, target rollbackTarget, historyID *int64, operator, reason string, notify func(ctx context.Context, text string)) (deployment.Deployment, error) {
+	d := deployment.Deployment{
+		ID:            deployment.NewID(),
+		Action:        deployment.ActionRollback,
+		Application:   target.Application,
+		Namespace:     target.Namespace,
+		Environment:   target.Environment,
+		Org:           target.Org,
+		Repo:          target.Repo,
+		Operator:      operator,
+		CommitMessage: reason,
+		RollbackOf:    target.DeploymentID,
+		Team:          teamOf(target.Application, target.Team),
+		RequestID:     requestid.From(ctx),
+		TraceParent:   tracing.TraceParent(ctx),
+	}
+
+	instance := target.ArgoCD
+	if instance == "" {
+		instance = applications.Get(target.Application).ArgoCD
+	}
+	argo, err := argoInstances.Get(instance)
+	if err != nil {
+		err = fmt.Errorf("startRollback | failed to get argocd instance: %w", err)
+		recordRollbackAudit(d, deployment.StatusFailed, err)
+		return d, err
+	}
+	d.ArgoCD = argo.Name
+
+	if _, err := deployments.Register(d); err != nil {
+		err = fmt.Errorf("startRollback | failed to register rollback: %w", err)
+		recordRollbackAudit(d, deployment.StatusFailed, err)
+		return d, err
+	}
+
+	run := func(ctx, _ context.Context, d deployment.Deployment) (deployment.Status, error) {
+		result := deployment.StatusSucceeded
+		err := executeRollback(ctx, argo, target, historyID, &d, notify)
+		if err != nil {
+			result = deployment.StatusFailed
+		}
+		recordRollbackAudit(d, result, err)
+		return result, err
+	}
+	failed := func(ctx context.Context, d deployment.Deployment, err error) {
+		if errors.Is(err, deployment.ErrSuperseded) {
+			notify(ctx, fmt.Sprintf(":fast_forward: *롤백 대체* | *%s* (`%s`) 롤백이 대기 중 신규 요청(`%s`)으로 대체되었습니다.", target.Application, target.Environment, d.SupersededBy))
+			recordRollbackAudit(d, deployment.StatusSuperseded, err)
+			return
+		}
+		err = fmt.Errorf("startRollback | %w: %w", errRollbackLock, err)
+		notify(ctx, fmt.Sprintf(":x: *롤백 실패* | *%s* (`%s`) 롤백을 시작하지 못했습니다.\n> %v", target.Application, target.Environment, err))
+		recordRollbackAudit(d, deployment.StatusFailed, err)
+	}
+
+	if err := queuePipeline(d.ID, deployment.LockSupersede, run, failed); err != nil {
+		err = fmt.Errorf("startRollback | failed to start rollback: %w", err)
+		deployments.Update(d.ID, func(d *deployment.Deployment) { d.Error = err.Error() })
+		deployments.Finish(d.ID, deployment.StatusFailed)
+		recordRollbackAudit(d, deployment.StatusFailed, err)
+		return d, err
+	}
+	return d, nil
+}
+
+// executeRollback 배포 잠금 획득 이후 워커에서 롤백 수행
+// historyID 미지정 시 배포 기록의 Sync 직전 배포 이력, 배포 기록이 없는 경우 현재 배포 직전의 배포 이력(ArgoCD history는 성공한 Sync만 기록)으로 롤백한다.
+// 선택한 배포 이력은 d 및 배포 기록에 저장한다.
+func executeRollback(ctx context.Context, argo *argocd.Instance, target rollbackTarget, historyID *int64, d *deployment.Deployment, notify func(context.Context, string)) error {
+	app := applications.Get(target.Application)
+
+	application, err := argo.Client.GetApplication(ctx, target.Application)
+	if err != nil {
+		notify(ctx, fmt.Sprintf(":x: *롤백 실패* | *%s* (`%s`) 애플리케이션 조회에 실패했습니다.\n> %v", target.Application, target.Environment, err))
+		return fmt.Errorf("executeRollback | failed to get application: %w", err)
+	}
+
+	history, err := selectRollbackHistory(application.Status.History, historyID, target.PreviousHistoryID)
+	if err != nil {
+		notify(ctx, fmt.Sprintf(":x: *롤백 실패* | *%s* (`%s`) 롤백 대상 배포 이력이 없습니다.\n> %v", target.Application, target.Environment, err))
+		return fmt.Errorf("executeRollback | %w", err)
+	}
+	d.HistoryID, d.Revision = history.ID, history.Revision
+	deployments.Update(d.ID, func(r *deployment.Deployment) { r.HistoryID, r.Revision = history.ID, history.Revision })
+
+	log.Ctx(ctx).Info().Msgf("executeRollback | rollback requested by %s - Application: %s, History: %d, Revision: %s", d.Operator, target.Application, history.ID, history.Revision)
+	notify(ctx, fmt.Sprintf(":rewind: *롤백 시작* | *%s* 사용자 요청으로 *%s* (`%s`)를 이전 배포 이력(ID: `%d`, Revision: `%s`)으로 롤백합니다.", d.Operator, target.Application, target.Environment, history.ID, shortRevision(history.Revision)))
+
+	startedAt := time.Now()
+	if _, err := argo.Client.Rollback(ctx, target.Application, argocd.RollbackRequest{ID: history.ID, Prune: app.Sync.Prune}); err != nil {
+		notify(ctx, fmt.Sprintf(":x: *롤백 실패* | *%s* (`%s`) ArgoCD 롤백 요청에 실패했습니다.\n> %v", target.Application, target.Environment, err))
+		return fmt.Errorf("executeRollback | failed to rollback application: %w", err)
+	}
+
+	cfg := app.Sync
+	cfg.DryRun = false
+	if err := waitForSyncOperation(ctx, argo.Client, target.Application, startedAt, cfg); err != nil {
+		notify(ctx, fmt.Sprintf(":x: *롤백 실패* | *%s* (`%s`) 롤백 이후 상태 확인에 실패했습니다.\n> %v", target.Application, target.Environment, err))
+		return fmt.Errorf("executeRollback | rollback operation failed: %w", err)
+	}
+
+	notify(ctx, fmt.Sprintf(":white_check_mark: *롤백 완료* | *%s* (`%s`) 롤백이 완료되었습니다. (Revision: `%s`, 배포 ID: `%s`)", target.Application, target.Environment, shortRevision(history.Revision), d.ID))
+	return nil
+}
+
+// selectRollbackHistory 지정한 배포 이력, 배포 기록의 Sync 직전 배포 이력(previous) 혹은 현재 배포 직전의 배포 이력 선택
+// Sync에 실패한 배포는 배포 이력이 추가되지 않아 현재 배포 이력이 Sync 직전 배포 이력이며, 이 경우 현재 배포 이력을 다시 적용한다.
+func selectRollbackHistory(history []argocd.RevisionHistory, id, previous *int64) (argocd.RevisionHistory, error) {
+	if len(history) == 0 {
+		return argocd.RevisionHistory{}, errNoRollbackRevision
+	}
+	current := history[len(history)-1]
+
+	if id == nil && previous != nil {
+		for _, h := range history {
+			if h.ID == *previous {
+				return h, nil
+			}
+		}
+		return argocd.RevisionHistory{}, fmt.Errorf("%w: history %d before the deployment not found", errNoRollbackRevision, *previous)
+	}
+
+	if id != nil {
+		if *id == current.ID {
+			return argocd.RevisionHistory{}, fmt.Errorf("%w: history %d is the current deployment", errNoRollbackRevision, *id)
+		}
+		for _, h := range history {
+			if h.ID == *id {
+				return h, nil
+			}
+		}
+		return argocd.RevisionHistory{}, fmt.Errorf("%w: history %d not found", errNoRollbackRevision, *id)
+	}
+
+	if len(history) < 2 {
+		return argocd.RevisionHistory{}, fmt.Errorf("%w: only the current deployment exists in history", errNoRollbackRevision)
+	}
+	return history[len(history)-2], nil
+}
+
+func recordRollbackAudit(d deployment.Deployment, result deployment.Status, rollbackErr error) {
+	detail := map[string]string{
+		"deployment_id": d.ID,
+		"rollback_of":   d.RollbackOf,
+		"history_id":    strconv.FormatInt(d.HistoryID, 10),
+		"revision":      d.Revision,
+		"argocd":        d.ArgoCD,
+		"result":        string(result),
+	}
+	if rollbackErr != nil {
+		detail["error"] = rollbackErr.Error()
+	}
+
+	err := audit.Record(audit.Entry{
+		Action:      "deployment.rollback",
+		Actor:       d.Operator,
+		Application: d.Application,
+		Environment: d.Environment,
+		Reason:      d.CommitMessage,
+		RequestID:   d.RequestID,
+		Detail:      detail,
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("recordRollbackAudit | failed to record rollback audit entry: %s", d.ID)
+	}
+}
+
+// sendRollbackMessage API 요청 롤백 진행 상황 전송
+// 요청에 Webhook이 없는 경우 애플리케이션 Slack 채널로 전송한다.
+func sendRollbackMessage(ctx context.Context, target rollbackTarget, webhookUrl, text string) error {
+	if webhookUrl != "" {
+		reply := slackResponseForm{url: webhookUrl, msg: generateSlackTextBlock(text)}
+		return reply.sendResponseToSlack(ctx)
+	}
+
+	channel := applications.Get(target.Application).SlackChannel
+	if slackClient == nil || channel == "" {
+		log.Ctx(ctx).Info().Msgf("sendRollbackMessage | no slack destination for %s: %s", target.Application, text)
+		return nil
+	}
+
+	if _, _, err := postSlackMessage(ctx, channel, generateSlackTextBlock(text), "롤백 진행 상황"); err != nil {
+		return fmt.Errorf("sendRollbackMessage | failed to post slack message: %w", err)
+	}
+	return nil
+}
+
+// rollbackButton 배포 결과 메시지에 포함되는 롤백 버튼
+func rollbackButton(org, branch, appName, namespace, deploymentID string) *slack.ActionBlock {
+	value := buttonValue(ButtonValue{Org: org, Branch: branch, ApplicationName: appName, ApplicationNamespace: namespace, RequestType: "deploy", Result: "rollback", DeploymentID: deploymentID})
+	return slack.NewActionBlock("rollback_block",
+		slack.NewButtonBlockElement("rollback", value,
+			slack.NewTextBlockObject("plain_text", "롤백", true, false),
+		).WithStyle("danger").WithConfirm(slack.NewConfirmationBlockObject(
+			slack.NewTextBlockObject("plain_text", "롤백 확인", false, false),
+			slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*%s* 를 이전 배포 이력으로 롤백하시겠습니까?", appName), false, false),
+			slack.NewTextBlockObject("plain_text", "롤백", false, false),
+			slack.NewTextBlockObject("plain_text", "취소", false, false),
+		)),
+	)
+}
+
+func shortRevision(revision string) string {
+	if len(revision) > 8 {
+		return revision[:8]
+	}
+	return revision
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/rollouts"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"strings"
+	"time"
+)
+
+// automationActor 자동 조치(중단, 롤백) 감사 로그 및 알림에 기록되는 수행자
+const automationActor = "devops-relay"
+
+// bake Rollout 완료 이후 bake 기간 동안 Rollout/ArgoCD 상태 및 Health Probe 확인
+// 연속 실패가 failure_threshold에 도달하거나 Rollout이 Degraded 상태가 되면 자동 조치한다.
+func (w rolloutWatch) bake(ctx context.Context, status *rollouts.Status, cfg config.BakeConfig) {
+	log.Ctx(ctx).Info().Msgf("rolloutWatch | rollout %s completed, baking for %s", w.rollout, cfg.Duration)
+	blocks := rolloutProgressBlocks(w, status,
+		fmt.Sprintf(":stopwatch: *운영 배포 안정화 확인 중* | *%s* Rollout이 완료되어 `%s` 동안 상태를 확인합니다.", w.rollout.Application, cfg.Duration),
+		rollbackButton(w.service.Org, w.service.Branch, w.service.ApplicationName, w.service.ApplicationNamespace, w.deploymentID),
+	)
+	if err := w.message.update(ctx, blocks, "운영 배포 안정화 확인 중", false); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("rolloutWatch | failed to update progress message: %s", w.rollout)
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(cfg.Duration)
+	defer deadline.Stop()
+
+	controller := rolloutController(w.argo)
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			w.stop(ctx, status)
+			return
+		case <-deadline.C:
+			w.finish(ctx, status, deployment.StatusSucceeded, bakeResultBlock(fmt.Sprintf(":white_check_mark: 안정화 기간(`%s`) 동안 문제가 감지되지 않았습니다.", cfg.Duration)))
+			return
+		case <-ticker.C:
+		}
+
+		current, err := controller.Status(ctx, w.rollout)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("rolloutWatch | failed to get rollout status: %s", w.rollout)
+			continue
+		}
+		status = current
+
+		if status.Failed() {
+			w.remediate(ctx, status, []string{degradedProblem(status)}, cfg)
+			return
+		}
+
+		problems := w.check(ctx, status, cfg)
+		if len(problems) == 0 {
+			failures = 0
+			continue
+		}
+
+		failures++
+		log.Ctx(ctx).Warn().Msgf("rolloutWatch | problems detected while baking rollout %s (%d/%d): %v", w.rollout, failures, cfg.FailureThreshold, problems)
+		if failures >= cfg.FailureThreshold {
+			w.remediate(ctx, status, problems, cfg)
+			return
+		}
+	}
+}
+
+// check ArgoCD Application Health 및 서비스 Health Probe 확인. 감지된 문제 목록을 반환한다.
+func (w rolloutWatch) check(ctx context.Context, status *rollouts.Status, cfg config.BakeConfig) []string {
+	var problems []string
+	if status.Failed() {
+		problems = append(problems, degradedProblem(status))
+	}
+
+	app, err := w.argo.Client.GetApplication(ctx, w.rollout.Application)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("rolloutWatch | failed to get application: %s", w.rollout.Application)
+	} else {
+		switch health := app.Status.Health; health.Status {
+		case "Degraded", "Missing":
+			problems = append(problems, fmt.Sprintf("ArgoCD Application Health `%s` %s", health.Status, health.Message))
+		}
+	}
+
+	if cfg.Probe {
+		if err := probeService(ctx, w.service.ApplicationName, w.service.ApplicationNamespace); err != nil {
+			problems = append(problems, fmt.Sprintf("Health Probe 실패: %v", err))
+		}
+	}
+	return problems
+}
+
+// remediate 문제 감지 시 자동 조치 및 진단 정보 알림
+// rollback: Rollout 완료 전에는 Rollout 중단(abort), 완료 이후에는 ArgoCD 이전 배포 이력으로 롤백
+// alert: 조치 없이 알림만 전송
+func (w rolloutWatch) remediate(ctx context.Context, status *rollouts.Status, problems []string, cfg config.BakeConfig) {
+	diagnostics := bakeResultBlock(fmt.Sprintf("*감지된 문제*\n> %s", strings.Join(problems, "\n> ")))
+	reason := fmt.Sprintf("automatic remediation: %s", strings.Join(problems, "; "))
+
+	switch {
+	case cfg.Action == "alert":
+		w.finish(ctx, status, deployment.StatusFailed, diagnostics)
+	case !status.Completed():
+		err := runRolloutAction(ctx, w.argo, w.rollout, rollouts.ActionAbort, 0, automationActor, w.service.Branch, reason)
+		text := ":rotating_light: Rollout이 자동으로 중단(abort)되었습니다."
+		if err != nil {
+			text = fmt.Sprintf(":rotating_light: Rollout 자동 중단(abort)에 실패했습니다. 즉시 확인이 필요합니다.\n> %v", err)
+		}
+		w.finish(ctx, status, deployment.StatusFailed, diagnostics, bakeResultBlock(text))
+	default:
+		w.finish(ctx, status, deployment.StatusFailed, diagnostics, bakeResultBlock(":rotating_light: 이전 배포 이력으로 자동 롤백합니다."))
+		w.rollback(ctx, reason)
+	}
+}
+
+// rollback 완료된 배포를 이전 배포 이력으로 롤백. 배포 잠금 해제 이후 호출한다.
+func (w rolloutWatch) rollback(ctx context.Context, reason string) {
+	target := rollbackTarget{
+		Application:  w.rollout.Application,
+		Namespace:    w.service.ApplicationNamespace,
+		Environment:  w.service.Branch,
+		Org:          w.service.Org,
+		ArgoCD:       w.argo.Name,
+		DeploymentID: w.deploymentID,
+	}
+	if d, exist := deployments.Get(w.deploymentID); exist {
+		target.Repo = d.Repo
+		target.PreviousHistoryID = d.PreviousHistoryID
+	}
+
+	notify := func(ctx context.Context, text string) {
+		if err := w.message.post(ctx, generateSlackTextBlock(text), "롤백 진행 상황"); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("rolloutWatch | failed to send rollback message: %s", w.rollout.Application)
+		}
+	}
+
+	if _, err := startRollback(ctx, target, nil, automationActor, reason, notify); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("rolloutWatch | failed to start automatic rollback of %s", w.rollout.Application)
+	}
+}
+
+func degradedProblem(status *rollouts.Status) string {
+	if status.Aborted {
+		return fmt.Sprintf("Rollout 중단(abort) `%s` %s", status.Phase, status.Message)
+	}
+	return fmt.Sprintf("Rollout `%s` %s", status.Phase, status.Message)
+}
+
+func bakeResultBlock(text string) slack.Block {
+	return slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil)
+}
@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/pipeline"
+	"github.com/antonio-kim-1994/devops-relay/server/rollouts"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"time"
+)
+
+const (
+	// responseURLLimit Slack response_url 사용 가능 횟수 및 사용 가능 기간
+	responseURLLimit    = 5
+	responseURLLifetime = 30 * time.Minute
+	// 승인 버튼 클릭 이후 Server가 승인을 처리하기까지의 지연을 고려한 response_url 만료 여유 시간
+	responseURLLeeway = time.Minute
+)
+
+// rolloutWatch 승인 이후 Rollout 진행 상황 추적
+// 진행 상황은 승인 요청 메시지에 표시하고, 완료/실패 시 소요 시간과 함께 결과 메시지를 전송한다.
+type rolloutWatch struct {
+	argo         *argocd.Instance
+	rollout      rollouts.Rollout
+	service      ServiceInfo
+	deploymentID string
+	approver     string
+	// 배포 요청 시각 (배포 기록이 없는 경우 승인 시각)
+	requestedAt time.Time
+	approvedAt  time.Time
+	message     *progressMessage
+}
+
+// progressMessage Rollout 진행 상황을 표시할 승인 요청 메시지
+// Slack Bot으로 전송된 메시지는 chat.update로, Webhook 메시지는 response_url로 수정한다.
+type progressMessage struct {
+	channel     string
+	timestamp   string
+	responseURL string
+	// response_url 사용 횟수 및 만료 시각. 배포 기록이 있는 경우 배포 기록에 사용 횟수를 저장한다.
+	deploymentID string
+	uses         int
+	expiresAt    time.Time
+	// response_url을 사용할 수 없는 경우 결과 메시지를 전송할 애플리케이션 Slack 채널 (Slack Bot)
+	fallbackChannel string
+}
+
+// newRolloutWatch 승인 버튼 응답 기준 추적 대상 생성
+// response_url 사용 횟수는 배포 기록 기준으로 이어가며(재기동 이후 재개 포함), 배포 기록이 없는 경우
+// Gateway의 승인 접수 메시지와 approveDeployment의 승인 결과 메시지로 2회를 사용한 상태로 시작한다.
+func newRolloutWatch(r SlackResponse, argo *argocd.Instance, rollout rollouts.Rollout) rolloutWatch {
+	now := time.Now()
+	w := rolloutWatch{
+		argo:    argo,
+		rollout: rollout,
+		service: ServiceInfo{
+			Org:                  r.Button.Org,
+			Branch:               r.Button.Branch,
+			ApplicationName:      r.Button.ApplicationName,
+			ApplicationNamespace: r.Button.ApplicationNamespace,
+		},
+		deploymentID: r.Button.DeploymentID,
+		approver:     r.User.Name,
+		requestedAt:  now,
+		approvedAt:   now,
+		message: &progressMessage{
+			responseURL:     r.ResponseURL,
+			deploymentID:    r.Button.DeploymentID,
+			uses:            2,
+			fallbackChannel: applications.Get(r.Button.ApplicationName).SlackChannel,
+		},
+	}
+
+	if d, exist := deployments.Get(r.Button.DeploymentID); exist {
+		w.requestedAt = d.CreatedAt
+		w.message.channel = d.SlackChannel
+		w.message.timestamp = d.SlackTimestamp
+		w.message.uses = max(w.message.uses, d.ResponseURLUses)
+		if !d.ApprovedAt.IsZero() {
+			w.approvedAt = d.ApprovedAt
+		}
+	}
+	w.message.expiresAt = w.approvedAt.Add(responseURLLifetime - responseURLLeeway)
+	return w
+}
+
+// run Rollout 완료, 실패, 추가 승인 대기 혹은 시간 초과 시까지 진행 상황 추적
+// 배포 잠금은 추적이 끝날 때까지 유지하며, 추가 승인이 필요한 단계에서 멈춘 경우 승인 대기 상태로 되돌린다.
+// bake가 활성화된 경우 진행 중 문제가 감지되면 Rollout을 중단하고, 완료 이후 bake 기간 동안 상태를 확인한다.
+func (w rolloutWatch) run(ctx context.Context, cfg config.RolloutConfig) {
+	watchCtx, cancel := context.WithTimeout(ctx, cfg.WatchTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(cfg.WatchInterval)
+	defer ticker.Stop()
+
+	controller := rolloutController(w.argo)
+	var last *rollouts.Status
+	failures := 0
+	for {
+		select {
+		case <-watchCtx.Done():
+			w.stop(watchCtx, last)
+			return
+		case <-ticker.C:
+		}
+
+		status, err := controller.Status(watchCtx, w.rollout)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("rolloutWatch | failed to get rollout status: %s", w.rollout)
+			continue
+		}
+
+		switch {
+		case status.Completed():
+			if cfg.Bake.Enabled {
+				w.bake(ctx, status, cfg.Bake)
+				return
+			}
+			w.finish(ctx, status, deployment.StatusSucceeded)
+			return
+		case status.Failed():
+			if cfg.Bake.Enabled {
+				w.remediate(ctx, status, []string{degradedProblem(status)}, cfg.Bake)
+				return
+			}
+			w.finish(ctx, status, deployment.StatusFailed)
+			return
+		case status.AwaitingPromotion:
+			w.awaitPromotion(ctx, status)
+			return
+		}
+
+		if cfg.Bake.Enabled {
+			if problems := w.check(watchCtx, status, cfg.Bake); len(problems) > 0 {
+				failures++
+				log.Ctx(ctx).Warn().Msgf("rolloutWatch | problems detected in rollout %s (%d/%d): %v", w.rollout, failures, cfg.Bake.FailureThreshold, problems)
+				if failures >= cfg.Bake.FailureThreshold {
+					w.remediate(ctx, status, problems, cfg.Bake)
+					return
+				}
+			} else {
+				failures = 0
+			}
+		}
+
+		if last == nil || progressChanged(last, status) {
+			blocks := rolloutProgressBlocks(w, status, fmt.Sprintf(":hourglass_flowing_sand: *운영 배포 진행 중* | *%s*", w.rollout.Application), rolloutControlBlock(w.service, w.deploymentID))
+			if err := w.message.update(ctx, blocks, "운영 배포 진행 중", false); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msgf("rolloutWatch | failed to update progress message: %s", w.rollout)
+			}
+		}
+		last = status
+	}
+}
+
+// finish Rollout 완료/실패 결과를 승인 요청 메시지와 채널에 전송하고 배포 종료
+func (w rolloutWatch) finish(ctx context.Context, status *rollouts.Status, result deployment.Status, details ...slack.Block) {
+	title := fmt.Sprintf(":white_check_mark: *운영 배포 완료* | *%s*", w.rollout.Application)
+	text := "운영 배포 완료"
+	if result == deployment.StatusFailed {
+		title = fmt.Sprintf(":x: *운영 배포 실패* | *%s*", w.rollout.Application)
+		text = "운영 배포 실패"
+	}
+
+	log.Ctx(ctx).Info().Msgf("rolloutWatch | rollout %s finished: %s (%s)", w.rollout, result, status.Phase)
+	blocks := append(details, rollbackButton(w.service.Org, w.service.Branch, w.service.ApplicationName, w.service.ApplicationNamespace, w.deploymentID))
+	w.report(ctx, rolloutProgressBlocks(w, status, title, blocks...), text)
+
+	if w.deploymentID != "" {
+		deployments.Finish(w.deploymentID, result)
+	}
+}
+
+// awaitPromotion 추가 승인이 필요한 단계에서 멈춘 경우 승인 버튼을 다시 표시하고 승인 대기 상태로 전환
+func (w rolloutWatch) awaitPromotion(ctx context.Context, status *rollouts.Status) {
+	blocks := rolloutProgressBlocks(w, status,
+		fmt.Sprintf(":double_vertical_bar: *운영 배포 승인 대기* | *%s* Rollout이 다음 단계 승인을 기다리고 있습니다.", w.rollout.Application),
+		approvalButtons(w.service, w.deploymentID),
+		rolloutControlBlock(w.service, w.deploymentID),
+	)
+	if err := w.message.update(ctx, blocks, "운영 배포 승인 대기", true); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("rolloutWatch | failed to update progress message: %s", w.rollout)
+	}
+
+	if w.deploymentID != "" {
+		deployments.AwaitApproval(w.deploymentID)
+	}
+}
+
+// stop 시간 초과 혹은 이후 배포 요청으로 대체되어 추적 중단
+// 서버 종료로 중단된 경우 배포를 종료하지 않고 재기동 시 추적을 재개한다.
+func (w rolloutWatch) stop(ctx context.Context, last *rollouts.Status) {
+	cause := context.Cause(ctx)
+	if errors.Is(cause, pipeline.ErrShutdown) {
+		log.Ctx(ctx).Warn().Msgf("rolloutWatch | stop watching rollout %s for shutdown, resume on restart", w.rollout)
+		return
+	}
+
+	if last == nil {
+		last = &rollouts.Status{}
+	}
+
+	title := fmt.Sprintf(":warning: *운영 배포 확인 시간 초과* | *%s* Rollout이 제한 시간 내에 완료되지 않았습니다. Rollout 상태를 확인하세요.", w.rollout.Application)
+	result := deployment.StatusFailed
+	if !errors.Is(cause, context.DeadlineExceeded) {
+		title = fmt.Sprintf(":heavy_minus_sign: *운영 배포 추적 중단* | *%s* (%v)", w.rollout.Application, cause)
+	}
+
+	log.Ctx(ctx).Warn().Err(cause).Msgf("rolloutWatch | stop watching rollout %s", w.rollout)
+	w.report(ctx, rolloutProgressBlocks(w, last, title, rollbackButton(w.service.Org, w.service.Branch, w.service.ApplicationName, w.service.ApplicationNamespace, w.deploymentID)), "운영 배포 추적 종료")
+
+	if w.deploymentID != "" {
+		deployments.Finish(w.deploymentID, result)
+	}
+}
+
+// report 승인 요청 메시지를 최종 결과로 수정하고 채널에 결과 메시지 전송
+func (w rolloutWatch) report(ctx context.Context, blocks slack.Blocks, text string) {
+	if err := w.message.update(ctx, blocks, text, true); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("rolloutWatch | failed to update progress message: %s", w.rollout)
+	}
+	if err := w.message.post(ctx, blocks, text); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("rolloutWatch | failed to post result message: %s", w.rollout)
+	}
+}
+
+// progressChanged 메시지 수정이 필요한 진행 상황 변경 여부
+func progressChanged(before, after *rollouts.Status) bool {
+	return before.Phase != after.Phase ||
+		before.CurrentStep != after.CurrentStep ||
+		before.Weight != after.Weight ||
+		before.ReadyReplicas != after.ReadyReplicas ||
+		before.AvailableReplicas != after.AvailableReplicas ||
+		before.UpdatedReplicas != after.UpdatedReplicas ||
+		before.AnalysisRun != after.AnalysisRun ||
+		before.AnalysisStatus != after.AnalysisStatus
+}
+
+// update 메시지 수정. response_url은 사용 횟수 제한이 있으므로 최종 결과 전송을 위해 2회(최종 수정 시 1회)를 남겨둔다.
+func (m *progressMessage) update(ctx context.Context, blocks slack.Blocks, text string, final bool) error {
+	if slackClient != nil && m.channel != "" && m.timestamp != "" {
+		return updateSlackMessage(ctx, m.channel, m.timestamp, blocks, text)
+	}
+
+	reserve := 2
+	if final {
+		reserve = 1
+	}
+	if !m.responseURLAvailable(reserve) {
+		return nil
+	}
+
+	m.used()
+	reply := slackResponseForm{url: m.responseURL, msg: blocks, replaceOption: true}
+	return reply.sendResponseToSlack(ctx)
+}
+
+// post 승인 요청 메시지가 전송된 채널에 새 메시지 전송
+// response_url 사용 횟수를 모두 사용했거나 만료된 경우 Slack Bot으로 애플리케이션 채널에 전송한다.
+func (m *progressMessage) post(ctx context.Context, blocks slack.Blocks, text string) error {
+	if slackClient != nil && m.channel != "" {
+		_, _, err := postSlackMessage(ctx, m.channel, blocks, text)
+		return err
+	}
+
+	if m.responseURLAvailable(0) {
+		m.used()
+		reply := slackResponseForm{url: m.responseURL, msg: blocks}
+		return reply.sendResponseToSlack(ctx)
+	}
+
+	if slackClient != nil && m.fallbackChannel != "" {
+		_, _, err := postSlackMessage(ctx, m.fallbackChannel, blocks, text)
+		return err
+	}
+	return errors.New("no slack bot channel or usable response_url available")
+}
+
+// responseURLAvailable reserve 횟수를 남기고 response_url을 사용할 수 있는지 여부 (사용 가능 기간 이내)
+func (m *progressMessage) responseURLAvailable(reserve int) bool {
+	return m.responseURL != "" && responseURLLimit-m.uses > reserve && time.Now().Before(m.expiresAt)
+}
+
+// used response_url 사용 기록
+func (m *progressMessage) used() {
+	m.uses++
+	if m.deploymentID != "" {
+		uses := m.uses
+		deployments.Update(m.deploymentID, func(d *deployment.Deployment) { d.ResponseURLUses = uses })
+	}
+}
+
+// countResponseURLUse 승인 버튼 응답 response_url 사용 횟수를 배포 기록에 저장
+func countResponseURLUse(id string) {
+	if id != "" {
+		deployments.Update(id, func(d *deployment.Deployment) { d.ResponseURLUses++ })
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/rollouts"
+	"testing"
+	"time"
+)
+
+// setupRolloutWatch 기본 애플리케이션 설정 및 배포 기록
+func setupRolloutWatch(t *testing.T, d *deployment.Deployment) {
+	t.Helper()
+	apps, err := config.LoadApplications("")
+	if err != nil {
+		t.Fatalf("LoadApplications: %v", err)
+	}
+
+	prevApps, prevDeployments := applications, deployments
+	applications, deployments = apps, deployment.NewRegistry()
+	t.Cleanup(func() { applications, deployments = prevApps, prevDeployments })
+
+	if d != nil {
+		if _, err := deployments.Register(*d); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+}
+
+func approvalResponse(id string) SlackResponse {
+	return SlackResponse{
+		Button:      ButtonValue{ApplicationName: "homepage-front", ApplicationNamespace: "homepage", Branch: "prod", DeploymentID: id},
+		User:        User{Name: "dev-lead"},
+		ResponseURL: "https://hooks.slack.com/actions/T000/1/abc",
+	}
+}
+
+func TestRolloutWatchResponseURLUses(t *testing.T) {
+	cases := []struct {
+		name   string
+		record *deployment.Deployment
+		// 추적 시작 시 최종 결과 전송 전까지 사용할 수 있는 횟수
+		updates int
+	}{
+		// 배포 기록이 없으면 승인 접수, 승인 결과 메시지로 2회 사용한 상태로 시작한다.
+		{name: "without record", updates: 1},
+		{name: "recorded uses", record: &deployment.Deployment{ID: "dep-1", ResponseURLUses: 2}, updates: 1},
+		// 재기동 이후 재개된 추적은 이전 추적의 사용 횟수를 이어간다.
+		{name: "resumed watch", record: &deployment.Deployment{ID: "dep-1", ResponseURLUses: 4}, updates: 0},
+		{name: "exhausted", record: &deployment.Deployment{ID: "dep-1", ResponseURLUses: 5}, updates: -1},
+		// 사용 횟수가 기록되지 않은 이전 배포 기록
+		{name: "legacy record", record: &deployment.Deployment{ID: "dep-1"}, updates: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			id := ""
+			if tc.record != nil {
+				id = tc.record.ID
+				tc.record.Application, tc.record.Environment, tc.record.ApprovedAt = "homepage-front", "prod", time.Now()
+			}
+			setupRolloutWatch(t, tc.record)
+
+			m := newRolloutWatch(approvalResponse(id), nil, rollouts.Rollout{}).message
+			for i := 0; i < tc.updates; i++ {
+				if !m.responseURLAvailable(2) {
+					t.Fatalf("update %d: response_url unavailable (uses %d)", i+1, m.uses)
+				}
+				m.used()
+			}
+			if m.responseURLAvailable(2) {
+				t.Errorf("uses = %d, want progress updates exhausted", m.uses)
+			}
+			// 최종 결과 메시지 1회는 남겨둔다.
+			if got := m.responseURLAvailable(0); got != (tc.updates >= 0) {
+				t.Errorf("final message available = %t, want %t", got, tc.updates >= 0)
+			}
+
+			if tc.record != nil {
+				d, _ := deployments.Get(id)
+				if d.ResponseURLUses != m.uses {
+					t.Errorf("recorded uses = %d, want %d", d.ResponseURLUses, m.uses)
+				}
+			}
+		})
+	}
+}
+
+func TestRolloutWatchResponseURLExpired(t *testing.T) {
+	setupRolloutWatch(t, &deployment.Deployment{ID: "dep-1", Application: "homepage-front", Environment: "prod", ApprovedAt: time.Now().Add(-responseURLLifetime)})
+
+	if m := newRolloutWatch(approvalResponse("dep-1"), nil, rollouts.Rollout{}).message; m.responseURLAvailable(0) {
+		t.Error("response_url available after lifetime")
+	}
+}
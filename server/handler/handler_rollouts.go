@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"github.com/antonio-kim-1994/devops-relay/server/audit"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/requestid"
+	"github.com/antonio-kim-1994/devops-relay/server/rollouts"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var rolloutActionNames = map[rollouts.Action]string{
+	rollouts.ActionPromote:     "한 단계 승인",
+	rollouts.ActionPromoteFull: "전체 승인",
+	rollouts.ActionPause:       "일시정지",
+	rollouts.ActionResume:      "재개",
+	rollouts.ActionAbort:       "중단",
+	rollouts.ActionRetry:       "재시도",
+	rollouts.ActionRestart:     "재시작",
+	rollouts.ActionSetWeight:   "가중치 설정",
+}
+
+// rolloutController ArgoCD 인스턴스의 Argo Rollouts 제어 백엔드
+func rolloutController(instance *argocd.Instance) rollouts.Controller {
+	return rolloutControllers[instance.Name]
+}
+
+// resolveRollout ArgoCD Application resource tree에서 애플리케이션의 Rollout 조회
+func resolveRollout(ctx context.Context, instance *argocd.Instance, appName, namespace string) (rollouts.Rollout, error) {
+	return rollouts.Resolve(ctx, instance.Client, appName, namespace)
+}
+
+// HandleRolloutAction POST /apps/:app/rollouts/:action
+func HandleRolloutAction(c *gin.Context) {
+	var req RolloutActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Ctx(c.Request.Context()).Error().Err(err).Msg("HandleRolloutAction | failed to bind rollout action request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "failed to get rollout action request",
+			"status":  "failed",
+		})
+		return
+	}
+
+	action, err := rollouts.ParseAction(c.Param("action"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "unknown rollout action",
+			"error":   fmt.Sprintf("%v", err),
+			"status":  "failed",
+		})
+		return
+	}
+
+	var weight int32
+	if action == rollouts.ActionSetWeight {
+		if req.Weight == nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": "weight is required for set-weight",
+				"status":  "failed",
+			})
+			return
+		}
+		weight = *req.Weight
+	}
+
+	appName := c.Param("app")
+
+	// 배포를 진행시키는 액션은 최근 배포 요청 기준으로 동결 기간 및 배포 정책 확인
+	latest, _ := deployments.Latest(appName, req.Environment)
+	environment := req.Environment
+	if environment == "" {
+		environment = latest.Environment
+	}
+	if environment == "" && !rolloutStopActions[action] {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("environment is required for %s", action),
+			"status":  "failed",
+		})
+		return
+	}
+
+	s := rolloutServiceInfo(latest, ServiceInfo{
+		Branch:               environment,
+		ApplicationName:      appName,
+		ApplicationNamespace: req.Namespace,
+		Operator:             req.Operator,
+		BreakGlass:           req.BreakGlass,
+		BreakGlassReason:     req.Reason,
+	})
+	if code, reason := admitRolloutAction(s, action, verifyBreakGlassToken(c.GetHeader(breakGlassHeader))); reason != "" {
+		log.Ctx(c.Request.Context()).Warn().Msgf("HandleRolloutAction | %s - Application: %s, Environment: %s", reason, appName, environment)
+		status := "blocked"
+		if code == http.StatusForbidden {
+			status = "denied"
+		}
+		c.JSON(code, gin.H{
+			"message": reason,
+			"status":  status,
+		})
+		return
+	}
+
+	argo, err := argoInstances.Get(applications.Get(appName).ArgoCD)
+	if err != nil {
+		log.Ctx(c.Request.Context()).Error().Err(err).Msgf("HandleRolloutAction | failed to get argocd instance: %s", appName)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": "failed to get argocd instance",
+			"error":   fmt.Sprintf("%v", err),
+			"status":  "failed",
+		})
+		return
+	}
+
+	target := rollouts.Rollout{Application: appName, Namespace: req.Namespace, Name: req.Rollout}
+	if target.Name == "" {
+		target, err = resolveRollout(c.Request.Context(), argo, appName, req.Namespace)
+		if err != nil {
+			log.Ctx(c.Request.Context()).Error().Err(err).Msgf("HandleRolloutAction | failed to resolve rollout: %s", appName)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"message": "failed to resolve rollout",
+				"error":   fmt.Sprintf("%v", err),
+				"status":  "failed",
+			})
+			return
+		}
+	}
+
+	if err := runRolloutAction(c.Request.Context(), argo, target, action, weight, req.Operator, environment, req.Reason); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, rollouts.ErrInvalidWeight) || errors.Is(err, rollouts.ErrWeightNotInSteps) || errors.Is(err, rollouts.ErrSetWeightUnsupported) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"message": fmt.Sprintf("failed to %s rollout", action),
+			"error":   fmt.Sprintf("%v", err),
+			"status":  "failed",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("%s | %s succeeded", target, action),
+		"status":  "success",
+	})
+}
+
+// handleSlackRolloutAction Slack Rollout 제어 버튼 처리
+// 버튼 값: Org/Branch/ApplicationName/ApplicationNamespace/rollout/<action>/DeploymentID[/weight][/RequestID]
+func handleSlackRolloutAction(c *gin.Context, r SlackResponse, argo *argocd.Instance) {
+	action, err := rollouts.ParseAction(r.Button.Result)
+	if err != nil {
+		log.Ctx(c.Request.Context()).Error().Err(err).Msgf("handleSlackRolloutAction | invalid rollout action: %s", r.Button.Result)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "unknown rollout action",
+			"status":  "failed",
+		})
+		return
+	}
+
+	var weight int32
+	if action == rollouts.ActionSetWeight {
+		w, err := strconv.ParseInt(r.Button.Argument, 10, 32)
+		if err != nil {
+			log.Ctx(c.Request.Context()).Error().Err(err).Msgf("handleSlackRolloutAction | invalid canary weight: %s", r.Button.Argument)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": "invalid canary weight",
+				"status":  "failed",
+			})
+			return
+		}
+		weight = int32(w)
+	}
+
+	ctx := withRequestScope(context.Background(), c.Request.Context())
+	reply := func(text string) {
+		reply := slackResponseForm{url: r.ResponseURL, msg: generateSlackTextBlock(text)}
+		if err := reply.sendResponseToSlack(ctx); err != nil {
+			log.Ctx(ctx).Err(err).Msgf("handleSlackRolloutAction | failed to send result message to slack: %s", r.Button.ApplicationName)
+		}
+	}
+
+	// 승인 요청 메시지는 채널 전체에 노출되므로 배포 승인자 혹은 관리자만 Rollout을 제어할 수 있다.
+	d, _ := deployments.Get(r.Button.DeploymentID)
+	if !authorizeSlackRolloutAction(d, r.User.Name) {
+		log.Ctx(ctx).Warn().Msgf("handleSlackRolloutAction | unauthorized rollout %s by %s: %s", action, r.User.Name, r.Button.ApplicationName)
+		reply(fmt.Sprintf(":no_entry_sign: *Rollout %s 권한 없음* | 배포 승인자 혹은 관리자만 *%s* Rollout을 제어할 수 있습니다. (요청자: %s)", rolloutActionNames[action], r.Button.ApplicationName, r.User.Name))
+		c.JSON(http.StatusForbidden, gin.H{
+			"message": fmt.Sprintf("rollout %s requires the deployment approver or an admin", action),
+			"status":  "denied",
+		})
+		return
+	}
+
+	// Slack 버튼은 break-glass 토큰을 전달할 수 없으므로 동결 기간에는 진행 액션을 허용하지 않는다.
+	s := rolloutServiceInfo(d, ServiceInfo{
+		Org:                  r.Button.Org,
+		Branch:               r.Button.Branch,
+		ApplicationName:      r.Button.ApplicationName,
+		ApplicationNamespace: r.Button.ApplicationNamespace,
+		Operator:             r.User.Name,
+		DeploymentID:         r.Button.DeploymentID,
+	})
+	if code, reason := admitRolloutAction(s, action, false); reason != "" {
+		log.Ctx(ctx).Warn().Msgf("handleSlackRolloutAction | %s - Application: %s", reason, r.Button.ApplicationName)
+		reply(fmt.Sprintf(":no_entry_sign: *Rollout %s 차단* | *%s* Rollout %s 요청이 차단되었습니다.\n> %s", rolloutActionNames[action], r.Button.ApplicationName, rolloutActionNames[action], reason))
+		c.JSON(code, gin.H{
+			"message": reason,
+			"status":  "blocked",
+		})
+		return
+	}
+
+	target, err := resolveRollout(ctx, argo, r.Button.ApplicationName, r.Button.ApplicationNamespace)
+	text := fmt.Sprintf(":gear: *Rollout %s* | *%s* 사용자에 의해 *%s* Rollout %s 요청이 처리되었습니다.", rolloutActionNames[action], r.User.Name, r.Button.ApplicationName, rolloutActionNames[action])
+	if action == rollouts.ActionSetWeight {
+		text = fmt.Sprintf(":gear: *Rollout 가중치 설정* | *%s* 사용자에 의해 *%s* Canary 가중치가 `%d%%`로 설정되었습니다.", r.User.Name, r.Button.ApplicationName, weight)
+	}
+
+	if err == nil {
+		err = runRolloutAction(ctx, argo, target, action, weight, r.User.Name, r.Button.Branch, "Slack rollout button")
+	}
+	if err != nil {
+		text = fmt.Sprintf(":x: *Rollout %s 실패* | *%s* Rollout %s 요청에 실패했습니다.\n> %v", rolloutActionNames[action], r.Button.ApplicationName, rolloutActionNames[action], err)
+	}
+
+	reply(text)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("failed to %s rollout", action),
+			"error":   fmt.Sprintf("%v", err),
+			"status":  "failed",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("%s | %s succeeded", target, action),
+		"status":  "success",
+	})
+}
+
+// rolloutStopActions 변경을 멈추는 액션으로, 동결 기간 및 배포 정책과 무관하게 허용한다.
+var rolloutStopActions = map[rollouts.Action]bool{
+	rollouts.ActionPause: true,
+	rollouts.ActionAbort: true,
+}
+
+// authorizeSlackRolloutAction Slack Rollout 제어 버튼 사용 권한 확인
+// 배포 승인자 혹은 관리자(RELAY_ADMINS)만 허용하며, 승인 이전에는 관리자만 허용한다.
+func authorizeSlackRolloutAction(d deployment.Deployment, user string) bool {
+	if relayConfig.IsAdmin(user) {
+		return true
+	}
+	return d.Approver != "" && strings.EqualFold(d.Approver, user)
+}
+
+// rolloutServiceInfo Rollout 액션의 동결 기간 및 배포 정책 확인 대상
+// 배포 기록의 원본 요청(조직, 태그 등)에서 요청자와 break-glass 여부만 바꿔 확인하며, 원본 요청이 없으면 fallback을 사용한다.
+func rolloutServiceInfo(d deployment.Deployment, fallback ServiceInfo) ServiceInfo {
+	if len(d.Request) == 0 {
+		return fallback
+	}
+
+	var s ServiceInfo
+	if err := json.Unmarshal(d.Request, &s); err != nil {
+		log.Error().Err(err).Msgf("rolloutServiceInfo | failed to parse deploy request: %s", d.ID)
+		return fallback
+	}
+	s.Operator = fallback.Operator
+	s.BreakGlass = fallback.BreakGlass
+	s.BreakGlassReason = fallback.BreakGlassReason
+	if s.DeploymentID == "" {
+		s.DeploymentID = d.ID
+	}
+	return s
+}
+
+// admitRolloutAction Rollout 액션 실행 전 동결 기간 및 배포 정책 확인
+// 배포를 진행시키는 액션(promote, resume, retry, restart, set-weight)만 확인하며, 차단 시 응답 코드와 사유를 반환한다.
+func admitRolloutAction(s ServiceInfo, action rollouts.Action, verified bool) (int, string) {
+	if rolloutStopActions[action] {
+		return http.StatusOK, ""
+	}
+
+	if freeze, reason := checkDeployFreeze(s, verified); freeze != nil {
+		return http.StatusLocked, reason
+	}
+
+	if decision := evaluateDeployPolicy(s, verified); !decision.Allowed {
+		return http.StatusForbidden, fmt.Sprintf("rollout %s denied by policy: %s", action, decision.Reason())
+	}
+	return http.StatusOK, ""
+}
+
+// runRolloutAction Rollout 액션 실행 및 감사 로그 기록
+func runRolloutAction(ctx context.Context, argo *argocd.Instance, target rollouts.Rollout, action rollouts.Action, weight int32, operator, environment, reason string) error {
+	err := rollouts.Run(ctx, rolloutController(argo), target, action, weight)
+
+	detail := map[string]string{
+		"rollout": target.String(),
+		"argocd":  argo.Name,
+		"result":  "succeeded",
+	}
+	if action == rollouts.ActionSetWeight {
+		detail["weight"] = strconv.Itoa(int(weight))
+	}
+	if err != nil {
+		detail["result"] = "failed"
+		detail["error"] = err.Error()
+		log.Ctx(ctx).Error().Err(err).Msgf("runRolloutAction | failed to %s rollout %s", action, target)
+	} else {
+		log.Ctx(ctx).Info().Msgf("runRolloutAction | %s rollout %s by %s", action, target, operator)
+	}
+
+	auditErr := audit.Record(audit.Entry{
+		Action:      fmt.Sprintf("rollout.%s", action),
+		Actor:       operator,
+		Application: target.Application,
+		Environment: environment,
+		Reason:      reason,
+		RequestID:   requestid.From(ctx),
+		Detail:      detail,
+	})
+	if auditErr != nil {
+		log.Ctx(ctx).Error().Err(auditErr).Msgf("runRolloutAction | failed to record rollout audit entry: %s", target)
+	}
+	return err
+}
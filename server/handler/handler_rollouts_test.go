@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/policy"
+	"github.com/antonio-kim-1994/devops-relay/server/rollouts"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupRolloutPolicy 허용된 조직만 배포할 수 있는 배포 정책
+func setupRolloutPolicy(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	content := "policies:\n  - {name: allowed-orgs, rule: 'request.org in [\"org-a\"]', message: 허용되지 않은 조직입니다.}\n"
+	if err := os.WriteFile(filepath.Join(dir, "deploy_policies.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	e, err := policy.Load(dir, time.UTC)
+	if err != nil {
+		t.Fatalf("policy.Load: %v", err)
+	}
+
+	prev := policyEngine
+	policyEngine = e
+	t.Cleanup(func() { policyEngine = prev })
+}
+
+func TestAuthorizeSlackRolloutAction(t *testing.T) {
+	setupFreeze(t)
+
+	cases := []struct {
+		name     string
+		approver string
+		user     string
+		want     bool
+	}{
+		{name: "approver", approver: "dev-lead", user: "Dev-Lead", want: true},
+		{name: "admin", approver: "dev-lead", user: "devops-admin", want: true},
+		{name: "other user", approver: "dev-lead", user: "dev-user", want: false},
+		// 승인 이전에는 관리자만 제어할 수 있다.
+		{name: "before approval", user: "dev-user", want: false},
+		{name: "admin before approval", user: "devops-admin", want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := deployment.Deployment{ID: "dep-1", Approver: tc.approver}
+			if got := authorizeSlackRolloutAction(d, tc.user); got != tc.want {
+				t.Errorf("authorizeSlackRolloutAction(%q, %q) = %t, want %t", tc.approver, tc.user, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRolloutServiceInfo(t *testing.T) {
+	request, _ := json.Marshal(ServiceInfo{Org: "org-a", Branch: "prod", ApplicationName: "homepage-front", DockerTag: "v1.2.3", Operator: "deployer", BreakGlass: true})
+	fallback := ServiceInfo{Branch: "prod", ApplicationName: "homepage-front", Operator: "dev-lead"}
+
+	s := rolloutServiceInfo(deployment.Deployment{ID: "dep-1", Request: request}, fallback)
+	// 원본 요청의 조직, 태그를 유지하고 요청자와 break-glass 여부는 Rollout 요청 기준으로 바꾼다.
+	if s.Org != "org-a" || s.DockerTag != "v1.2.3" || s.Operator != "dev-lead" || s.BreakGlass || s.DeploymentID != "dep-1" {
+		t.Errorf("service info = %+v", s)
+	}
+
+	if s := rolloutServiceInfo(deployment.Deployment{ID: "dep-2"}, fallback); s != fallback {
+		t.Errorf("service info = %+v, want fallback", s)
+	}
+}
+
+func TestAdmitRolloutAction(t *testing.T) {
+	setupFreeze(t)
+	setupRolloutPolicy(t)
+
+	cases := []struct {
+		name     string
+		service  ServiceInfo
+		action   rollouts.Action
+		verified bool
+		code     int
+		reason   string
+	}{
+		{
+			name:    "allowed",
+			service: ServiceInfo{Org: "org-a", Branch: "dev", ApplicationName: "homepage-front", Operator: "dev-user"},
+			action:  rollouts.ActionPromoteFull,
+			code:    http.StatusOK,
+		},
+		{
+			name:    "frozen promote",
+			service: ServiceInfo{Org: "org-a", Branch: "prod", ApplicationName: "homepage-front", Operator: "dev-user"},
+			action:  rollouts.ActionPromote,
+			code:    http.StatusLocked,
+			reason:  "테스트 동결 기간",
+		},
+		{
+			name:    "frozen set-weight",
+			service: ServiceInfo{Org: "org-a", Branch: "prod", ApplicationName: "homepage-front", Operator: "dev-user"},
+			action:  rollouts.ActionSetWeight,
+			code:    http.StatusLocked,
+			reason:  "테스트 동결 기간",
+		},
+		{
+			name:    "break-glass without token",
+			service: ServiceInfo{Org: "org-a", Branch: "prod", ApplicationName: "homepage-front", Operator: "devops-admin", BreakGlass: true, BreakGlassReason: "장애 대응"},
+			action:  rollouts.ActionResume,
+			code:    http.StatusLocked,
+			reason:  "requires a valid break-glass token",
+		},
+		{
+			name:     "break-glass",
+			service:  ServiceInfo{Org: "org-a", Branch: "prod", ApplicationName: "homepage-front", Operator: "devops-admin", BreakGlass: true, BreakGlassReason: "장애 대응"},
+			action:   rollouts.ActionRetry,
+			verified: true,
+			code:     http.StatusOK,
+		},
+		{
+			name:    "policy denied",
+			service: ServiceInfo{Org: "org-x", Branch: "dev", ApplicationName: "homepage-front", Operator: "dev-user"},
+			action:  rollouts.ActionRestart,
+			code:    http.StatusForbidden,
+			reason:  "rollout restart denied by policy: [allowed-orgs] 허용되지 않은 조직입니다.",
+		},
+		// 변경을 멈추는 액션은 동결 기간 및 배포 정책과 무관하게 허용한다.
+		{
+			name:    "frozen abort",
+			service: ServiceInfo{Org: "org-x", Branch: "prod", ApplicationName: "homepage-front", Operator: "dev-user"},
+			action:  rollouts.ActionAbort,
+			code:    http.StatusOK,
+		},
+		{
+			name:    "frozen pause",
+			service: ServiceInfo{Org: "org-x", Branch: "prod", ApplicationName: "homepage-front", Operator: "dev-user"},
+			action:  rollouts.ActionPause,
+			code:    http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, reason := admitRolloutAction(tc.service, tc.action, tc.verified)
+			if code != tc.code || !strings.Contains(reason, tc.reason) || (tc.reason == "") != (reason == "") {
+				t.Errorf("admitRolloutAction = %d, %q, want %d, %q", code, reason, tc.code, tc.reason)
+			}
+		})
+	}
+}
+
+func TestHandleRolloutActionBlocked(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setupFreeze(t)
+
+	prev := deployments
+	deployments = deployment.NewRegistry()
+	t.Cleanup(func() { deployments = prev })
+
+	request, _ := json.Marshal(ServiceInfo{Org: "org-a", Branch: "prod", ApplicationName: "homepage-front"})
+	if _, err := deployments.Register(deployment.Deployment{ID: "dep-1", Application: "homepage-front", Environment: "prod", Request: request}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		app    string
+		action string
+		body   string
+		token  string
+		code   int
+		want   string
+	}{
+		{
+			// environment 미지정 시 최근 배포 기록의 환경(prod) 기준으로 확인한다.
+			name:   "frozen",
+			app:    "homepage-front",
+			action: "promote-full",
+			body:   `{"operator": "dev-user", "namespace": "homepage"}`,
+			code:   http.StatusLocked,
+			want:   "테스트 동결 기간",
+		},
+		{
+			name:   "break-glass without token",
+			app:    "homepage-front",
+			action: "resume",
+			body:   `{"operator": "devops-admin", "namespace": "homepage", "break_glass": true, "reason": "장애 대응"}`,
+			token:  "guess",
+			code:   http.StatusLocked,
+			want:   "requires a valid break-glass token",
+		},
+		{
+			name:   "unknown environment",
+			app:    "cms-front",
+			action: "promote",
+			body:   `{"operator": "dev-user", "namespace": "cms"}`,
+			code:   http.StatusBadRequest,
+			want:   "environment is required for promote",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/apps/:app/rollouts/:action", HandleRolloutAction)
+
+			req := httptest.NewRequest(http.MethodPost, "/apps/"+tc.app+"/rollouts/"+tc.action, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.token != "" {
+				req.Header.Set(breakGlassHeader, tc.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tc.code || !strings.Contains(w.Body.String(), tc.want) {
+				t.Errorf("response = %d %s, want %d %q", w.Code, w.Body.String(), tc.code, tc.want)
+			}
+		})
+	}
+}
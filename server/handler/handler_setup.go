@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/analysis"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"github.com/antonio-kim-1994/devops-relay/server/audit"
+	"github.com/antonio-kim-1994/devops-relay/server/calendar"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"github.com/antonio-kim-1994/devops-relay/server/datadog"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/diagnostics"
+	"github.com/antonio-kim-1994/devops-relay/server/dora"
+	"github.com/antonio-kim-1994/devops-relay/server/metrics"
+	"github.com/antonio-kim-1994/devops-relay/server/pipeline"
+	"github.com/antonio-kim-1994/devops-relay/server/policy"
+	"github.com/antonio-kim-1994/devops-relay/server/rollouts"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
+	"github.com/antonio-kim-1994/devops-relay/server/webhook"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"net/http"
+	"os"
+	"time"
+)
+
+var (
+	relayConfig    *config.Config
+	applications   *config.Applications
+	changeCalendar *calendar.Calendar
+	policyEngine   *policy.Engine
+	deployments    = deployment.NewRegistry()
+	// 배포 파이프라인 워커 풀 (HTTP 요청과 분리해 실행)
+	pipelines     *pipeline.Pool
+	argoInstances *argocd.Registry
+	// ArgoCD 인스턴스별 Argo Rollouts 제어 백엔드
+	rolloutControllers = map[string]rollouts.Controller{}
+	// ArgoCD 인스턴스별 Kubernetes 진단 정보 수집 (Health Check 실패 시)
+	diagnosticCollectors = map[string]*diagnostics.Collector{}
+	// Canary 분석 메트릭 조회 대상 (prometheus, datadog)
+	metricProviders = map[string]analysis.Provider{}
+	// SLACK_BOT_TOKEN이 설정된 경우 승인 요청 메시지 수정(chat.update)에 사용
+	slackClient *slack.Client
+	// DATADOG_EVENTS_ENABLED 설정 시 배포 lifecycle 이벤트 및 배포 메트릭 전송
+	datadogEvents *datadog.Client
+	// DORA 지표 계산용 종료된 배포 기록 및 주간 보고 중단
+	doraStore      *dora.Store
+	stopDORADigest context.CancelFunc
+	// 배포 lifecycle 이벤트 외부 webhook 구독 및 전송
+	webhooks *webhook.Dispatcher
+)
+
+// Setup 핸들러에서 사용하는 설정 및 의존성 초기화
+func Setup(cfg *config.Config) error {
+	relayConfig = cfg
+
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return fmt.Errorf("Setup | failed to load timezone %s: %w", cfg.Timezone, err)
+	}
+
+	applications, err = config.LoadApplications(cfg.ApplicationsPath)
+	if err != nil {
+		return fmt.Errorf("Setup | failed to load applications config: %w", err)
+	}
+
+	// 프로젝트 API 토큰이 설정된 인스턴스는 세션 로그인 없이 사용
+	argoInstances = argocd.NewRegistry(cfg.DefaultArgoCDInstance)
+	for name, instance := range cfg.ArgoCDInstances {
+		argo := argoInstances.Add(name, argocd.InstanceOptions{
+			Options: argocd.Options{
+				BaseURL:  instance.URL,
+				APIToken: instance.APIToken,
+				Username: instance.Username,
+				Password: instance.Password,
+				Timeout:  instance.Timeout,
+			},
+			RolloutsURL: instance.RolloutsURL,
+		})
+
+		switch instance.RolloutsBackend {
+		case "kubernetes":
+			controller, err := rollouts.NewKubernetesFromConfig(instance.Kubeconfig, instance.KubeContext)
+			if err != nil {
+				return fmt.Errorf("Setup | failed to create rollouts controller for argocd instance %s: %w", name, err)
+			}
+			rolloutControllers[name] = rollouts.Instrument(controller, "kubernetes")
+		default:
+			rolloutControllers[name] = rollouts.Instrument(rollouts.NewDashboard(argo.RolloutsURL, argo.RolloutsClient, argo.Client), "dashboard")
+		}
+
+		// 클러스터 접근 권한이 없는 환경에서는 진단 정보 없이 Health Check 실패만 알린다.
+		collector, err := diagnostics.NewCollectorFromConfig(instance.Kubeconfig, instance.KubeContext)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Setup | kubernetes diagnostics disabled for argocd instance %s", name)
+			continue
+		}
+		diagnosticCollectors[name] = collector
+	}
+
+	for name, instance := range applications.ArgoCDInstances() {
+		if _, err := argoInstances.Get(instance); err != nil {
+			return fmt.Errorf("Setup | invalid argocd instance for application %s: %w", name, err)
+		}
+	}
+
+	if cfg.PrometheusURL != "" {
+		metricProviders["prometheus"] = analysis.NewPrometheus(cfg.PrometheusURL, &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport("prometheus", nil)})
+	}
+	if cfg.Datadog.APIKey != "" && cfg.Datadog.AppKey != "" {
+		metricProviders["datadog"] = analysis.NewDatadog("", cfg.Datadog.Site, cfg.Datadog.APIKey, cfg.Datadog.AppKey, &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport("datadog", nil)})
+	}
+	for name, provider := range applications.AnalysisProviders() {
+		if _, exist := metricProviders[provider]; !exist {
+			return fmt.Errorf("Setup | metric provider %s for application %s is not configured", provider, name)
+		}
+	}
+
+	if token := os.Getenv("SLACK_BOT_TOKEN"); token != "" {
+		slackClient = slack.New(token)
+	}
+
+	if err := audit.Setting(cfg.AuditLogPath); err != nil {
+		return fmt.Errorf("Setup | failed to set audit log: %w", err)
+	}
+
+	changeCalendar, err = calendar.Load(cfg.ChangeCalendarPath, location)
+	if err != nil {
+		return fmt.Errorf("Setup | failed to load change calendar: %w", err)
+	}
+
+	policyEngine, err = policy.Load(cfg.PolicyDir, location)
+	if err != nil {
+		return fmt.Errorf("Setup | failed to load deploy policies: %w", err)
+	}
+
+	// 서버 종료로 중단된 배포 기록을 복원해 파이프라인 재개
+	deployments, err = deployment.Open(cfg.DeploymentStatePath)
+	if err != nil {
+		return fmt.Errorf("Setup | failed to load deployment state: %w", err)
+	}
+	if cfg.Datadog.Events {
+		hostname, _ := os.Hostname()
+		datadogEvents = datadog.New(datadog.Options{
+			Site:          cfg.Datadog.Site,
+			APIKey:        cfg.Datadog.APIKey,
+			Tags:          cfg.Datadog.Tags,
+			Host:          hostname,
+			FlushInterval: cfg.Datadog.FlushInterval,
+			HTTPClient:    &http.Client{Timeout: 10 * time.Second, Transport: tracing.Transport("datadog", nil)},
+		})
+		client := datadogEvents
+		deployments.Subscribe(func(e deployment.Event) { sendDatadogEvent(client, e) })
+	}
+
+	if err := setupDORA(cfg.DORA); err != nil {
+		return err
+	}
+	if err := setupWebhooks(cfg.Webhook); err != nil {
+		return err
+	}
+
+	pipelines = pipeline.NewPool(cfg.PipelineWorkers, cfg.PipelineQueueSize)
+	metrics.RegisterPipeline(func() (int64, int64, int64) {
+		stats := pipelines.Stats()
+		return int64(stats.Queued), stats.Running, stats.Tasks
+	})
+	resumeDeployments()
+
+	return nil
+}
@@ -1,7 +1,15 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/pipeline"
+	"github.com/antonio-kim-1994/devops-relay/server/rollouts"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 	"net/http"
@@ -10,7 +18,7 @@ import (
 func HandleSlackResponse(c *gin.Context) {
 	var r SlackResponse
 	if err := c.ShouldBindJSON(&r); err != nil {
-		log.Err(err).Msg("HandleSlackResponse | failed to bind request body")
+		log.Ctx(c.Request.Context()).Err(err).Msg("HandleSlackResponse | failed to bind request body")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "failed to get service info",
 			"status":  "failed",
@@ -18,57 +26,104 @@ func HandleSlackResponse(c *gin.Context) {
 		return
 	}
 
-	switch r.Button.Result {
-	case "approve":
-		healthCheckResult, h := serviceHealthCheck(r.Button.ApplicationName, r.Button.ApplicationNamespace)
-		if !healthCheckResult {
-			log.Error().Msg("HandleSlackResponse | failed to send health check fail message")
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": "failed to send health check fail message",
-				"status":  "failed",
-			})
-			err := sendHealthCheckFailMessage(r.Button.ApplicationName, r.ResponseURL, *h)
-			if err != nil {
-				log.Error().Err(err).Msg("HandleSlackResponse | failed to send health check fail message")
-				return
-			}
-		}
-		err := promoteApplication(fmt.Sprintf("%s-rollout", r.Button.ApplicationName), r.Button.ApplicationNamespace)
-		if err != nil {
-			log.Error().Err(err).Msgf("HandleSlackResponse | failed to promote application: %s", r.Button.ApplicationName)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": "failed to promote application",
-				"status":  "failed",
+	// 버튼의 배포 ID, 배포를 시작한 요청 ID를 요청 로거에 추가해 GitHub 배포 요청 로그와 연결
+	logger := log.Ctx(c.Request.Context()).With().
+		Str("deployment_id", r.Button.DeploymentID).
+		Str("origin_request_id", r.Button.RequestID).
+		Logger()
+	c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context()))
+
+	// 롤백 버튼은 승인 대기 상태와 무관하게 처리
+	if r.Button.Result == "rollback" {
+		handleSlackRollback(c, r)
+		return
+	}
+
+	argo, err := argoInstanceOf(r.Button.ApplicationName, r.Button.DeploymentID)
+	if err != nil {
+		log.Ctx(c.Request.Context()).Error().Err(err).Msgf("HandleSlackResponse | failed to get argocd instance: %s", r.Button.ApplicationName)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": "failed to get argocd instance",
+			"status":  "failed",
+		})
+		return
+	}
+
+	// Rollout 제어 버튼은 승인 대기 상태와 무관하게 처리
+	if r.Button.RequestType == "rollout" {
+		handleSlackRolloutAction(c, r, argo)
+		return
+	}
+
+	// 배포 ID가 포함된 승인 요청인 경우 배포 잠금 상태 확인
+	// 승인 처리는 요청 이후에도 계속되므로 요청 context는 trace 연결에만 사용한다.
+	ctx := withRequestScope(context.Background(), c.Request.Context())
+	result := deployment.StatusFailed
+	locked := false
+	// 승인 처리를 워커에 등록한 경우 배포 종료는 워커에서 처리
+	started := false
+	if id := r.Button.DeploymentID; id != "" {
+		resumed, err := deployments.Resume(id)
+		switch {
+		case err == nil:
+			ctx = withRequestScope(resumed, c.Request.Context())
+			locked = true
+			defer func() {
+				if !started {
+					deployments.Finish(id, result)
+				}
+			}()
+		case errors.Is(err, deployment.ErrNotFound):
+			// 배포 기록이 없는 경우 기존 방식으로 처리
+			log.Ctx(ctx).Warn().Msgf("HandleSlackResponse | deployment %s not found, processing without deploy lock", id)
+		default:
+			log.Ctx(ctx).Warn().Err(err).Msgf("HandleSlackResponse | ignore %s request for deployment %s", r.Button.Result, id)
+			replyObsoleteApproval(ctx, r, err)
+			c.JSON(http.StatusConflict, gin.H{
+				"message":       "approval request is no longer valid",
+				"error":         fmt.Sprintf("%v", err),
+				"deployment_id": id,
+				"status":        "failed",
 			})
 			return
 		}
+	}
 
-		reply := slackResponseForm{
-			url:           r.ResponseURL,
-			msg:           generateSlackTextBlock(fmt.Sprintf(":white_check_mark: *운영 배포 승인* | *%s* 사용자에 의해 *%s* 배포가 승인되었습니다.", r.User.Name, r.Button.ApplicationName)),
-			replaceOption: true,
-		}
-
-		err = reply.sendResponseToSlack()
-		if err != nil {
-			log.Err(err).Msgf("HandleSlackResponse | failed to send result message to slack: %s", r.Button.ApplicationName)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": "failed to send result message to slack",
-				"status":  "failed",
+	switch r.Button.Result {
+	case "approve", "approve-full":
+		// Health Check, promote 및 Rollout 추적은 워커에서 수행하고 즉시 응답
+		if err := startApproval(ctx, r, argo, locked); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("HandleSlackResponse | failed to start approval of %s", r.Button.ApplicationName)
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"message":       "failed to start approval",
+				"error":         fmt.Sprintf("%v", err),
+				"deployment_id": r.Button.DeploymentID,
+				"status":        "failed",
 			})
 			return
 		}
+		started = true
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":       fmt.Sprintf("%s | approval accepted", r.Button.ApplicationName),
+			"deployment_id": r.Button.DeploymentID,
+			"status":        "accepted",
+		})
 		return
 	case "reject":
-		err := abortApplication(fmt.Sprintf("%s-rollout", r.Button.ApplicationName), r.Button.ApplicationNamespace)
+		rollout, err := resolveRollout(ctx, argo, r.Button.ApplicationName, r.Button.ApplicationNamespace)
+		if err == nil {
+			err = rolloutController(argo).Abort(ctx, rollout)
+		}
 		if err != nil {
-			log.Error().Err(err).Msgf("HandleSlackResponse | failed to abort application: %s", r.Button.ApplicationName)
+			log.Ctx(ctx).Error().Err(err).Msgf("HandleSlackResponse | failed to abort application: %s", r.Button.ApplicationName)
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"message": "failed to abort application",
 				"status":  "failed",
 			})
 			return
 		}
+		result = deployment.StatusRejected
 
 		reply := slackResponseForm{
 			url:           r.ResponseURL,
@@ -76,9 +131,9 @@ func HandleSlackResponse(c *gin.Context) {
 			replaceOption: true,
 		}
 
-		err = reply.sendResponseToSlack()
+		err = reply.sendResponseToSlack(ctx)
 		if err != nil {
-			log.Err(err).Msgf("HandleSlackResponse | failed to send result message to slack: %s", r.Button.ApplicationName)
+			log.Ctx(ctx).Err(err).Msgf("HandleSlackResponse | failed to send result message to slack: %s", r.Button.ApplicationName)
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"message": "failed to send result message to slack",
 				"status":  "failed",
@@ -88,3 +143,146 @@ func HandleSlackResponse(c *gin.Context) {
 		return
 	}
 }
+
+// startApproval 승인 처리를 워커 풀에 등록
+// 배포 잠금을 보유한 경우 승인 정보를 배포 기록에 저장해 서버 재기동 시 재개할 수 있도록 한다.
+func startApproval(lock context.Context, r SlackResponse, argo *argocd.Instance, locked bool) error {
+	if !locked {
+		return pipelines.Submit(func(ctx context.Context) {
+			if _, err := approveDeployment(withRequestScope(ctx, lock), lock, r, argo); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msgf("startApproval | approval of %s failed", r.Button.ApplicationName)
+			}
+		})
+	}
+
+	approval, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("startApproval | failed to encode slack response: %w", err)
+	}
+
+	deployments.Approve(r.Button.DeploymentID, r.User.Name, func(d *deployment.Deployment) {
+		d.Stage = deployment.StagePromote
+		d.Approval = approval
+		// Gateway의 승인 접수 메시지
+		d.ResponseURLUses = 1
+		d.TraceParent = tracing.TraceParent(lock)
+	})
+	return startPipeline(r.Button.DeploymentID, lock, runApprovalPipeline)
+}
+
+// runApprovalPipeline 승인 처리 파이프라인 실행. Rollout 추적 단계에서 재개된 경우 추적만 다시 시작한다.
+func runApprovalPipeline(ctx, lock context.Context, d deployment.Deployment) (deployment.Status, error) {
+	var r SlackResponse
+	if err := json.Unmarshal(d.Approval, &r); err != nil {
+		return deployment.StatusFailed, fmt.Errorf("runApprovalPipeline | failed to decode slack response: %w", err)
+	}
+
+	argo, err := argoInstances.Get(d.ArgoCD)
+	if err != nil {
+		return deployment.StatusFailed, fmt.Errorf("runApprovalPipeline | failed to get argocd instance: %w", err)
+	}
+
+	if d.Stage == deployment.StageRolloutWatch {
+		rollout, err := resolveRollout(ctx, argo, r.Button.ApplicationName, r.Button.ApplicationNamespace)
+		if err != nil {
+			return deployment.StatusFailed, fmt.Errorf("runApprovalPipeline | failed to resolve rollout: %w", err)
+		}
+		startRolloutWatch(ctx, lock, r, argo, rollout)
+		return deployment.StatusRunning, nil
+	}
+	return approveDeployment(ctx, lock, r, argo)
+}
+
+// approveDeployment Health Check 이후 Rollout promote 및 진행 상황 추적 시작
+// 추적이 시작된 경우 배포 잠금을 유지하도록 StatusRunning을 반환한다.
+func approveDeployment(ctx, lock context.Context, r SlackResponse, argo *argocd.Instance) (deployment.Status, error) {
+	id := r.Button.DeploymentID
+	h := serviceHealthCheck(ctx, r.Button.ApplicationName, r.Button.ApplicationNamespace)
+	if ctx.Err() != nil {
+		if !errors.Is(context.Cause(ctx), pipeline.ErrShutdown) {
+			replyObsoleteApproval(ctx, r, context.Cause(ctx))
+			countResponseURLUse(id)
+		}
+		return deployment.StatusFailed, context.Cause(ctx)
+	}
+	if id != "" {
+		deployments.SetHealthCheck(id, h)
+	}
+	if !h.Healthy {
+		report := collectDiagnostics(ctx, argo, r.Button.ApplicationName, r.Button.ApplicationNamespace, id)
+		err := sendHealthCheckFailMessage(ctx, r.Button.ApplicationName, r.ResponseURL, h, report, rollbackButton(r.Button.Org, r.Button.Branch, r.Button.ApplicationName, r.Button.ApplicationNamespace, id))
+		countResponseURLUse(id)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("approveDeployment | failed to send health check fail message")
+		}
+		uploadDiagnostics(ctx, report, r.Button.ApplicationName, id)
+		return deployment.StatusFailed, fmt.Errorf("approveDeployment | server health check failed after %d attempts", len(h.Attempts))
+	}
+
+	// approve: 다음 단계로 진행, approve-full: 남은 단계를 건너뛰고 전체 배포
+	rollout, err := resolveRollout(ctx, argo, r.Button.ApplicationName, r.Button.ApplicationNamespace)
+	if err == nil {
+		err = rolloutController(argo).Promote(ctx, rollout, r.Button.Result == "approve-full")
+	}
+	if err != nil {
+		return deployment.StatusFailed, fmt.Errorf("approveDeployment | failed to promote application %s: %w", r.Button.ApplicationName, err)
+	}
+	if id != "" {
+		deployments.Update(id, func(d *deployment.Deployment) { d.Stage = deployment.StageRolloutWatch })
+	}
+
+	msg := generateSlackTextBlock(fmt.Sprintf(":white_check_mark: *운영 배포 승인* | *%s* 사용자에 의해 *%s* 배포가 승인되었습니다.", r.User.Name, r.Button.ApplicationName))
+	msg.BlockSet = append(msg.BlockSet, rollbackButton(r.Button.Org, r.Button.Branch, r.Button.ApplicationName, r.Button.ApplicationNamespace, id))
+	reply := slackResponseForm{
+		url:           r.ResponseURL,
+		msg:           msg,
+		replaceOption: true,
+	}
+	if err := reply.sendResponseToSlack(ctx); err != nil {
+		log.Ctx(ctx).Err(err).Msgf("approveDeployment | failed to send result message to slack: %s", r.Button.ApplicationName)
+	}
+	countResponseURLUse(id)
+
+	startRolloutWatch(ctx, lock, r, argo, rollout)
+	return deployment.StatusRunning, nil
+}
+
+// startRolloutWatch 워커를 점유하지 않는 작업으로 Rollout 진행 상황 추적 시작
+// 서버 종료 중이라 시작하지 못한 경우 배포 기록에 남은 추적 단계에서 재기동 시 재개된다.
+// parent는 trace 연결에만 사용한다.
+func startRolloutWatch(parent, lock context.Context, r SlackResponse, argo *argocd.Instance, rollout rollouts.Rollout) {
+	err := pipelines.Go(func(taskCtx context.Context) {
+		ctx, cancel := withDeployment(withRequestScope(taskCtx, parent), lock)
+		defer cancel()
+		newRolloutWatch(r, argo, rollout).run(ctx, applications.Get(r.Button.ApplicationName).Rollout)
+	})
+	if err != nil {
+		log.Warn().Err(err).Msgf("startRolloutWatch | failed to watch rollout %s", rollout)
+	}
+}
+
+// argoInstanceOf 배포 기록의 ArgoCD 인스턴스. 배포 기록이 없는 경우 애플리케이션 설정 기준으로 조회한다.
+func argoInstanceOf(appName, deploymentID string) (*argocd.Instance, error) {
+	if d, exist := deployments.Get(deploymentID); exist && d.ArgoCD != "" {
+		return argoInstances.Get(d.ArgoCD)
+	}
+	return argoInstances.Get(applications.Get(appName).ArgoCD)
+}
+
+// replyObsoleteApproval 대체되었거나 이미 처리된 승인 요청 메시지의 버튼 제거
+func replyObsoleteApproval(ctx context.Context, r SlackResponse, reason error) {
+	text := fmt.Sprintf(":heavy_minus_sign: *만료된 배포 승인 요청* | *%s* 배포 요청은 더 이상 유효하지 않습니다. (%v)", r.Button.ApplicationName, reason)
+	if d, exist := deployments.Get(r.Button.DeploymentID); exist && d.SupersededBy != "" {
+		text = fmt.Sprintf(":heavy_minus_sign: *만료된 배포 승인 요청* | *%s* 배포 요청이 이후 요청된 배포(`%s`)로 대체되었습니다.", r.Button.ApplicationName, d.SupersededBy)
+	}
+
+	reply := slackResponseForm{
+		url:           r.ResponseURL,
+		msg:           generateSlackTextBlock(text),
+		replaceOption: true,
+	}
+
+	if err := reply.sendResponseToSlack(ctx); err != nil {
+		log.Ctx(ctx).Err(err).Msgf("replyObsoleteApproval | failed to send obsolete message to slack: %s", r.Button.ApplicationName)
+	}
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/audit"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/logging"
+	"github.com/antonio-kim-1994/devops-relay/server/requestid"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
+	"github.com/antonio-kim-1994/devops-relay/server/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"slices"
+)
+
+// webhookSource CloudEvents source
+const webhookSource = "/devops-relay/server"
+
+// webhookEvents 구독 가능한 배포 lifecycle 이벤트
+var webhookEvents = []deployment.EventType{
+	deployment.EventQueued,
+	deployment.EventStarted,
+	deployment.EventStageChanged,
+	deployment.EventHealthCheckFailed,
+	deployment.EventApprovalRequested,
+	deployment.EventApproved,
+	deployment.EventFinished,
+}
+
+// setupWebhooks webhook 구독 및 전송 기록 복원, 배포 lifecycle 이벤트 구독
+func setupWebhooks(cfg config.WebhookConfig) error {
+	dispatcher, err := webhook.Open(webhook.Options{
+		StatePath:            cfg.StatePath,
+		Workers:              cfg.Workers,
+		MaxAttempts:          cfg.MaxAttempts,
+		AllowPrivateNetworks: cfg.AllowPrivateNetworks,
+		HTTPClient:           &http.Client{Timeout: cfg.Timeout, Transport: tracing.Transport("webhook", webhook.NewTransport(cfg.AllowPrivateNetworks))},
+	})
+	if err != nil {
+		return fmt.Errorf("setupWebhooks | failed to load webhook state: %w", err)
+	}
+	webhooks = dispatcher
+	deployments.Subscribe(publishWebhookEvent)
+	return nil
+}
+
+// publishWebhookEvent 배포 lifecycle 이벤트를 CloudEvents로 변환해 구독별 전송 대기열에 등록
+// 배포 기록(data)의 커밋 메시지 등에 포함된 URL 비밀 값 및 토큰은 마스킹해 전송한다.
+func publishWebhookEvent(e deployment.Event) {
+	d := e.Deployment
+	data, err := json.Marshal(d)
+	if err != nil {
+		log.Error().Err(err).Msgf("publishWebhookEvent | failed to encode deployment %s", d.ID)
+		return
+	}
+
+	webhooks.Publish(webhook.Event{
+		SpecVersion:     webhook.SpecVersion,
+		ID:              webhook.NewID("evt-"),
+		Source:          webhookSource,
+		Type:            webhook.TypePrefix + string(e.Type),
+		Subject:         d.ID,
+		Time:            e.Time,
+		DataContentType: "application/json",
+		Application:     d.Application,
+		Environment:     d.Environment,
+		TraceParent:     d.TraceParent,
+		Data:            logging.RedactJSON(data),
+	})
+}
+
+// HandleWebhookCreate POST /webhooks
+// webhook 구독 등록. 서명 secret은 등록 응답에서만 확인할 수 있다.
+func HandleWebhookCreate(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("HandleWebhookCreate | failed to bind webhook request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "failed to get webhook request",
+			"status":  "failed",
+		})
+		return
+	}
+
+	for _, event := range req.Events {
+		if event != "*" && !slices.Contains(webhookEvents, deployment.EventType(event)) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": fmt.Sprintf("unknown event %q", event),
+				"events":  webhookEvents,
+				"status":  "failed",
+			})
+			return
+		}
+	}
+
+	sub, err := webhooks.Subscribe(webhook.Subscription{
+		URL:          req.URL,
+		Events:       req.Events,
+		Applications: req.Applications,
+		Environments: req.Environments,
+		Description:  req.Description,
+		Secret:       req.Secret,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+			"status":  "failed",
+		})
+		return
+	}
+
+	recordWebhookAudit(ctx, "webhook.create", map[string]string{"webhook_id": sub.ID, "url": logging.Redact(sub.URL)})
+	log.Ctx(ctx).Info().Msgf("HandleWebhookCreate | webhook %s subscribed: %s", sub.ID, logging.Redact(sub.URL))
+
+	secret := sub.Secret
+	sub.Secret = ""
+	c.JSON(http.StatusCreated, gin.H{
+		"webhook": sub,
+		"secret":  secret,
+		"status":  "success",
+	})
+}
+
+// HandleWebhookList GET /webhooks
+func HandleWebhookList(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": webhooks.Subscriptions(),
+		"status":   "success",
+	})
+}
+
+// HandleWebhookGet GET /webhooks/:id
+func HandleWebhookGet(c *gin.Context) {
+	sub, exist := webhooks.Subscription(c.Param("id"))
+	if !exist {
+		webhookNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhook": sub,
+		"status":  "success",
+	})
+}
+
+// HandleWebhookDelete DELETE /webhooks/:id
+// 재시도 대기 중인 전송은 실패로 종료하며, 전송 기록은 유지한다.
+func HandleWebhookDelete(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	if err := webhooks.Unsubscribe(id); err != nil {
+		webhookNotFound(c)
+		return
+	}
+
+	recordWebhookAudit(ctx, "webhook.delete", map[string]string{"webhook_id": id})
+	log.Ctx(ctx).Info().Msgf("HandleWebhookDelete | webhook %s unsubscribed", id)
+	c.JSON(http.StatusOK, gin.H{
+		"webhook_id": id,
+		"status":     "success",
+	})
+}
+
+// HandleWebhookDeliveries GET /webhooks/:id/deliveries
+// 전송 기록 (최근 순, 시도별 응답 코드 및 오류 포함)
+func HandleWebhookDeliveries(c *gin.Context) {
+	id := c.Param("id")
+	if _, exist := webhooks.Subscription(id); !exist {
+		webhookNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": webhooks.Deliveries(id),
+		"status":     "success",
+	})
+}
+
+// HandleWebhookRedeliver POST /webhooks/:id/deliveries/:delivery/redeliver
+// 전송 기록의 이벤트를 새 전송으로 다시 전송 (같은 CloudEvents id)
+func HandleWebhookRedeliver(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	original, exist := webhooks.Delivery(c.Param("delivery"))
+	if !exist || original.SubscriptionID != id {
+		c.JSON(http.StatusNotFound, gin.H{
+			"message":     "webhook delivery not found",
+			"delivery_id": c.Param("delivery"),
+			"status":      "failed",
+		})
+		return
+	}
+
+	delivery, err := webhooks.Redeliver(original.ID)
+	if errors.Is(err, webhook.ErrNotFound) {
+		webhookNotFound(c)
+		return
+	}
+
+	recordWebhookAudit(ctx, "webhook.redeliver", map[string]string{"webhook_id": id, "delivery_id": delivery.ID, "redelivery_of": original.ID})
+	c.JSON(http.StatusAccepted, gin.H{
+		"delivery": delivery,
+		"status":   "accepted",
+	})
+}
+
+// recordWebhookAudit webhook 구독 변경 및 재전송 감사 로그 기록 (API 인증 토큰 기준으로 수행자를 구분할 수 없어 api로 기록)
+func recordWebhookAudit(ctx context.Context, action string, detail map[string]string) {
+	err := audit.Record(audit.Entry{
+		Action:    action,
+		Actor:     "api",
+		RequestID: requestid.From(ctx),
+		Detail:    detail,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("recordWebhookAudit | failed to record %s audit entry", action)
+	}
+}
+
+func webhookNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"message":    "webhook not found",
+		"webhook_id": c.Param("id"),
+		"status":     "failed",
+	})
+}
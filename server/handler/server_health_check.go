@@ -1,20 +1,18 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/metrics"
+	"github.com/antonio-kim-1994/devops-relay/server/probe"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
-	"net/http"
-	"os"
-	"strconv"
+	"go.opentelemetry.io/otel/attribute"
 	"time"
 )
 
-type HealthCheck struct {
-	limits   int
-	interval time.Duration
-}
-
 func CommonHealthCheck(c *gin.Context) {
 	c.JSON(200, gin.H{
 		"status": "ok",
@@ -25,7 +23,7 @@ func CommonHealthCheck(c *gin.Context) {
 func ServerHealthCheck(c *gin.Context) {
 	var h HealthCheckRequest
 	if err := c.ShouldBindJSON(&h); err != nil {
-		log.Error().Err(err).Msg("ServerHealthCheck | failed to bind healthcheck data to json")
+		log.Ctx(c.Request.Context()).Error().Err(err).Msg("ServerHealthCheck | failed to bind healthcheck data to json")
 	}
 	c.JSON(200, gin.H{
 		"message": fmt.Sprintf("Service Name: %s, Org: %s, Branch: %s", h.ApplicationName, h.Org, h.Branch),
@@ -33,66 +31,67 @@ func ServerHealthCheck(c *gin.Context) {
 	return
 }
 
-func serviceHealthCheck(appName, namespace string) (bool, *HealthCheck) {
-	// HealthCheck Default Config
-	h := HealthCheck{
-		limits:   25,
-		interval: 5 * time.Second,
-	}
-
-	healthCheckLimits := os.Getenv("HEALTH_CHECK_LIMITS")
-	healthCheckInterval := os.Getenv("HEALTH_CHECK_INTERVAL")
-
-	// HEALTH_CHECK_LIMITS 파싱
-	if healthCheckLimits != "" {
-		if parsedLimits, err := strconv.Atoi(healthCheckLimits); err == nil {
-			h.limits = parsedLimits
-		} else {
-			log.Info().Msgf("serviceHealthCheck | No HEALTH_CHECK_LIMITS value served. Set Default value(%d)", h.limits)
+// serviceHealthCheck 애플리케이션 Probe 설정(횟수, 연속 성공, backoff)에 따라 Preview 서비스 Health Check 수행
+// 모든 시도 기록을 반환하며, 신규 배포로 대체되어 ctx가 취소된 경우 즉시 중단한다.
+func serviceHealthCheck(ctx context.Context, appName, namespace string) (result probe.Result) {
+	ctx, span := tracing.Start(ctx, "health_check",
+		attribute.String("deployment.application", appName),
+		attribute.String("k8s.namespace.name", namespace),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Bool("health_check.healthy", result.Healthy), attribute.Int("health_check.attempts", len(result.Attempts)))
+		var err error
+		if !result.Healthy && len(result.Attempts) > 0 {
+			err = errors.New(result.Attempts[len(result.Attempts)-1].Error)
 		}
+		tracing.End(span, err)
+	}()
+
+	cfg := applications.Get(appName).Probe
+	policy := probe.Policy{
+		Attempts:         cfg.Attempts,
+		SuccessThreshold: cfg.SuccessThreshold,
+		Interval:         cfg.Interval,
+		MaxInterval:      cfg.MaxInterval,
+		Multiplier:       cfg.BackoffMultiplier,
 	}
 
-	// HEALTH_CHECK_INTERVAL 파싱
-	if healthCheckInterval != "" {
-		if parsedInterval, err := strconv.Atoi(healthCheckInterval); err == nil {
-			h.interval = time.Duration(parsedInterval) * time.Second
-		} else {
-			log.Info().Msgf("serviceHealthCheck | No HEALTH_CHECK_INTERVAL value served. Set Default Value(%d)", h.interval)
+	prober, err := newServiceProber(appName, namespace)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("serviceHealthCheck | [%s] failed to create health probe", appName)
+		return probe.Result{
+			Policy:   policy,
+			Attempts: []probe.Attempt{{Number: 1, StartedAt: time.Now(), Error: err.Error()}},
 		}
 	}
 
-	// 기본값 설정
-	var url string
-	switch appName {
-	// Web Front
-	case "homepage-front", "cms-front", "mydata-front", "pms-front", "mydata-cms-front":
-		url = fmt.Sprintf("http://%s-preview.%s.svc.cluster.local/", appName, namespace)
-	// Back-end
+	result = probe.Run(ctx, prober, policy)
+	if !result.Canceled {
+		metrics.ObserveHealthCheck(appName, result.Healthy, len(result.Attempts))
+	}
+	switch {
+	case result.Canceled:
+		log.Ctx(ctx).Info().Msgf("serviceHealthCheck | [%s] health check canceled: %v", appName, context.Cause(ctx))
+	case result.Healthy:
+		log.Ctx(ctx).Info().Msgf("serviceHealthCheck | [%s] health check success: %s (%d attempts, %s)", appName, result.Target, len(result.Attempts), result.Elapsed.Round(time.Millisecond))
 	default:
-		url = fmt.Sprintf("http://%s-preview.%s.svc.cluster.local/healthz/healthcheck", appName, namespace)
+		last := result.Attempts[len(result.Attempts)-1]
+		log.Ctx(ctx).Error().Msgf("serviceHealthCheck | [%s] health check fail: %s (%d attempts, %s): %s", appName, result.Target, len(result.Attempts), result.Elapsed.Round(time.Millisecond), last.Error)
 	}
+	return result
+}
 
-	count := 0
-	for {
-		resp, err := http.Get(url)
-		if err != nil {
-			log.Info().Msgf("serviceHealthCheck | Status Code: %d | [%s] health check retry [%d]", resp.StatusCode, appName, count)
-		} else {
-			if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
-				log.Info().Msgf("serviceHealthCheck | Status Code: %d | [%s] health check success", resp.StatusCode, appName)
-				resp.Body.Close()
-				return true, &h
-			}
-			resp.Body.Close()
-		}
-
-		count++
+// newServiceProber 애플리케이션 Probe 설정 기준 Preview 서비스 Prober 생성
+func newServiceProber(appName, namespace string) (probe.Prober, error) {
+	return probe.New(applications.Get(appName).Probe, probe.Vars{Application: appName, Namespace: namespace})
+}
 
-		// Healthcheck 실패 메시지 전송을 위해 healthcheck 설정값 return
-		if count >= h.limits {
-			log.Error().Msgf("serviceHealthCheck | [%s] health check fail", appName)
-			return false, &h
-		}
-		time.Sleep(h.interval)
+// probeService Health Check 1회 수행 (bake 기간 상태 확인용)
+func probeService(ctx context.Context, appName, namespace string) error {
+	prober, err := newServiceProber(appName, namespace)
+	if err != nil {
+		return err
 	}
+	_, err = prober.Probe(ctx)
+	return err
 }
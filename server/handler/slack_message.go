@@ -1,17 +1,40 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/analysis"
+	"github.com/antonio-kim-1994/devops-relay/server/deployment"
+	"github.com/antonio-kim-1994/devops-relay/server/diagnostics"
+	"github.com/antonio-kim-1994/devops-relay/server/probe"
+	"github.com/antonio-kim-1994/devops-relay/server/rollouts"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
+	"github.com/rs/zerolog/log"
 	"github.com/slack-go/slack"
+	"go.opentelemetry.io/otel/attribute"
+	"strconv"
+	"strings"
 	"time"
 )
 
-func sendHealthCheckFailMessage(serviceName, slackWebhookUrl string, h HealthCheck, replaceOption ...bool) error {
+// healthCheckHistoryLimit Health Check 실패 메시지에 표시할 최근 시도 수
+const healthCheckHistoryLimit = 10
+
+// diagnosticsSummaryLimit Health Check 실패 메시지에 표시할 진단 요약 라인 수
+const diagnosticsSummaryLimit = 8
+
+// sendHealthCheckFailMessage Health Check 실패 메시지 전송. 최근 시도 기록 및 Kubernetes 진단 요약을 포함하며, rollback 버튼이 주어진 경우 메시지에 포함한다.
+func sendHealthCheckFailMessage(ctx context.Context, serviceName, slackWebhookUrl string, h probe.Result, report *diagnostics.Report, rollback *slack.ActionBlock, replaceOption ...bool) error {
 	if slackWebhookUrl == "" {
 		return errors.New("slack webhook url is empty")
 	}
 
+	guide := "업데이트 예정 서비스의 Health Check를 실패했습니다.\n서비스 배포를 위해 관리자(@devops)에 문의하세요."
+	if report != nil {
+		guide = "업데이트 예정 서비스의 Health Check를 실패했습니다.\n아래 Kubernetes 진단 정보를 확인하고, 해결되지 않는 경우 관리자(@devops)에 문의하세요."
+	}
+
 	// Message Block
 	blocks := slack.Blocks{
 		BlockSet: []slack.Block{
@@ -22,23 +45,41 @@ func sendHealthCheckFailMessage(serviceName, slackWebhookUrl string, h HealthChe
 			),
 			slack.NewDividerBlock(),
 			slack.NewSectionBlock(
-				slack.NewTextBlockObject("mrkdwn", "업데이트 예정 서비스의 Health Check를 실패했습니다.\n서비스 배포를 위해 관리자(@devops)에 문의하세요.", false, false),
+				slack.NewTextBlockObject("mrkdwn", guide, false, false),
 				nil,
 				nil,
 			),
 			slack.NewSectionBlock(
 				slack.NewTextBlockObject(
 					"mrkdwn",
-					fmt.Sprintf("> *대상 서비스*: %s\n> *Health Check 횟수*: `%d 회`\n> *Health Check 간격*: `%d 초`", serviceName, h.limits, int(h.interval/time.Second)),
+					fmt.Sprintf("> *대상 서비스*: %s\n> *Probe 대상*: `%s`\n> *시도 횟수*: `%d / %d 회` (연속 %d회 성공 필요)\n> *소요 시간*: `%s`",
+						serviceName, h.Target, len(h.Attempts), h.Policy.Attempts, h.Policy.SuccessThreshold, h.Elapsed.Round(time.Second)),
 					false,
 					false,
 				),
 				nil,
 				nil,
 			),
+			slack.NewSectionBlock(
+				slack.NewTextBlockObject("mrkdwn", healthCheckHistory(h), false, false),
+				nil,
+				nil,
+			),
 		},
 	}
 
+	if report != nil {
+		blocks.BlockSet = append(blocks.BlockSet, slack.NewSectionBlock(
+			slack.NewTextBlockObject("mrkdwn", diagnosticsSummary(*report), false, false),
+			nil,
+			nil,
+		))
+	}
+
+	if rollback != nil {
+		blocks.BlockSet = append(blocks.BlockSet, rollback)
+	}
+
 	replaceOriginal := false
 	if len(replaceOption) > 0 {
 		replaceOriginal = replaceOption[0]
@@ -46,7 +87,7 @@ func sendHealthCheckFailMessage(serviceName, slackWebhookUrl string, h HealthChe
 
 	msg := slack.WebhookMessage{Blocks: &blocks, ReplaceOriginal: replaceOriginal}
 
-	err := slack.PostWebhook(slackWebhookUrl, &msg)
+	err := postWebhook(ctx, slackWebhookUrl, &msg)
 	if err != nil {
 		return fmt.Errorf("sendHealthCheckFailMessage | failed to post slack webhook: %w", err)
 	}
@@ -54,8 +95,58 @@ func sendHealthCheckFailMessage(serviceName, slackWebhookUrl string, h HealthChe
 	return nil
 }
 
-func sendDeployRequestMessage(s ServiceInfo) error {
-	if s.SlackWebhookUrl == "" {
+// healthCheckHistory 최근 Health Check 시도 기록 (번호, 시각, 응답 시간, 상태, 오류)
+func healthCheckHistory(h probe.Result) string {
+	attempts := h.Attempts
+	if len(attempts) > healthCheckHistoryLimit {
+		attempts = attempts[len(attempts)-healthCheckHistoryLimit:]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*최근 시도 기록* (%d/%d)\n```", len(attempts), len(h.Attempts))
+	for _, a := range attempts {
+		status := a.Status
+		if status == "" {
+			status = "-"
+		}
+		line := fmt.Sprintf("#%-3d %s %8s  %-10s", a.Number, a.StartedAt.Format("15:04:05"), a.Latency.Round(time.Millisecond), status)
+		if a.Error != "" {
+			line += " " + truncate(a.Error, 120)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("```")
+	return b.String()
+}
+
+// diagnosticsSummary Kubernetes 진단 요약 (Pod Ready 수, 비정상 Pod, 최근 Warning 이벤트)
+func diagnosticsSummary(report diagnostics.Report) string {
+	lines := report.Summary()
+	if len(lines) > diagnosticsSummaryLimit {
+		lines = append(lines[:diagnosticsSummaryLimit], fmt.Sprintf("… 외 %d건", len(lines)-diagnosticsSummaryLimit))
+	}
+
+	var b strings.Builder
+	b.WriteString("*Kubernetes 진단*")
+	for _, line := range lines {
+		b.WriteString("\n> " + truncate(line, 200))
+	}
+	b.WriteString("\n_전체 이벤트 및 컨테이너 로그는 첨부 파일 혹은 배포 기록에서 확인하세요._")
+	return b.String()
+}
+
+func truncate(s string, n int) string {
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
+
+// sendDeployRequestMessage 운영 배포 승인 요청 메시지 전송
+// Slack Bot과 채널이 설정된 경우 chat.postMessage로 전송해 이후 메시지를 수정할 수 있도록 한다.
+// Canary 분석 결과(canary)가 있는 경우 승인 버튼 위에 결과 표를 표시한다.
+func sendDeployRequestMessage(ctx context.Context, s ServiceInfo, deploymentID, channel string, canary *analysis.Result) error {
+	if s.SlackWebhookUrl == "" && (slackClient == nil || channel == "") {
 		return errors.New("slack webhook url is empty")
 	}
 
@@ -64,8 +155,6 @@ func sendDeployRequestMessage(s ServiceInfo) error {
 	branch := fmt.Sprintf("*업데이트 브랜치:*\n`%s`", s.Branch)
 	date := fmt.Sprintf("*업데이트 일시:*\n%s", s.Date)
 	commit := fmt.Sprintf("*업데이트 내용*\n%s", s.CommitMessage)
-	approveBtn := fmt.Sprintf("%s/%s/%s/%s/deploy/approve", s.Org, s.Branch, s.ApplicationName, s.ApplicationNamespace)
-	rejectBtn := fmt.Sprintf("%s/%s/%s/%s/deploy/reject", s.Org, s.Branch, s.ApplicationName, s.ApplicationNamespace)
 
 	blocks := slack.Blocks{
 		BlockSet: []slack.Block{
@@ -92,29 +181,130 @@ func sendDeployRequestMessage(s ServiceInfo) error {
 				nil,
 				nil,
 			),
-			slack.NewActionBlock("action_block", // Action 블록 ID
-				slack.NewButtonBlockElement("approve", approveBtn,
-					slack.NewTextBlockObject("plain_text", "승인", true, false),
-				).WithStyle("primary"),
-				slack.NewButtonBlockElement("deny", rejectBtn,
-					slack.NewTextBlockObject("plain_text", "반려", true, false),
-				).WithStyle("danger"),
-			),
-			slack.NewContextBlock("context_block",
-				slack.NewTextBlockObject("mrkdwn", ":warning: *승인 버튼 클릭 시 신규 서비스가 배포됩니다.*\n:pushpin: *배포 과정에 장애가 발생한 경우 DevOps 팀에 문의주시기 바랍니다.*", false, false),
-			),
 		},
 	}
 
+	if canary != nil {
+		blocks.BlockSet = append(blocks.BlockSet, analysisTableBlock(*canary))
+	}
+	blocks.BlockSet = append(blocks.BlockSet,
+		approvalButtons(s, deploymentID),
+		rolloutControlBlock(s, deploymentID),
+		slack.NewContextBlock("context_block",
+			slack.NewTextBlockObject("mrkdwn", ":warning: *승인 버튼 클릭 시 신규 서비스가 배포됩니다.*\n:pushpin: *배포 과정에 장애가 발생한 경우 DevOps 팀에 문의주시기 바랍니다.*", false, false),
+			slack.NewTextBlockObject("mrkdwn", deploymentRef(deploymentID), false, false),
+		),
+	)
+
+	if slackClient != nil && channel != "" {
+		channelID, timestamp, err := postSlackMessage(ctx, channel, blocks, "Production 배포 승인 요청")
+		if err != nil {
+			return fmt.Errorf("sendDeployRequestMessage | failed to post slack message: %w", err)
+		}
+		deployments.SetApprovalMessage(deploymentID, channelID, timestamp)
+		return nil
+	}
+
 	msg := slack.WebhookMessage{Blocks: &blocks}
-	err := slack.PostWebhook(s.SlackWebhookUrl, &msg)
+	err := postWebhook(ctx, s.SlackWebhookUrl, &msg)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func sendUpdateSuccessMessage(s ServiceInfo) error {
+// buttonValue Slack 버튼 값 (ButtonValue 형식)
+// 배포를 시작한 요청 ID를 마지막 항목으로 포함해 버튼 클릭을 원본 GitHub 배포 요청과 연결한다.
+func buttonValue(b ButtonValue) string {
+	value := strings.Join([]string{b.Org, b.Branch, b.ApplicationName, b.ApplicationNamespace, b.RequestType, b.Result, b.DeploymentID}, "/")
+	if d, exist := deployments.Get(b.DeploymentID); exist && d.RequestID != "" {
+		return fmt.Sprintf("%s/%s/%s", value, b.Argument, d.RequestID)
+	}
+	if b.Argument != "" {
+		value = fmt.Sprintf("%s/%s", value, b.Argument)
+	}
+	return value
+}
+
+// deploymentRef Slack 메시지에 표시할 배포 ID 및 배포를 시작한 요청 ID
+func deploymentRef(deploymentID string) string {
+	if d, exist := deployments.Get(deploymentID); exist && d.RequestID != "" {
+		return fmt.Sprintf("배포 ID: `%s` | 요청 ID: `%s`", deploymentID, d.RequestID)
+	}
+	return fmt.Sprintf("배포 ID: `%s`", deploymentID)
+}
+
+// approvalButtons 승인, 전체 승인, 반려 버튼
+func approvalButtons(s ServiceInfo, deploymentID string) *slack.ActionBlock {
+	value := func(result string) string {
+		return buttonValue(ButtonValue{Org: s.Org, Branch: s.Branch, ApplicationName: s.ApplicationName, ApplicationNamespace: s.ApplicationNamespace, RequestType: "deploy", Result: result, DeploymentID: deploymentID})
+	}
+	approveBtn, rejectBtn, approveFullBtn := value("approve"), value("reject"), value("approve-full")
+
+	return slack.NewActionBlock("action_block", // Action 블록 ID
+		slack.NewButtonBlockElement("approve", approveBtn,
+			slack.NewTextBlockObject("plain_text", "승인", true, false),
+		).WithStyle("primary"),
+		slack.NewButtonBlockElement("approve_full", approveFullBtn,
+			slack.NewTextBlockObject("plain_text", "전체 승인", true, false),
+		),
+		slack.NewButtonBlockElement("deny", rejectBtn,
+			slack.NewTextBlockObject("plain_text", "반려", true, false),
+		).WithStyle("danger"),
+	)
+}
+
+// rolloutControlBlock 승인 요청 메시지의 Rollout 제어 버튼 (일시정지, 재개, 재시도, 재시작, Canary 가중치)
+func rolloutControlBlock(s ServiceInfo, deploymentID string) *slack.ActionBlock {
+	value := func(action, argument string) string {
+		return buttonValue(ButtonValue{Org: s.Org, Branch: s.Branch, ApplicationName: s.ApplicationName, ApplicationNamespace: s.ApplicationNamespace, RequestType: "rollout", Result: action, DeploymentID: deploymentID, Argument: argument})
+	}
+
+	var weights []*slack.OptionBlockObject
+	for _, w := range []int{10, 25, 50, 75, 100} {
+		weights = append(weights, slack.NewOptionBlockObject(
+			value("set-weight", strconv.Itoa(w)),
+			slack.NewTextBlockObject("plain_text", fmt.Sprintf("%d%%", w), false, false),
+			nil,
+		))
+	}
+
+	return slack.NewActionBlock("rollout_block",
+		slack.NewButtonBlockElement("rollout_pause", value("pause", ""),
+			slack.NewTextBlockObject("plain_text", "일시정지", true, false),
+		),
+		slack.NewButtonBlockElement("rollout_resume", value("resume", ""),
+			slack.NewTextBlockObject("plain_text", "재개", true, false),
+		),
+		slack.NewButtonBlockElement("rollout_retry", value("retry", ""),
+			slack.NewTextBlockObject("plain_text", "재시도", true, false),
+		),
+		slack.NewButtonBlockElement("rollout_restart", value("restart", ""),
+			slack.NewTextBlockObject("plain_text", "재시작", true, false),
+		),
+		slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+			slack.NewTextBlockObject("plain_text", "Canary 가중치", false, false),
+			"rollout_set_weight",
+			weights...,
+		),
+	)
+}
+
+// markApprovalObsolete 대체된 배포의 승인 요청 메시지를 만료 처리하고 버튼 제거
+// Webhook으로 전송된 메시지는 수정할 수 없으므로 버튼 클릭 시 만료 메시지로 대체된다.
+func markApprovalObsolete(ctx context.Context, d deployment.Deployment) {
+	if slackClient == nil || d.SlackChannel == "" || d.SlackTimestamp == "" {
+		return
+	}
+
+	blocks := generateSlackTextBlock(fmt.Sprintf(":heavy_minus_sign: *만료된 배포 승인 요청* | *%s* `%s` 배포 요청이 이후 요청된 배포(`%s`)로 대체되었습니다.", d.Application, d.DockerTag, d.SupersededBy))
+	err := updateSlackMessage(ctx, d.SlackChannel, d.SlackTimestamp, blocks, "만료된 배포 승인 요청")
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("markApprovalObsolete | failed to update approval message of deployment %s", d.ID)
+	}
+}
+
+func sendUpdateSuccessMessage(ctx context.Context, s ServiceInfo, deploymentID string) error {
 	repoUrl := fmt.Sprintf("*서비스:*\n*<https://github.com/%s/%s|%s/%s>*", s.Org, s.Repo, s.Org, s.Repo)
 	operator := fmt.Sprintf("*담당자:*\n@%s", s.Operator)
 	commit := fmt.Sprintf("*업데이트 내용*\n%s", s.CommitMessage)
@@ -136,20 +326,110 @@ func sendUpdateSuccessMessage(s ServiceInfo) error {
 				nil,
 				nil,
 			),
+			rollbackButton(s.Org, s.Branch, s.ApplicationName, s.ApplicationNamespace, deploymentID),
 			slack.NewContextBlock("context_block",
 				slack.NewTextBlockObject("mrkdwn", ":pushpin: *배포 과정에 장애가 발생한 경우 DevOps 팀에 문의주시기 바랍니다.*", false, false),
+				slack.NewTextBlockObject("mrkdwn", deploymentRef(deploymentID), false, false),
 			),
 		},
 	}
 
 	msg := slack.WebhookMessage{Blocks: &blocks}
-	err := slack.PostWebhook(s.SlackWebhookUrl, &msg)
+	err := postWebhook(ctx, s.SlackWebhookUrl, &msg)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// sendDeployNoticeMessage 배포 동결, 정책 위반, Sync 실패 등으로 배포가 중단된 경우 사유 전송
+// actions(롤백 버튼 등)는 안내 문구 앞에 추가된다.
+func sendDeployNoticeMessage(ctx context.Context, s ServiceInfo, title, detail, guide string, actions ...slack.Block) error {
+	if s.SlackWebhookUrl == "" {
+		return errors.New("slack webhook url is empty")
+	}
+
+	repoUrl := fmt.Sprintf("*서비스:*\n*<https://github.com/%s/%s|%s/%s>*", s.Org, s.Repo, s.Org, s.Repo)
+	operator := fmt.Sprintf("*담당자:*\n@%s", s.Operator)
+
+	blocks := slack.Blocks{
+		BlockSet: []slack.Block{
+			slack.NewSectionBlock(
+				slack.NewTextBlockObject("mrkdwn", title, false, false),
+				nil,
+				nil,
+			),
+			slack.NewDividerBlock(),
+			slack.NewSectionBlock(nil, []*slack.TextBlockObject{
+				slack.NewTextBlockObject("mrkdwn", repoUrl, false, false),
+				slack.NewTextBlockObject("mrkdwn", operator, false, false),
+			}, nil),
+			slack.NewSectionBlock(
+				slack.NewTextBlockObject("mrkdwn", detail, false, false),
+				nil,
+				nil,
+			),
+		},
+	}
+
+	blocks.BlockSet = append(blocks.BlockSet, actions...)
+	blocks.BlockSet = append(blocks.BlockSet, slack.NewContextBlock("context_block",
+		slack.NewTextBlockObject("mrkdwn", guide, false, false),
+	))
+
+	msg := slack.WebhookMessage{Blocks: &blocks}
+	err := postWebhook(ctx, s.SlackWebhookUrl, &msg)
+	if err != nil {
+		return fmt.Errorf("sendDeployNoticeMessage | failed to post slack webhook: %w", err)
+	}
+	return nil
+}
+
+// rolloutProgressBlocks Rollout 진행 상황 메시지 (단계, 가중치, Replica, AnalysisRun, 소요 시간)
+func rolloutProgressBlocks(w rolloutWatch, status *rollouts.Status, title string, actions ...slack.Block) slack.Blocks {
+	step := status.Step()
+	if step == "" {
+		step = "-"
+	}
+	analysis := "-"
+	if status.AnalysisRun != "" {
+		analysis = fmt.Sprintf("`%s` %s", status.AnalysisRun, status.AnalysisStatus)
+	}
+	phase := fmt.Sprintf("`%s`", status.Phase)
+	if status.Message != "" {
+		phase = fmt.Sprintf("`%s` %s", status.Phase, status.Message)
+	}
+
+	now := time.Now()
+	blocks := slack.Blocks{
+		BlockSet: []slack.Block{
+			slack.NewSectionBlock(
+				slack.NewTextBlockObject("mrkdwn", title, false, false),
+				nil,
+				nil,
+			),
+			slack.NewDividerBlock(),
+			slack.NewSectionBlock(nil, []*slack.TextBlockObject{
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Rollout:*\n`%s`", w.rollout), false, false),
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*상태:*\n%s", phase), false, false),
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*단계:*\n`%s`", step), false, false),
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Canary 가중치:*\n`%d%%`", status.Weight), false, false),
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Replicas:*\nready `%d` / available `%d` / updated `%d` / 전체 `%d`", status.ReadyReplicas, status.AvailableReplicas, status.UpdatedReplicas, status.Replicas), false, false),
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*AnalysisRun:*\n%s", analysis), false, false),
+			}, nil),
+		},
+	}
+
+	blocks.BlockSet = append(blocks.BlockSet, actions...)
+	blocks.BlockSet = append(blocks.BlockSet, slack.NewContextBlock("context_block",
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("승인자: @%s", w.approver), false, false),
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("승인 후 소요 시간: `%s`", now.Sub(w.approvedAt).Round(time.Second)), false, false),
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("전체 배포 시간: `%s`", now.Sub(w.requestedAt).Round(time.Second)), false, false),
+		slack.NewTextBlockObject("mrkdwn", deploymentRef(w.deploymentID), false, false),
+	))
+	return blocks
+}
+
 type slackResponseForm struct {
 	url           string
 	msg           slack.Blocks
@@ -164,10 +444,36 @@ func generateSlackTextBlock(text string) slack.Blocks {
 	}
 }
 
-func (s slackResponseForm) sendResponseToSlack() error {
-	err := slack.PostWebhook(s.url, &slack.WebhookMessage{Blocks: &s.msg, ReplaceOriginal: s.replaceOption})
+func (s slackResponseForm) sendResponseToSlack(ctx context.Context) error {
+	err := postWebhook(ctx, s.url, &slack.WebhookMessage{Blocks: &s.msg, ReplaceOriginal: s.replaceOption})
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// Slack 전송은 배포 중단(대체, 서버 종료) 이후의 결과 알림도 포함하므로 ctx의 취소와 관계없이 전송하고 trace만 이어간다.
+
+// postWebhook Slack Webhook 메시지 전송. Webhook 주소는 인증 정보를 포함하므로 span에 기록하지 않는다.
+func postWebhook(ctx context.Context, url string, msg *slack.WebhookMessage) error {
+	ctx, span := tracing.Start(context.WithoutCancel(ctx), "slack webhook")
+	err := slack.PostWebhookContext(ctx, url, msg)
+	tracing.End(span, err)
+	return err
+}
+
+// postSlackMessage Slack Bot 메시지 전송 (chat.postMessage)
+func postSlackMessage(ctx context.Context, channel string, blocks slack.Blocks, text string) (string, string, error) {
+	ctx, span := tracing.Start(context.WithoutCancel(ctx), "slack chat.postMessage", attribute.String("slack.channel", channel))
+	channelID, timestamp, err := slackClient.PostMessageContext(ctx, channel, slack.MsgOptionBlocks(blocks.BlockSet...), slack.MsgOptionText(text, false))
+	tracing.End(span, err)
+	return channelID, timestamp, err
+}
+
+// updateSlackMessage Slack Bot 메시지 수정 (chat.update)
+func updateSlackMessage(ctx context.Context, channel, timestamp string, blocks slack.Blocks, text string) error {
+	ctx, span := tracing.Start(context.WithoutCancel(ctx), "slack chat.update", attribute.String("slack.channel", channel))
+	_, _, _, err := slackClient.UpdateMessageContext(ctx, channel, timestamp, slack.MsgOptionBlocks(blocks.BlockSet...), slack.MsgOptionText(text, false))
+	tracing.End(span, err)
+	return err
+}
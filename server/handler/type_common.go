@@ -17,6 +17,51 @@ type ServiceInfo struct {
 	Branch               string `json:"branch" binding:"required"`
 	ApplicationName      string `json:"application_name" binding:"required"`
 	ApplicationNamespace string `json:"application_namespace" binding:"required"`
+	// 배포 동결 기간 중 관리자 긴급 배포(break-glass) 요청
+	BreakGlass       bool   `json:"break_glass,omitempty"`
+	BreakGlassReason string `json:"break_glass_reason,omitempty"`
+	// Gateway가 발급한 배포 ID (미지정 시 Server에서 생성)
+	DeploymentID string `json:"deployment_id,omitempty"`
+	// 애플리케이션 담당 팀 (애플리케이션 설정 team이 우선)
+	Team string `json:"team,omitempty"`
+	// 배포 대상 커밋 시각 (RFC3339, 예: github.event.head_commit.timestamp). 미지정 시 Date를 사용한다.
+	CommitTimestamp string `json:"commit_timestamp,omitempty"`
+}
+
+// RollbackRequest 롤백 API 요청
+// HistoryID 미지정 시 현재 배포 직전의 ArgoCD 배포 이력으로 롤백한다.
+type RollbackRequest struct {
+	Operator        string `json:"operator" binding:"required"`
+	Reason          string `json:"reason"`
+	HistoryID       *int64 `json:"history_id,omitempty"`
+	Environment     string `json:"environment,omitempty"`
+	Namespace       string `json:"namespace,omitempty"`
+	SlackWebhookUrl string `json:"slack_webhook_url,omitempty"`
+}
+
+// RolloutActionRequest Rollout 제어 API 요청
+// Rollout 미지정 시 ArgoCD Application resource tree에서 조회한다.
+type RolloutActionRequest struct {
+	Operator    string `json:"operator" binding:"required"`
+	Reason      string `json:"reason"`
+	Namespace   string `json:"namespace" binding:"required"`
+	Rollout     string `json:"rollout,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	// set-weight 액션의 Canary 가중치 (0~100)
+	Weight *int32 `json:"weight,omitempty"`
+	// 배포 동결 기간 중 관리자 Rollout 진행(break-glass) 요청. reason을 break-glass 사유로 사용한다.
+	BreakGlass bool `json:"break_glass,omitempty"`
+}
+
+// WebhookRequest webhook 구독 등록 API 요청
+// Events, Applications, Environments 미지정 시 전체 이벤트를 수신하며, Secret 미지정 시 발급한다.
+type WebhookRequest struct {
+	URL          string   `json:"url" binding:"required"`
+	Events       []string `json:"events,omitempty"`
+	Applications []string `json:"applications,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Secret       string   `json:"secret,omitempty"`
 }
 
 type SlackResponse struct {
@@ -26,7 +71,8 @@ type SlackResponse struct {
 }
 
 // Button Value
-// Org/Branch/ApplicationName/ApplicationNamespace/deploy/approve, reject
+// Org/Branch/ApplicationName/ApplicationNamespace/deploy/approve, approve-full, reject, rollback/DeploymentID[//RequestID]
+// Org/Branch/ApplicationName/ApplicationNamespace/rollout/<action>/DeploymentID[/Argument][/RequestID]
 type ButtonValue struct {
 	Org                  string `json:"org"`
 	Branch               string `json:"branch"`
@@ -34,6 +80,11 @@ type ButtonValue struct {
 	ApplicationNamespace string `json:"application_namespace"`
 	RequestType          string `json:"request_type"`
 	Result               string `json:"result"`
+	DeploymentID         string `json:"deployment_id,omitempty"`
+	// Rollout 제어 인자 (set-weight 가중치)
+	Argument string `json:"argument,omitempty"`
+	// 배포를 시작한 요청 ID (승인 요청 메시지를 만든 GitHub 배포 요청)
+	RequestID string `json:"request_id,omitempty"`
 }
 
 type User struct {
@@ -0,0 +1,218 @@
+// Package metrics Relay Server Prometheus 메트릭
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "relay_server"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP 요청 수 (route, method, status)",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP 요청 처리 시간 (route, method, status)",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	argocdDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "argocd",
+		Name:      "request_duration_seconds",
+		Help:      "ArgoCD API 요청 시간. code는 응답 상태 코드, 요청 실패 시 error",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"server", "method", "endpoint", "code"})
+
+	rolloutsDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "rollouts",
+		Name:      "request_duration_seconds",
+		Help:      "Argo Rollouts 제어 요청 시간 (backend, action, result)",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"backend", "action", "result"})
+
+	deployments = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deployments_total",
+		Help:      "종료된 배포 수 (application, environment, action, result)",
+	}, []string{"application", "environment", "action", "result"})
+
+	approvalWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "approval_wait_seconds",
+		Help:      "운영 배포 승인 요청부터 승인/반려까지 대기 시간",
+		Buckets:   []float64{30, 60, 300, 900, 1800, 3600, 7200, 14400, 28800, 86400},
+	}, []string{"application", "environment"})
+
+	healthCheckAttempts = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "health_check_attempts",
+		Help:      "Preview 서비스 Health Check 시도 횟수 (application, result)",
+		Buckets:   []float64{1, 2, 3, 5, 8, 13, 21, 34},
+	}, []string{"application", "result"})
+
+	webhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "webhook 전송 시도 결과 (result). result는 succeeded, retry, failed",
+	}, []string{"result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		argocdDuration,
+		rolloutsDuration,
+		deployments,
+		approvalWait,
+		healthCheckAttempts,
+		webhookDeliveries,
+	)
+}
+
+// Handler /metrics 응답 (Prometheus exposition format)
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// ObserveRequest HTTP 요청 처리 결과. route는 등록된 경로 패턴을 사용한다.
+func ObserveRequest(route, method string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(route, method, code).Inc()
+	httpDuration.WithLabelValues(route, method, code).Observe(elapsed.Seconds())
+}
+
+// ObserveArgoCD ArgoCD API 요청 결과. status가 0인 경우 요청 실패(연결 오류, 타임아웃 등)로 기록한다.
+func ObserveArgoCD(server, method, endpoint string, status int, elapsed time.Duration) {
+	code := "error"
+	if status > 0 {
+		code = strconv.Itoa(status)
+	}
+	argocdDuration.WithLabelValues(server, method, endpoint, code).Observe(elapsed.Seconds())
+}
+
+// ObserveRollouts Argo Rollouts 제어 요청 결과
+func ObserveRollouts(backend, action string, err error, elapsed time.Duration) {
+	rolloutsDuration.WithLabelValues(backend, action, result(err == nil)).Observe(elapsed.Seconds())
+}
+
+// ObserveDeployment 배포 종료 결과 (succeeded, failed, approved, rejected, superseded)
+func ObserveDeployment(application, environment, action, status string) {
+	deployments.WithLabelValues(application, environment, action, status).Inc()
+}
+
+// ObserveApprovalWait 승인 대기 시간
+func ObserveApprovalWait(application, environment string, wait time.Duration) {
+	approvalWait.WithLabelValues(application, environment).Observe(wait.Seconds())
+}
+
+// ObserveHealthCheck Health Check 시도 횟수 및 결과
+func ObserveHealthCheck(application string, healthy bool, attempts int) {
+	healthCheckAttempts.WithLabelValues(application, result(healthy)).Observe(float64(attempts))
+}
+
+// ObserveWebhookDelivery webhook 전송 시도 결과 (succeeded, retry: 재시도 예정, failed: 최종 실패)
+func ObserveWebhookDelivery(result string) {
+	webhookDeliveries.WithLabelValues(result).Inc()
+}
+
+// RegisterPipeline 배포 파이프라인 대기열 및 실행 중인 작업 수. 조회 시점의 값을 stats로 가져온다.
+func RegisterPipeline(stats func() (queued, running, tasks int64)) {
+	gauge := func(name, help string, value func() int64) prometheus.GaugeFunc {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "pipeline",
+			Name:      name,
+			Help:      help,
+		}, func() float64 { return float64(value()) })
+	}
+
+	registry.MustRegister(
+		gauge("queue_depth", "워커 대기열에 등록된 배포 파이프라인 수", func() int64 { queued, _, _ := stats(); return queued }),
+		gauge("running", "실행 중인 배포 파이프라인 수", func() int64 { _, running, _ := stats(); return running }),
+		gauge("tasks", "실행 중인 장기 작업 수 (Rollout 진행 상황 추적)", func() int64 { _, _, tasks := stats(); return tasks }),
+	)
+}
+
+// DORA 팀/애플리케이션/환경 단위 DORA 지표. 표본이 없는 소요 시간은 nil로 전달한다.
+type DORA struct {
+	Team                 string
+	Application          string
+	Environment          string
+	DeploymentsPerDay    float64
+	LeadTimeSeconds      *float64
+	ChangeFailureRate    float64
+	TimeToRestoreSeconds *float64
+}
+
+// RegisterDORA window(예: 30d) 기간의 DORA 지표. 조회 시점의 값을 stats로 계산한다.
+func RegisterDORA(window string, stats func() []DORA) {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "dora", name), help,
+			[]string{"team", "application", "environment"}, prometheus.Labels{"window": window})
+	}
+
+	registry.MustRegister(&doraCollector{
+		stats:             stats,
+		deploymentsPerDay: desc("deployments_per_day", "일 평균 성공한 배포 수 (배포 빈도)"),
+		leadTime:          desc("lead_time_seconds", "커밋부터 배포 완료까지 시간 중앙값 (변경 리드 타임)"),
+		changeFailureRate: desc("change_failure_rate", "실패, Health Check 실패 혹은 롤백된 배포 비율 (변경 실패율)"),
+		timeToRestore:     desc("time_to_restore_seconds", "실패한 배포부터 복구까지 시간 중앙값 (서비스 복구 시간)"),
+	})
+}
+
+// doraCollector 조회 시점에 DORA 지표를 계산하는 Collector
+type doraCollector struct {
+	stats             func() []DORA
+	deploymentsPerDay *prometheus.Desc
+	leadTime          *prometheus.Desc
+	changeFailureRate *prometheus.Desc
+	timeToRestore     *prometheus.Desc
+}
+
+func (c *doraCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.deploymentsPerDay
+	ch <- c.leadTime
+	ch <- c.changeFailureRate
+	ch <- c.timeToRestore
+}
+
+func (c *doraCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, d := range c.stats() {
+		labels := []string{d.Team, d.Application, d.Environment}
+		ch <- prometheus.MustNewConstMetric(c.deploymentsPerDay, prometheus.GaugeValue, d.DeploymentsPerDay, labels...)
+		ch <- prometheus.MustNewConstMetric(c.changeFailureRate, prometheus.GaugeValue, d.ChangeFailureRate, labels...)
+		if d.LeadTimeSeconds != nil {
+			ch <- prometheus.MustNewConstMetric(c.leadTime, prometheus.GaugeValue, *d.LeadTimeSeconds, labels...)
+		}
+		if d.TimeToRestoreSeconds != nil {
+			ch <- prometheus.MustNewConstMetric(c.timeToRestore, prometheus.GaugeValue, *d.TimeToRestoreSeconds, labels...)
+		}
+	}
+}
+
+func result(success bool) string {
+	if success {
+		return "success"
+	}
+	return "failure"
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/antonio-kim-1994/devops-relay/server/metrics"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// RequestMetrics route 단위 요청 수 및 처리 시간 기록. 등록되지 않은 경로는 unmatched로 집계한다.
+func RequestMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveRequest(route, c.Request.Method, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"net/http"
+	"strings"
+)
+
+// RequestTracing 요청 traceparent 헤더를 이어받아 server span 생성. Health Check 요청은 기록하지 않는다.
+func RequestTracing(service string) gin.HandlerFunc {
+	return otelgin.Middleware(service, otelgin.WithFilter(func(r *http.Request) bool {
+		return !strings.HasPrefix(r.URL.Path, "/healthz")
+	}))
+}
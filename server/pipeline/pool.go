@@ -0,0 +1,156 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrShutdown 서버 종료로 중단된 작업의 취소 원인. 배포 상태를 유지해 재기동 시 재개한다.
+	ErrShutdown  = errors.New("pipeline: server shutting down")
+	ErrQueueFull = errors.New("pipeline: job queue is full")
+	ErrClosed    = errors.New("pipeline: pool is closed")
+)
+
+// Job 워커에서 실행되는 작업. ctx는 서버 종료 시 ErrShutdown으로 취소된다.
+type Job func(ctx context.Context)
+
+// Pool 배포 파이프라인을 HTTP 요청과 분리해 실행하는 고정 크기 워커 풀
+type Pool struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	jobs   chan Job
+	size   int
+
+	mu     sync.RWMutex
+	closed bool
+
+	wg      sync.WaitGroup
+	running atomic.Int64
+	tasks   atomic.Int64
+}
+
+// Stats 워커 풀 상태
+type Stats struct {
+	Workers   int   `json:"workers"`
+	QueueSize int   `json:"queue_size"`
+	Queued    int   `json:"queued"`
+	Running   int64 `json:"running"`
+	// 워커 수 제한 없이 실행 중인 장기 작업 (배포 잠금 대기, Rollout 진행 상황 추적)
+	Tasks int64 `json:"tasks"`
+}
+
+func NewPool(workers, queueSize int) *Pool {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	p := &Pool{
+		ctx:    ctx,
+		cancel: cancel,
+		jobs:   make(chan Job, queueSize),
+		size:   workers,
+	}
+
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// Submit 작업 등록. 대기열이 가득 찬 경우 대기하지 않고 ErrQueueFull을 반환한다.
+func (p *Pool) Submit(job Job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	select {
+	case p.jobs <- job:
+		return nil
+	default:
+		return fmt.Errorf("%w (%d queued)", ErrQueueFull, len(p.jobs))
+	}
+}
+
+// Go 워커를 점유하지 않는 장기 작업(배포 잠금 대기, Rollout 추적) 실행. 서버 종료 시 Submit 작업과 함께 취소 및 종료를 기다린다.
+func (p *Pool) Go(task Job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	p.wg.Add(1)
+	p.tasks.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer p.tasks.Add(-1)
+		p.execute(task)
+	}()
+	return nil
+}
+
+// Shutdown 신규 작업 등록을 중단하고 실행 중인 작업을 ErrShutdown으로 취소한 뒤 종료를 기다린다.
+// 대기열에 남은 작업은 실행하지 않는다.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+	p.mu.Unlock()
+	p.cancel(ErrShutdown)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("pipeline: %d jobs still running: %w", p.running.Load()+p.tasks.Load(), ctx.Err())
+	}
+}
+
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Workers:   p.size,
+		QueueSize: cap(p.jobs),
+		Queued:    len(p.jobs),
+		Running:   p.running.Load(),
+		Tasks:     p.tasks.Load(),
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		if p.ctx.Err() != nil {
+			// 종료 중에는 남은 작업을 실행하지 않는다. (재기동 시 저장된 상태로 재개)
+			continue
+		}
+		p.running.Add(1)
+		p.execute(job)
+		p.running.Add(-1)
+	}
+}
+
+// execute 작업 실행. panic이 발생해도 워커는 유지한다.
+func (p *Pool) execute(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().Msgf("pipeline | job panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+	job(p.ctx)
+}
@@ -0,0 +1,268 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/cel-go/cel"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Policy 배포 요청 허용 조건(CEL 표현식)
+// rule이 true로 평가되면 허용, false인 경우 message(혹은 message_expression)를 거부 사유로 반환한다.
+type Policy struct {
+	Name              string `yaml:"name"`
+	Description       string `yaml:"description"`
+	Rule              string `yaml:"rule"`
+	Message           string `yaml:"message"`
+	MessageExpression string `yaml:"message_expression"`
+
+	rule    cel.Program
+	message cel.Program
+}
+
+type policyFile struct {
+	Policies []*Policy `yaml:"policies"`
+}
+
+// Requester 배포 요청자 정보
+type Requester struct {
+	Login string
+	Admin bool
+}
+
+// Input 정책 평가 입력값
+type Input struct {
+	Request     map[string]any
+	Environment string
+	Requester   Requester
+	Now         time.Time
+}
+
+type Denial struct {
+	Policy  string `json:"policy"`
+	Message string `json:"message"`
+}
+
+type Decision struct {
+	Allowed bool     `json:"allowed"`
+	Denials []Denial `json:"denials,omitempty"`
+}
+
+type Engine struct {
+	policies []*Policy
+	location *time.Location
+}
+
+// Reason 거부 사유를 한 줄로 반환
+func (d Decision) Reason() string {
+	reasons := make([]string, 0, len(d.Denials))
+	for _, denial := range d.Denials {
+		reasons = append(reasons, fmt.Sprintf("[%s] %s", denial.Policy, denial.Message))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Load 디렉토리의 정책 파일(*.yaml, *.yml) 로드. *_test.yaml 파일은 정책 테스트 케이스로 제외된다.
+// 디렉토리가 비어있으면 모든 요청을 허용하는 Engine을 반환한다.
+func Load(dir string, location *time.Location) (*Engine, error) {
+	e := &Engine{location: location}
+	if dir == "" {
+		return e, nil
+	}
+
+	files, err := policyFiles(dir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := newEnv()
+	if err != nil {
+		return nil, fmt.Errorf("policy.Load | failed to create CEL environment: %w", err)
+	}
+
+	names := map[string]string{}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("policy.Load | failed to read policy file %s: %w", path, err)
+		}
+
+		var f policyFile
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("policy.Load | failed to unmarshal policy file %s: %w", path, err)
+		}
+
+		for _, p := range f.Policies {
+			if prev, exist := names[p.Name]; exist {
+				return nil, fmt.Errorf("policy.Load | duplicated policy name %q in %s and %s", p.Name, prev, path)
+			}
+			names[p.Name] = path
+
+			if err := p.compile(env); err != nil {
+				return nil, fmt.Errorf("policy.Load | invalid policy %q in %s: %w", p.Name, path, err)
+			}
+			e.policies = append(e.policies, p)
+		}
+	}
+
+	return e, nil
+}
+
+// Policies 로드된 정책 목록
+func (e *Engine) Policies() []*Policy {
+	return e.policies
+}
+
+// Evaluate 모든 정책을 평가해 거부 사유를 모아 반환한다.
+// 평가 중 오류가 발생한 정책은 거부로 처리한다.
+func (e *Engine) Evaluate(in Input) Decision {
+	d := Decision{Allowed: true}
+	if e == nil {
+		return d
+	}
+
+	vars := e.activation(in)
+	for _, p := range e.policies {
+		out, _, err := p.rule.Eval(vars)
+		if err != nil {
+			d.Denials = append(d.Denials, Denial{Policy: p.Name, Message: fmt.Sprintf("policy evaluation failed: %v", err)})
+			continue
+		}
+
+		if allowed, ok := out.Value().(bool); ok && allowed {
+			continue
+		}
+
+		d.Denials = append(d.Denials, Denial{Policy: p.Name, Message: p.denyMessage(vars)})
+	}
+
+	d.Allowed = len(d.Denials) == 0
+	return d
+}
+
+func (e *Engine) activation(in Input) map[string]any {
+	now := in.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	local := now.In(e.location)
+
+	request := in.Request
+	if request == nil {
+		request = map[string]any{}
+	}
+
+	return map[string]any{
+		"request":     request,
+		"environment": in.Environment,
+		"requester": map[string]any{
+			"login": in.Requester.Login,
+			"admin": in.Requester.Admin,
+		},
+		"now":      now,
+		"timezone": e.location.String(),
+		// 설정된 Timezone 기준 시각 (weekday: 0 = Sunday)
+		"local": map[string]any{
+			"weekday":      int64(local.Weekday()),
+			"weekday_name": local.Weekday().String(),
+			"hour":         int64(local.Hour()),
+			"minute":       int64(local.Minute()),
+			"date":         local.Format("2006-01-02"),
+		},
+	}
+}
+
+func (p *Policy) compile(env *cel.Env) error {
+	if p.Name == "" {
+		return errors.New("policy name is empty")
+	}
+
+	rule, err := compileProgram(env, p.Rule, cel.BoolType)
+	if err != nil {
+		return fmt.Errorf("rule: %w", err)
+	}
+	p.rule = rule
+
+	if p.MessageExpression != "" {
+		message, err := compileProgram(env, p.MessageExpression, cel.StringType)
+		if err != nil {
+			return fmt.Errorf("message_expression: %w", err)
+		}
+		p.message = message
+	}
+	return nil
+}
+
+func (p *Policy) denyMessage(vars map[string]any) string {
+	if p.message != nil {
+		if out, _, err := p.message.Eval(vars); err == nil {
+			if msg, ok := out.Value().(string); ok && msg != "" {
+				return msg
+			}
+		}
+	}
+
+	if p.Message != "" {
+		return p.Message
+	}
+	if p.Description != "" {
+		return p.Description
+	}
+	return "denied by policy"
+}
+
+func newEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("environment", cel.StringType),
+		cel.Variable("requester", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("now", cel.TimestampType),
+		cel.Variable("timezone", cel.StringType),
+		cel.Variable("local", cel.MapType(cel.StringType, cel.DynType)),
+	)
+}
+
+func compileProgram(env *cel.Env, expr string, expected *cel.Type) (cel.Program, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, errors.New("expression is empty")
+	}
+
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	if !ast.OutputType().IsExactType(expected) && !ast.OutputType().IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression must return %s, got %s", expected, ast.OutputType())
+	}
+
+	return env.Program(ast)
+}
+
+func policyFiles(dir string, tests bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("policy | failed to read policy directory %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (filepath.Ext(name) != ".yaml" && filepath.Ext(name) != ".yml") {
+			continue
+		}
+
+		isTest := strings.HasSuffix(strings.TrimSuffix(name, filepath.Ext(name)), "_test")
+		if isTest == tests {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
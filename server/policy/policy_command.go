@@ -0,0 +1,162 @@
+package policy
+
+import (
+	"flag"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TestCase 정책 테스트 케이스 (*_test.yaml)
+type TestCase struct {
+	Name        string         `yaml:"name"`
+	Request     map[string]any `yaml:"request"`
+	Environment string         `yaml:"environment"`
+	Requester   struct {
+		Login string `yaml:"login"`
+		Admin bool   `yaml:"admin"`
+	} `yaml:"requester"`
+	Now      string   `yaml:"now"`
+	Expect   string   `yaml:"expect"`
+	DeniedBy []string `yaml:"denied_by"`
+}
+
+type testFile struct {
+	Tests []TestCase `yaml:"tests"`
+}
+
+const usage = `usage: devops-relay-server policy test [-timezone Asia/Seoul] <policy-dir>`
+
+// RunCommand policy 서브 커맨드 실행 후 종료 코드 반환
+func RunCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "test" {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("policy test", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	timezone := fs.String("timezone", defaultTimezone(), "timezone used to evaluate policies")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	dir := fs.Arg(0)
+
+	location, err := time.LoadLocation(*timezone)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to load timezone %s: %v\n", *timezone, err)
+		return 2
+	}
+
+	engine, err := Load(dir, location)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	files, err := policyFiles(dir, true)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	passed, failed := 0, 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to read test file %s: %v\n", path, err)
+			return 1
+		}
+
+		var f testFile
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			fmt.Fprintf(stderr, "failed to unmarshal test file %s: %v\n", path, err)
+			return 1
+		}
+
+		for _, tc := range f.Tests {
+			if msg := engine.runTestCase(tc); msg != "" {
+				failed++
+				fmt.Fprintf(stdout, "FAIL  %s: %s\n      %s\n", path, tc.Name, msg)
+				continue
+			}
+			passed++
+			fmt.Fprintf(stdout, "PASS  %s: %s\n", path, tc.Name)
+		}
+	}
+
+	fmt.Fprintf(stdout, "\n%d policies, %d passed, %d failed\n", len(engine.Policies()), passed, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runTestCase 테스트 케이스 실행. 실패 시 사유를 반환한다.
+func (e *Engine) runTestCase(tc TestCase) string {
+	in := Input{
+		Request:     tc.Request,
+		Environment: tc.Environment,
+		Requester:   Requester{Login: tc.Requester.Login, Admin: tc.Requester.Admin},
+	}
+
+	if in.Environment == "" {
+		in.Environment, _ = tc.Request["branch"].(string)
+	}
+	if in.Requester.Login == "" {
+		in.Requester.Login, _ = tc.Request["operator"].(string)
+	}
+
+	if tc.Now != "" {
+		now, err := time.Parse(time.RFC3339, tc.Now)
+		if err != nil {
+			return fmt.Sprintf("invalid now %q: %v", tc.Now, err)
+		}
+		in.Now = now
+	}
+
+	d := e.Evaluate(in)
+
+	switch tc.Expect {
+	case "allow":
+		if !d.Allowed {
+			return fmt.Sprintf("expected allow, got deny: %s", d.Reason())
+		}
+	case "deny":
+		if d.Allowed {
+			return "expected deny, got allow"
+		}
+	default:
+		return fmt.Sprintf("unknown expect %q (allow or deny)", tc.Expect)
+	}
+
+	if len(tc.DeniedBy) > 0 {
+		var got []string
+		for _, denial := range d.Denials {
+			got = append(got, denial.Policy)
+		}
+		want := append([]string(nil), tc.DeniedBy...)
+		sort.Strings(got)
+		sort.Strings(want)
+
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			return fmt.Sprintf("expected denied by %v, got %v (%s)", want, got, d.Reason())
+		}
+	}
+	return ""
+}
+
+func defaultTimezone() string {
+	if tz := os.Getenv("TIMEZONE"); tz != "" {
+		return tz
+	}
+	return "Asia/Seoul"
+}
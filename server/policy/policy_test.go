@@ -0,0 +1,195 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPolicies = `
+policies:
+  - name: allowed-orgs
+    description: 허용된 GitHub 조직만 배포할 수 있습니다.
+    rule: request.org in ["org-a", "org-b"]
+    message_expression: '"허용되지 않은 조직입니다: " + request.org'
+
+  - name: no-friday-prod
+    description: 금요일 15시 이후 운영 배포는 관리자만 가능합니다.
+    rule: environment != "prod" || local.weekday != 5 || local.hour < 15 || requester.admin
+`
+
+var seoul = time.FixedZone("KST", 9*60*60)
+
+// 2026-03-06 (금) 16:00 KST
+var fridayEvening = time.Date(2026, 3, 6, 7, 0, 0, 0, time.UTC)
+
+func writePolicies(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	return dir
+}
+
+func loadPolicies(t *testing.T, content string) *Engine {
+	t.Helper()
+	e, err := Load(writePolicies(t, map[string]string{"deploy_policies.yaml": content}), seoul)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return e
+}
+
+func TestLoadCompileErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy string
+		want   string
+	}{
+		{name: "syntax error", policy: `{name: broken, rule: 'request.org =='}`, want: `invalid policy "broken"`},
+		{name: "non bool rule", policy: `{name: not-bool, rule: size(request)}`, want: "expression must return bool"},
+		{name: "undeclared variable", policy: `{name: unknown, rule: 'user.admin'}`, want: "undeclared reference"},
+		{name: "empty rule", policy: `{name: empty, rule: ''}`, want: "expression is empty"},
+		{name: "empty name", policy: `{rule: 'true'}`, want: "policy name is empty"},
+		{name: "non string message", policy: `{name: message, rule: 'true', message_expression: '1 + 1'}`, want: "message_expression"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writePolicies(t, map[string]string{"deploy_policies.yaml": "policies:\n  - " + tc.policy + "\n"})
+			_, err := Load(dir, seoul)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadDuplicatedName(t *testing.T) {
+	dir := writePolicies(t, map[string]string{
+		"a.yaml": "policies:\n  - {name: same, rule: 'true'}\n",
+		"b.yml":  "policies:\n  - {name: same, rule: 'false'}\n",
+	})
+	if _, err := Load(dir, seoul); err == nil || !strings.Contains(err.Error(), `duplicated policy name "same"`) {
+		t.Errorf("err = %v, want duplicated policy name", err)
+	}
+}
+
+func TestLoadSkipsTestFiles(t *testing.T) {
+	dir := writePolicies(t, map[string]string{
+		"deploy_policies.yaml":      testPolicies,
+		"deploy_policies_test.yaml": "policies:\n  - {name: broken, rule: '=='}\n",
+		"README.md":                 "# policies",
+	})
+	e, err := Load(dir, seoul)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(e.Policies()) != 2 {
+		t.Errorf("policies = %d, want 2", len(e.Policies()))
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	e := loadPolicies(t, testPolicies)
+
+	cases := []struct {
+		name    string
+		in      Input
+		denials []string
+	}{
+		{
+			name: "allowed",
+			in:   Input{Request: map[string]any{"org": "org-a"}, Environment: "prod", Now: fridayEvening.Add(-2 * time.Hour)},
+		},
+		{
+			name:    "unknown org",
+			in:      Input{Request: map[string]any{"org": "org-x"}, Environment: "dev", Now: fridayEvening},
+			denials: []string{"[allowed-orgs] 허용되지 않은 조직입니다: org-x"},
+		},
+		{
+			name:    "friday prod",
+			in:      Input{Request: map[string]any{"org": "org-a"}, Environment: "prod", Requester: Requester{Login: "dev-user"}, Now: fridayEvening},
+			denials: []string{"[no-friday-prod] 금요일 15시 이후 운영 배포는 관리자만 가능합니다."},
+		},
+		{
+			name: "friday prod by admin",
+			in:   Input{Request: map[string]any{"org": "org-a"}, Environment: "prod", Requester: Requester{Login: "devops-admin", Admin: true}, Now: fridayEvening},
+		},
+		{
+			name: "friday dev",
+			in:   Input{Request: map[string]any{"org": "org-b"}, Environment: "dev", Now: fridayEvening},
+		},
+		{
+			name: "all denials",
+			in:   Input{Request: map[string]any{"org": "org-x"}, Environment: "prod", Now: fridayEvening},
+			denials: []string{
+				"[allowed-orgs] 허용되지 않은 조직입니다: org-x",
+				"[no-friday-prod] 금요일 15시 이후 운영 배포는 관리자만 가능합니다.",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := e.Evaluate(tc.in)
+			if d.Allowed != (len(tc.denials) == 0) {
+				t.Errorf("allowed = %t, want %t (%s)", d.Allowed, len(tc.denials) == 0, d.Reason())
+			}
+			if want := strings.Join(tc.denials, "; "); d.Reason() != want {
+				t.Errorf("reason = %q, want %q", d.Reason(), want)
+			}
+		})
+	}
+}
+
+func TestEvaluateTimezone(t *testing.T) {
+	e := loadPolicies(t, testPolicies)
+
+	// UTC 기준으로는 금요일 오전이지만 TIMEZONE(KST) 기준 16시이므로 거부한다.
+	d := e.Evaluate(Input{Request: map[string]any{"org": "org-a"}, Environment: "prod", Now: fridayEvening})
+	if d.Allowed {
+		t.Error("allowed, want denied in configured timezone")
+	}
+}
+
+func TestEvaluateError(t *testing.T) {
+	e := loadPolicies(t, "policies:\n  - {name: tag, rule: 'request.docker_tag.startsWith(\"v\")', message: 태그는 v로 시작해야 합니다.}\n")
+
+	// 평가 중 오류(필드 없음)는 거부로 처리한다.
+	d := e.Evaluate(Input{Request: map[string]any{"org": "org-a"}})
+	if d.Allowed || len(d.Denials) != 1 || !strings.Contains(d.Denials[0].Message, "policy evaluation failed") {
+		t.Errorf("decision = %+v, want evaluation failure denial", d)
+	}
+
+	if d := e.Evaluate(Input{Request: map[string]any{"docker_tag": "1.2.3"}}); d.Reason() != "[tag] 태그는 v로 시작해야 합니다." {
+		t.Errorf("reason = %q, want static message", d.Reason())
+	}
+}
+
+func TestDenyMessageFallback(t *testing.T) {
+	e := loadPolicies(t, `
+policies:
+  - {name: description, description: 설명이 거부 사유가 됩니다., rule: 'false'}
+  - {name: default, rule: 'false'}
+`)
+	want := "[description] 설명이 거부 사유가 됩니다.; [default] denied by policy"
+	if d := e.Evaluate(Input{}); d.Reason() != want {
+		t.Errorf("reason = %q, want %q", d.Reason(), want)
+	}
+}
+
+func TestEmptyPolicyDir(t *testing.T) {
+	e, err := Load("", seoul)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if d := e.Evaluate(Input{Request: map[string]any{"org": "org-x"}}); !d.Allowed {
+		t.Errorf("decision = %+v, want allowed without policies", d)
+	}
+}
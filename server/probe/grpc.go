@@ -0,0 +1,52 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"time"
+)
+
+// GRPC gRPC Health Checking Protocol(grpc.health.v1.Health/Check) 기반 Prober
+type GRPC struct {
+	address string
+	service string
+	timeout time.Duration
+	tls     *tls.Config
+}
+
+func (p *GRPC) Target() string {
+	if p.service == "" {
+		return p.address
+	}
+	return fmt.Sprintf("%s (%s)", p.address, p.service)
+}
+
+func (p *GRPC) Probe(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	creds := insecure.NewCredentials()
+	if p.tls != nil {
+		creds = credentials.NewTLS(p.tls)
+	}
+
+	conn, err := grpc.NewClient(p.address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return "", fmt.Errorf("probe: failed to create grpc client %s: %w", p.address, err)
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: p.service})
+	if err != nil {
+		return "", fmt.Errorf("probe: grpc health check %s: %w", p.address, err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return resp.GetStatus().String(), fmt.Errorf("probe: grpc health status %s", resp.GetStatus())
+	}
+	return resp.GetStatus().String(), nil
+}
@@ -0,0 +1,148 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBodySize 응답 본문 검사 최대 크기
+const maxBodySize = 1 << 20
+
+// HTTP 상태 코드, 응답 본문 정규식, JSON 필드를 검사하는 Prober
+type HTTP struct {
+	url            string
+	method         string
+	headers        map[string]string
+	expectedStatus []int
+	bodyRegex      *regexp.Regexp
+	jsonPath       string
+	jsonValue      string
+	timeout        time.Duration
+	client         *http.Client
+}
+
+func newHTTP(url string, cfg config.ProbeConfig, tlsConfig *tls.Config) (*HTTP, error) {
+	p := &HTTP{
+		url:            url,
+		method:         cfg.Method,
+		headers:        cfg.Headers,
+		expectedStatus: cfg.ExpectedStatus,
+		jsonPath:       cfg.JSONPath,
+		jsonValue:      cfg.JSONValue,
+		timeout:        cfg.Timeout,
+		client: &http.Client{
+			// Prober는 배포 단위로 생성되므로 연결을 재사용하지 않는다.
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: true},
+		},
+	}
+	if !cfg.FollowRedirects {
+		// Redirect 응답 코드 자체를 검사할 수 있도록 따라가지 않는다.
+		p.client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	}
+	if p.method == "" {
+		p.method = http.MethodGet
+	}
+
+	if cfg.BodyRegex != "" {
+		re, err := regexp.Compile(cfg.BodyRegex)
+		if err != nil {
+			return nil, fmt.Errorf("probe: invalid body regex: %w", err)
+		}
+		p.bodyRegex = re
+	}
+	return p, nil
+}
+
+func (p *HTTP) Target() string {
+	return p.url
+}
+
+func (p *HTTP) Probe(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, p.method, p.url, nil)
+	if err != nil {
+		return "", fmt.Errorf("probe: failed to create request: %w", err)
+	}
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("probe: %s %s: %w", p.method, p.url, err)
+	}
+	defer resp.Body.Close()
+
+	status := fmt.Sprintf("HTTP %d", resp.StatusCode)
+
+	if !slices.Contains(p.expectedStatus, resp.StatusCode) {
+		return status, fmt.Errorf("probe: unexpected status code %d (expected %v)", resp.StatusCode, p.expectedStatus)
+	}
+
+	if p.bodyRegex == nil && p.jsonPath == "" {
+		return status, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return status, fmt.Errorf("probe: failed to read response body: %w", err)
+	}
+
+	if p.bodyRegex != nil && !p.bodyRegex.Match(body) {
+		return status, fmt.Errorf("probe: response body does not match %q", p.bodyRegex)
+	}
+
+	if p.jsonPath != "" {
+		var doc any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return status, fmt.Errorf("probe: failed to unmarshal response body: %w", err)
+		}
+		value, err := lookupJSONPath(doc, p.jsonPath)
+		if err != nil {
+			return status, err
+		}
+		if p.jsonValue != "" && fmt.Sprint(value) != p.jsonValue {
+			return status, fmt.Errorf("probe: %s is %v (expected %s)", p.jsonPath, value, p.jsonValue)
+		}
+	}
+	return status, nil
+}
+
+// lookupJSONPath 점(.)으로 구분된 경로로 JSON 값 조회. 배열은 인덱스로 접근한다. (예: checks.0.status)
+func lookupJSONPath(doc any, path string) (any, error) {
+	current := doc
+	for _, key := range strings.Split(strings.TrimPrefix(path, "$."), ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, exist := node[key]
+			if !exist {
+				return nil, fmt.Errorf("probe: json path %s not found", path)
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("probe: invalid json path index %q in %s", key, path)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("probe: json path %s not found", path)
+		}
+	}
+	return current, nil
+}
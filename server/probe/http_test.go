@@ -0,0 +1,50 @@
+package probe
+
+import (
+	"context"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "/ko", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cases := []struct {
+		name            string
+		followRedirects bool
+		expectedStatus  []int
+		wantStatus      string
+	}{
+		{name: "follow", followRedirects: true, expectedStatus: []int{200}, wantStatus: "HTTP 200"},
+		{name: "no follow", followRedirects: false, expectedStatus: []int{302}, wantStatus: "HTTP 302"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := New(config.ProbeConfig{
+				Type:            "http",
+				URL:             srv.URL + "/",
+				ExpectedStatus:  tc.expectedStatus,
+				FollowRedirects: tc.followRedirects,
+				Timeout:         5 * time.Second,
+			}, Vars{})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			status, err := p.Probe(context.Background())
+			if err != nil || status != tc.wantStatus {
+				t.Errorf("Probe = %q, %v, want %q", status, err, tc.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Package probe 서비스 Health Probe (HTTP, TCP, gRPC Health Checking Protocol)
+package probe
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/config"
+	"os"
+	"text/template"
+)
+
+// Prober Health Probe 1회 수행. 확인된 상태(HTTP 상태 코드, gRPC Health 상태 등)를 반환하며, 비정상인 경우 원인을 error로 반환한다.
+type Prober interface {
+	Probe(ctx context.Context) (string, error)
+	// Target Probe 대상 (URL 혹은 주소)
+	Target() string
+}
+
+// Vars url, address 템플릿 변수
+type Vars struct {
+	Application string
+	Namespace   string
+}
+
+// New Probe 설정으로 Prober 생성
+func New(cfg config.ProbeConfig, vars Vars) (Prober, error) {
+	tlsConfig, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Type {
+	case "http":
+		url, err := render(cfg.URL, vars)
+		if err != nil {
+			return nil, err
+		}
+		return newHTTP(url, cfg, tlsConfig)
+	case "tcp", "grpc":
+		address, err := render(cfg.Address, vars)
+		if err != nil {
+			return nil, err
+		}
+		if !cfg.TLS.Enabled {
+			tlsConfig = nil
+		}
+		if cfg.Type == "tcp" {
+			return &TCP{address: address, timeout: cfg.Timeout, tls: tlsConfig}, nil
+		}
+		return &GRPC{address: address, service: cfg.GRPCService, timeout: cfg.Timeout, tls: tlsConfig}, nil
+	}
+	return nil, fmt.Errorf("probe: unknown probe type %q", cfg.Type)
+}
+
+func render(text string, vars Vars) (string, error) {
+	tmpl, err := template.New("probe").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("probe: failed to parse template %q: %w", text, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("probe: failed to render template %q: %w", text, err)
+	}
+	return buf.String(), nil
+}
+
+func newTLSConfig(cfg config.ProbeTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		ServerName:         cfg.ServerName,
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("probe: failed to read ca file %s: %w", cfg.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("probe: no certificates found in ca file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
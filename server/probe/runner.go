@@ -0,0 +1,104 @@
+package probe
+
+import (
+	"context"
+	"time"
+)
+
+// Policy Health Probe 반복 수행 정책
+type Policy struct {
+	// 최대 시도 횟수
+	Attempts int
+	// 정상으로 판단하기 위한 연속 성공 횟수
+	SuccessThreshold int
+	// 시도 간격. 실패할 때마다 Multiplier 배로 늘어나며 MaxInterval을 넘지 않는다. 성공 시 초기 간격으로 돌아간다.
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Multiplier  float64
+}
+
+// Attempt Health Probe 시도 기록
+type Attempt struct {
+	Number    int           `json:"number"`
+	StartedAt time.Time     `json:"started_at"`
+	Latency   time.Duration `json:"latency"`
+	Status    string        `json:"status,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Succeeded 시도 성공 여부
+func (a Attempt) Succeeded() bool {
+	return a.Error == ""
+}
+
+// Result Health Probe 수행 결과
+type Result struct {
+	Target   string        `json:"target"`
+	Healthy  bool          `json:"healthy"`
+	Canceled bool          `json:"canceled,omitempty"`
+	Policy   Policy        `json:"policy"`
+	Elapsed  time.Duration `json:"elapsed"`
+	Attempts []Attempt     `json:"attempts"`
+}
+
+// Run SuccessThreshold만큼 연속으로 성공하거나 Attempts를 모두 소진할 때까지 Health Probe 수행
+// ctx가 취소되면 즉시 중단하고 Canceled로 표시한다.
+func Run(ctx context.Context, p Prober, policy Policy) (result Result) {
+	result = Result{Target: p.Target(), Policy: policy}
+	startedAt := time.Now()
+	defer func() { result.Elapsed = time.Since(startedAt) }()
+
+	interval := policy.Interval
+	successes, failures := 0, 0
+	for n := 1; n <= policy.Attempts; n++ {
+		attempt := Attempt{Number: n, StartedAt: time.Now()}
+		status, err := p.Probe(ctx)
+		attempt.Latency = time.Since(attempt.StartedAt)
+		attempt.Status = status
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+
+		if ctx.Err() != nil {
+			result.Canceled = true
+			return result
+		}
+		result.Attempts = append(result.Attempts, attempt)
+
+		if attempt.Succeeded() {
+			successes, failures = successes+1, 0
+			interval = policy.Interval
+			if successes >= policy.SuccessThreshold {
+				result.Healthy = true
+				return result
+			}
+		} else {
+			if failures > 0 {
+				interval = nextInterval(interval, policy)
+			}
+			successes, failures = 0, failures+1
+		}
+
+		if n == policy.Attempts {
+			break
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.Canceled = true
+			return result
+		case <-timer.C:
+		}
+	}
+	return result
+}
+
+func nextInterval(current time.Duration, policy Policy) time.Duration {
+	next := time.Duration(float64(current) * policy.Multiplier)
+	if policy.MaxInterval > 0 && next > policy.MaxInterval {
+		return policy.MaxInterval
+	}
+	return next
+}
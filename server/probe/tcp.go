@@ -0,0 +1,39 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+)
+
+// TCP 접속 가능 여부(TLS 설정 시 Handshake 포함)를 확인하는 Prober
+type TCP struct {
+	address string
+	timeout time.Duration
+	tls     *tls.Config
+}
+
+func (p *TCP) Target() string {
+	return p.address
+}
+
+func (p *TCP) Probe(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if p.tls != nil {
+		conn, err = (&tls.Dialer{Config: p.tls}).DialContext(ctx, "tcp", p.address)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", p.address)
+	}
+	if err != nil {
+		return "", fmt.Errorf("probe: failed to connect %s: %w", p.address, err)
+	}
+	return "connected", conn.Close()
+}
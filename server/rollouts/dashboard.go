@@ -0,0 +1,144 @@
+package rollouts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// rolloutResource ArgoCD Resource Action/Patch 대상 Rollout 리소스
+func rolloutResource(r Rollout) argocd.ResourceKey {
+	return argocd.ResourceKey{
+		Group:     "argoproj.io",
+		Version:   "v1alpha1",
+		Kind:      "Rollout",
+		Namespace: r.Namespace,
+		Name:      r.Name,
+	}
+}
+
+// Dashboard Argo Rollouts Dashboard API 기반 Controller
+// Dashboard API에 없는 pause, resume은 ArgoCD Resource Action으로, 상태 조회는 ArgoCD Live manifest로 처리한다.
+type Dashboard struct {
+	baseURL    string
+	httpClient *http.Client
+	argo       *argocd.Client
+}
+
+func NewDashboard(baseURL string, httpClient *http.Client, argo *argocd.Client) *Dashboard {
+	return &Dashboard{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+		argo:       argo,
+	}
+}
+
+// APIError Rollouts Dashboard API 오류 응답
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("rollouts: %s %s: %d %s", e.Method, e.Path, e.StatusCode, strings.TrimSpace(e.Message))
+}
+
+func (d *Dashboard) Promote(ctx context.Context, r Rollout, full bool) error {
+	payload := map[string]any{
+		"name":      r.Name,
+		"namespace": r.Namespace,
+		"full":      full,
+	}
+	return d.put(ctx, r, "promote", payload)
+}
+
+func (d *Dashboard) Abort(ctx context.Context, r Rollout) error {
+	return d.put(ctx, r, "abort", nil)
+}
+
+func (d *Dashboard) Retry(ctx context.Context, r Rollout) error {
+	return d.put(ctx, r, "retry", nil)
+}
+
+func (d *Dashboard) Restart(ctx context.Context, r Rollout) error {
+	return d.put(ctx, r, "restart", nil)
+}
+
+func (d *Dashboard) Pause(ctx context.Context, r Rollout) error {
+	if err := d.argo.RunResourceAction(ctx, r.Application, rolloutResource(r), "pause"); err != nil {
+		return fmt.Errorf("rollouts: failed to pause %s: %w", r, err)
+	}
+	return nil
+}
+
+func (d *Dashboard) Resume(ctx context.Context, r Rollout) error {
+	if err := d.argo.RunResourceAction(ctx, r.Application, rolloutResource(r), "resume"); err != nil {
+		return fmt.Errorf("rollouts: failed to resume %s: %w", r, err)
+	}
+	return nil
+}
+
+// SetWeight ArgoCD Resource Patch는 status subresource를 변경할 수 없으므로 지원하지 않는다.
+// Canary 단계(spec)를 변경하면 Application이 OutOfSync 상태가 되고 이후 배포 단계도 바뀌므로 spec patch로 대체하지 않는다.
+func (d *Dashboard) SetWeight(ctx context.Context, r Rollout, weight int32) error {
+	return fmt.Errorf("%w: %s", ErrSetWeightUnsupported, r)
+}
+
+// Status ArgoCD에서 조회한 Rollout Live manifest 기준 진행 상태
+func (d *Dashboard) Status(ctx context.Context, r Rollout) (*Status, error) {
+	manifest, err := d.argo.GetResource(ctx, r.Application, rolloutResource(r))
+	if err != nil {
+		return nil, fmt.Errorf("rollouts: failed to get %s: %w", r, err)
+	}
+	return parseStatus(manifest), nil
+}
+
+// put PUT /api/v1/rollouts/{namespace}/{name}/{action}
+func (d *Dashboard) put(ctx context.Context, r Rollout, action string, payload map[string]any) error {
+	if payload == nil {
+		payload = map[string]any{"name": r.Name, "namespace": r.Namespace}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("rollouts: failed to marshal payload: %w", err)
+	}
+
+	path := fmt.Sprintf("api/v1/rollouts/%s/%s/%s", r.Namespace, r.Name, action)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/%s", d.baseURL, path), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("rollouts: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("rollouts: %s %s: %w", http.MethodPut, path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("rollouts: failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{Method: http.MethodPut, Path: path, StatusCode: resp.StatusCode, Message: resp.Status}
+		var e struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &e) == nil && e.Message != "" {
+			apiErr.Message = e.Message
+		}
+		return apiErr
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/metrics"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"time"
+)
+
+// instrumented 제어 요청 시간 및 결과를 메트릭, trace span으로 기록하는 Controller
+type instrumented struct {
+	next    Controller
+	backend string
+}
+
+// Instrument Controller 요청 메트릭 및 span 기록 (backend: dashboard, kubernetes)
+func Instrument(c Controller, backend string) Controller {
+	return &instrumented{next: c, backend: backend}
+}
+
+func (i *instrumented) observe(ctx context.Context, action string, r Rollout, run func(context.Context) error) error {
+	ctx, span := tracing.Start(ctx, fmt.Sprintf("rollouts %s", action),
+		attribute.String("rollouts.backend", i.backend),
+		attribute.String("rollouts.rollout", r.String()),
+	)
+	start := time.Now()
+	err := run(ctx)
+	metrics.ObserveRollouts(i.backend, action, err, time.Since(start))
+	tracing.End(span, err)
+	return err
+}
+
+func (i *instrumented) Promote(ctx context.Context, r Rollout, full bool) error {
+	action := ActionPromote
+	if full {
+		action = ActionPromoteFull
+	}
+	return i.observe(ctx, string(action), r, func(ctx context.Context) error { return i.next.Promote(ctx, r, full) })
+}
+
+func (i *instrumented) Pause(ctx context.Context, r Rollout) error {
+	return i.observe(ctx, string(ActionPause), r, func(ctx context.Context) error { return i.next.Pause(ctx, r) })
+}
+
+func (i *instrumented) Resume(ctx context.Context, r Rollout) error {
+	return i.observe(ctx, string(ActionResume), r, func(ctx context.Context) error { return i.next.Resume(ctx, r) })
+}
+
+func (i *instrumented) Abort(ctx context.Context, r Rollout) error {
+	return i.observe(ctx, string(ActionAbort), r, func(ctx context.Context) error { return i.next.Abort(ctx, r) })
+}
+
+func (i *instrumented) Retry(ctx context.Context, r Rollout) error {
+	return i.observe(ctx, string(ActionRetry), r, func(ctx context.Context) error { return i.next.Retry(ctx, r) })
+}
+
+func (i *instrumented) Restart(ctx context.Context, r Rollout) error {
+	return i.observe(ctx, string(ActionRestart), r, func(ctx context.Context) error { return i.next.Restart(ctx, r) })
+}
+
+func (i *instrumented) SetWeight(ctx context.Context, r Rollout, weight int32) error {
+	return i.observe(ctx, string(ActionSetWeight), r, func(ctx context.Context) error { return i.next.SetWeight(ctx, r, weight) })
+}
+
+func (i *instrumented) Status(ctx context.Context, r Rollout) (*Status, error) {
+	var status *Status
+	err := i.observe(ctx, "status", r, func(ctx context.Context) error {
+		var err error
+		status, err = i.next.Status(ctx, r)
+		return err
+	})
+	return status, err
+}
@@ -0,0 +1,172 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"time"
+)
+
+// RolloutResource Argo Rollouts CRD
+var RolloutResource = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+
+// kubectl-argo-rollouts 플러그인과 동일한 patch
+const (
+	pausePatch                = `{"spec":{"paused":true}}`
+	unpausePatch              = `{"spec":{"paused":false}}`
+	clearPauseConditionsPatch = `{"status":{"pauseConditions":null}}`
+	promoteFullPatch          = `{"status":{"promoteFull":true}}`
+	abortPatch                = `{"status":{"abort":true}}`
+	retryPatch                = `{"status":{"abort":false}}`
+)
+
+// Kubernetes Kubernetes API로 Rollout CRD의 spec/status를 patch하는 Controller
+type Kubernetes struct {
+	client dynamic.Interface
+}
+
+func NewKubernetes(client dynamic.Interface) *Kubernetes {
+	return &Kubernetes{client: client}
+}
+
+// NewKubernetesFromConfig kubeconfig 경로가 비어있으면 in-cluster ServiceAccount 인증 정보를 사용한다.
+func NewKubernetesFromConfig(kubeconfig, kubeContext string) (*Kubernetes, error) {
+	var (
+		cfg *rest.Config
+		err error
+	)
+
+	if kubeconfig == "" {
+		cfg, err = rest.InClusterConfig()
+	} else {
+		cfg, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+			&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+		).ClientConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rollouts: failed to load kubernetes config: %w", err)
+	}
+
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("rollouts: failed to create kubernetes client: %w", err)
+	}
+	return NewKubernetes(client), nil
+}
+
+// Promote full인 경우 status.promoteFull을 설정하고, 그렇지 않은 경우 일시정지 상태를 해제하거나 다음 Canary 단계로 진행한다.
+func (k *Kubernetes) Promote(ctx context.Context, r Rollout, full bool) error {
+	obj, err := k.get(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused")
+	if paused {
+		if err := k.patch(ctx, r, unpausePatch, false); err != nil {
+			return err
+		}
+	}
+
+	if full {
+		return k.patch(ctx, r, promoteFullPatch, true)
+	}
+
+	// 일시정지 단계(pause step) 혹은 분석 대기 중인 경우 pause condition만 해제
+	if conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "pauseConditions"); len(conditions) > 0 {
+		return k.patch(ctx, r, clearPauseConditionsPatch, true)
+	}
+
+	// pause condition이 없으면 kubectl-argo-rollouts와 같이 Canary 다음 단계로 진행 (수동 일시정지 포함)
+	steps, _, _ := unstructured.NestedSlice(obj.Object, "spec", "strategy", "canary", "steps")
+	if paused && len(steps) == 0 {
+		// Canary 단계가 없는 경우(BlueGreen 등) 일시정지 해제만 수행
+		return nil
+	}
+	index, found, _ := unstructured.NestedInt64(obj.Object, "status", "currentStepIndex")
+	if !found || int(index) >= len(steps) {
+		return fmt.Errorf("rollouts: %s has no step to promote", r)
+	}
+	return k.patch(ctx, r, fmt.Sprintf(`{"status":{"pauseConditions":null,"currentStepIndex":%d}}`, index+1), true)
+}
+
+func (k *Kubernetes) Pause(ctx context.Context, r Rollout) error {
+	return k.patch(ctx, r, pausePatch, false)
+}
+
+func (k *Kubernetes) Resume(ctx context.Context, r Rollout) error {
+	if err := k.patch(ctx, r, unpausePatch, false); err != nil {
+		return err
+	}
+	return k.patch(ctx, r, clearPauseConditionsPatch, true)
+}
+
+func (k *Kubernetes) Abort(ctx context.Context, r Rollout) error {
+	return k.patch(ctx, r, abortPatch, true)
+}
+
+func (k *Kubernetes) Retry(ctx context.Context, r Rollout) error {
+	return k.patch(ctx, r, retryPatch, true)
+}
+
+// Restart spec.restartAt 설정 시 Rollout Controller가 Pod를 순차 재시작한다.
+func (k *Kubernetes) Restart(ctx context.Context, r Rollout) error {
+	return k.patch(ctx, r, fmt.Sprintf(`{"spec":{"restartAt":%q}}`, time.Now().UTC().Format(time.RFC3339)), false)
+}
+
+// SetWeight 요청 가중치의 setWeight 단계로 진행 단계(status.currentStepIndex)를 이동한다. Canary 단계(spec)는 변경하지 않는다.
+func (k *Kubernetes) SetWeight(ctx context.Context, r Rollout, weight int32) error {
+	obj, err := k.get(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	patch, err := canaryStepPatch(r, obj.Object, weight)
+	if err != nil {
+		return err
+	}
+
+	if paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused"); paused {
+		if err := k.patch(ctx, r, unpausePatch, false); err != nil {
+			return err
+		}
+	}
+	return k.patch(ctx, r, patch, true)
+}
+
+func (k *Kubernetes) Status(ctx context.Context, r Rollout) (*Status, error) {
+	obj, err := k.get(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return parseStatus(obj.Object), nil
+}
+
+func (k *Kubernetes) get(ctx context.Context, r Rollout) (*unstructured.Unstructured, error) {
+	obj, err := k.client.Resource(RolloutResource).Namespace(r.Namespace).Get(ctx, r.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("rollouts: failed to get %s: %w", r, err)
+	}
+	return obj, nil
+}
+
+// patch status가 true인 경우 status subresource에 patch
+func (k *Kubernetes) patch(ctx context.Context, r Rollout, patch string, status bool) error {
+	var subresources []string
+	if status {
+		subresources = append(subresources, "status")
+	}
+
+	_, err := k.client.Resource(RolloutResource).Namespace(r.Namespace).Patch(ctx, r.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}, subresources...)
+	if err != nil {
+		return fmt.Errorf("rollouts: failed to patch %s: %w", r, err)
+	}
+	return nil
+}
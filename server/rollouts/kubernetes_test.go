@@ -0,0 +1,356 @@
+package rollouts
+
+import (
+	"context"
+	"errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testRollout = Rollout{Application: "homepage-front", Namespace: "homepage", Name: "homepage-front"}
+
+// patchCall Rollout patch 요청 (subresource가 status인 경우 status subresource patch)
+type patchCall struct {
+	Subresource string
+	Patch       string
+}
+
+// newRolloutObject 진행 중인 Canary Rollout (setWeight 20 → pause → setWeight 50 → pause)
+func newRolloutObject(mutate func(obj map[string]any)) *unstructured.Unstructured {
+	obj := map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata": map[string]any{
+			"name":      testRollout.Name,
+			"namespace": testRollout.Namespace,
+		},
+		"spec": map[string]any{
+			"strategy": map[string]any{
+				"canary": map[string]any{
+					"steps": []any{
+						map[string]any{"setWeight": int64(20)},
+						map[string]any{"pause": map[string]any{}},
+						map[string]any{"setWeight": int64(50)},
+						map[string]any{"pause": map[string]any{}},
+					},
+				},
+			},
+		},
+		"status": map[string]any{
+			"phase":            PhasePaused,
+			"currentStepIndex": int64(1),
+			"currentPodHash":   "6b8f",
+			"stableRS":         "5c7d",
+			"pauseConditions": []any{
+				map[string]any{"reason": "CanaryPauseStep", "startTime": "2025-01-01T00:00:00Z"},
+			},
+		},
+	}
+	if mutate != nil {
+		mutate(obj)
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func newFakeKubernetes(t *testing.T, obj *unstructured.Unstructured) (*Kubernetes, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), obj)
+	return NewKubernetes(client), client
+}
+
+// patches Rollout patch 요청 목록. 모든 patch는 merge patch여야 한다.
+func patches(t *testing.T, client *dynamicfake.FakeDynamicClient) []patchCall {
+	t.Helper()
+
+	var calls []patchCall
+	for _, action := range client.Actions() {
+		patch, ok := action.(k8stesting.PatchAction)
+		if !ok {
+			continue
+		}
+		if patch.GetResource() != RolloutResource || patch.GetNamespace() != testRollout.Namespace || patch.GetName() != testRollout.Name {
+			t.Errorf("patch target = %s %s/%s", patch.GetResource(), patch.GetNamespace(), patch.GetName())
+		}
+		if patch.GetPatchType() != types.MergePatchType {
+			t.Errorf("patch type = %s, want merge patch", patch.GetPatchType())
+		}
+		calls = append(calls, patchCall{Subresource: patch.GetSubresource(), Patch: string(patch.GetPatch())})
+	}
+	return calls
+}
+
+func assertPatches(t *testing.T, got, want []patchCall) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("patches = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("patch[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPromoteStep(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(nil))
+
+	if err := k.Promote(context.Background(), testRollout, false); err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	// pause 단계에서는 pause condition만 해제하고 단계 이동은 Rollout Controller에 맡긴다.
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "status", Patch: `{"status":{"pauseConditions":null}}`},
+	})
+}
+
+func TestPromoteStepWithoutPause(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(func(obj map[string]any) {
+		status := obj["status"].(map[string]any)
+		status["phase"] = PhaseProgressing
+		status["currentStepIndex"] = int64(2)
+		delete(status, "pauseConditions")
+	}))
+
+	if err := k.Promote(context.Background(), testRollout, false); err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "status", Patch: `{"status":{"pauseConditions":null,"currentStepIndex":3}}`},
+	})
+}
+
+func TestPromoteLastStep(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(func(obj map[string]any) {
+		status := obj["status"].(map[string]any)
+		status["currentStepIndex"] = int64(4)
+		delete(status, "pauseConditions")
+	}))
+
+	if err := k.Promote(context.Background(), testRollout, false); err == nil {
+		t.Fatal("Promote succeeded, want no step to promote error")
+	}
+	assertPatches(t, patches(t, client), nil)
+}
+
+func TestPromotePaused(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(func(obj map[string]any) {
+		obj["spec"].(map[string]any)["paused"] = true
+	}))
+
+	if err := k.Promote(context.Background(), testRollout, false); err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "", Patch: `{"spec":{"paused":false}}`},
+		{Subresource: "status", Patch: `{"status":{"pauseConditions":null}}`},
+	})
+}
+
+func TestPromotePausedWithoutCondition(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(func(obj map[string]any) {
+		obj["spec"].(map[string]any)["paused"] = true
+		delete(obj["status"].(map[string]any), "pauseConditions")
+	}))
+
+	if err := k.Promote(context.Background(), testRollout, false); err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	// kubectl-argo-rollouts와 같이 수동 일시정지(spec.paused)를 해제하고 다음 단계로 진행한다.
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "", Patch: `{"spec":{"paused":false}}`},
+		{Subresource: "status", Patch: `{"status":{"pauseConditions":null,"currentStepIndex":2}}`},
+	})
+}
+
+func TestPromotePausedBlueGreen(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(func(obj map[string]any) {
+		obj["spec"].(map[string]any)["paused"] = true
+		obj["spec"].(map[string]any)["strategy"] = map[string]any{"blueGreen": map[string]any{"activeService": "homepage-front"}}
+		delete(obj["status"].(map[string]any), "pauseConditions")
+	}))
+
+	if err := k.Promote(context.Background(), testRollout, false); err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	// Canary 단계가 없으면 일시정지만 해제한다.
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "", Patch: `{"spec":{"paused":false}}`},
+	})
+}
+
+func TestPromotePausedLastStep(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(func(obj map[string]any) {
+		obj["spec"].(map[string]any)["paused"] = true
+		obj["status"].(map[string]any)["currentStepIndex"] = int64(4)
+		delete(obj["status"].(map[string]any), "pauseConditions")
+	}))
+
+	if err := k.Promote(context.Background(), testRollout, false); err == nil || !strings.Contains(err.Error(), "has no step to promote") {
+		t.Fatalf("Promote error = %v, want no step to promote", err)
+	}
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "", Patch: `{"spec":{"paused":false}}`},
+	})
+}
+
+func TestPromoteFull(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(func(obj map[string]any) {
+		obj["spec"].(map[string]any)["paused"] = true
+	}))
+
+	if err := k.Promote(context.Background(), testRollout, true); err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "", Patch: `{"spec":{"paused":false}}`},
+		{Subresource: "status", Patch: `{"status":{"promoteFull":true}}`},
+	})
+}
+
+func TestAbortAndRetry(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(nil))
+	ctx := context.Background()
+
+	if err := k.Abort(ctx, testRollout); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+	if err := k.Retry(ctx, testRollout); err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "status", Patch: `{"status":{"abort":true}}`},
+		{Subresource: "status", Patch: `{"status":{"abort":false}}`},
+	})
+}
+
+func TestPauseAndResume(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(nil))
+	ctx := context.Background()
+
+	if err := k.Pause(ctx, testRollout); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	if err := k.Resume(ctx, testRollout); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "", Patch: `{"spec":{"paused":true}}`},
+		{Subresource: "", Patch: `{"spec":{"paused":false}}`},
+		{Subresource: "status", Patch: `{"status":{"pauseConditions":null}}`},
+	})
+}
+
+func TestRestart(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(nil))
+
+	before := time.Now().UTC().Truncate(time.Second)
+	if err := k.Restart(context.Background(), testRollout); err != nil {
+		t.Fatalf("Restart: %v", err)
+	}
+
+	calls := patches(t, client)
+	if len(calls) != 1 || calls[0].Subresource != "" {
+		t.Fatalf("patches = %+v, want a single spec patch", calls)
+	}
+	m := regexp.MustCompile(`^\{"spec":\{"restartAt":"([^"]+)"\}\}$`).FindStringSubmatch(calls[0].Patch)
+	if m == nil {
+		t.Fatalf("patch = %s", calls[0].Patch)
+	}
+	restartAt, err := time.Parse(time.RFC3339, m[1])
+	if err != nil || restartAt.Before(before) {
+		t.Errorf("restartAt = %s (%v), want RFC3339 time after %s", m[1], err, before)
+	}
+}
+
+func TestSetWeight(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(nil))
+
+	if err := k.SetWeight(context.Background(), testRollout, 50); err != nil {
+		t.Fatalf("SetWeight: %v", err)
+	}
+	// Canary 단계(spec)는 변경하지 않고 setWeight 50 단계로 진행 단계만 이동한다.
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "status", Patch: `{"status":{"pauseConditions":null,"currentStepIndex":2}}`},
+	})
+}
+
+func TestSetWeightPaused(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(func(obj map[string]any) {
+		obj["spec"].(map[string]any)["paused"] = true
+	}))
+
+	if err := k.SetWeight(context.Background(), testRollout, 20); err != nil {
+		t.Fatalf("SetWeight: %v", err)
+	}
+	assertPatches(t, patches(t, client), []patchCall{
+		{Subresource: "", Patch: `{"spec":{"paused":false}}`},
+		{Subresource: "status", Patch: `{"status":{"pauseConditions":null,"currentStepIndex":0}}`},
+	})
+}
+
+func TestSetWeightRejected(t *testing.T) {
+	cases := []struct {
+		name    string
+		mutate  func(obj map[string]any)
+		weight  int32
+		wantErr error
+	}{
+		{name: "weight not in steps", weight: 30, wantErr: ErrWeightNotInSteps},
+		{name: "completed", weight: 50, mutate: func(obj map[string]any) {
+			status := obj["status"].(map[string]any)
+			status["phase"] = PhaseHealthy
+			status["stableRS"] = "6b8f"
+		}},
+		{name: "aborted", weight: 50, mutate: func(obj map[string]any) {
+			obj["status"].(map[string]any)["abort"] = true
+		}},
+		{name: "blue-green", weight: 50, mutate: func(obj map[string]any) {
+			obj["spec"].(map[string]any)["strategy"] = map[string]any{"blueGreen": map[string]any{"activeService": "homepage-front"}}
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			k, client := newFakeKubernetes(t, newRolloutObject(tc.mutate))
+
+			err := k.SetWeight(context.Background(), testRollout, tc.weight)
+			if err == nil {
+				t.Fatal("SetWeight succeeded, want error")
+			}
+			if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+				t.Errorf("err = %v, want %v", err, tc.wantErr)
+			}
+			assertPatches(t, patches(t, client), nil)
+		})
+	}
+}
+
+func TestRunValidatesWeight(t *testing.T) {
+	k, client := newFakeKubernetes(t, newRolloutObject(nil))
+
+	if err := Run(context.Background(), k, testRollout, ActionSetWeight, 120); !errors.Is(err, ErrInvalidWeight) {
+		t.Errorf("err = %v, want ErrInvalidWeight", err)
+	}
+	if len(client.Actions()) != 0 {
+		t.Errorf("actions = %v, want none", client.Actions())
+	}
+}
+
+func TestStatus(t *testing.T) {
+	k, _ := newFakeKubernetes(t, newRolloutObject(nil))
+
+	status, err := k.Status(context.Background(), testRollout)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.Step() != "1/4" || status.Weight != 20 || !status.AwaitingPromotion || status.Completed() {
+		t.Errorf("status = %+v", status)
+	}
+}
@@ -0,0 +1,40 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/argocd"
+	"strings"
+)
+
+// Resolve ArgoCD Application의 resource tree에서 Rollout 조회
+// Rollout이 여러 개인 경우 namespace가 일치하고 이름이 <application>-rollout인 Rollout을 우선한다.
+func Resolve(ctx context.Context, argo *argocd.Client, application, namespace string) (Rollout, error) {
+	tree, err := argo.ResourceTree(ctx, application)
+	if err != nil {
+		return Rollout{}, fmt.Errorf("rollouts: failed to get resource tree of %s: %w", application, err)
+	}
+
+	var candidates []argocd.ResourceNode
+	for _, n := range tree.FindNodes("argoproj.io", "Rollout") {
+		if namespace == "" || n.Namespace == namespace {
+			candidates = append(candidates, n)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return Rollout{}, fmt.Errorf("rollouts: no rollout found in application %s (namespace: %s)", application, namespace)
+	case 1:
+		return Rollout{Application: application, Namespace: candidates[0].Namespace, Name: candidates[0].Name}, nil
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, n := range candidates {
+		if n.Name == fmt.Sprintf("%s-rollout", application) {
+			return Rollout{Application: application, Namespace: n.Namespace, Name: n.Name}, nil
+		}
+		names = append(names, fmt.Sprintf("%s/%s", n.Namespace, n.Name))
+	}
+	return Rollout{}, fmt.Errorf("rollouts: multiple rollouts found in application %s, specify one of: %s", application, strings.Join(names, ", "))
+}
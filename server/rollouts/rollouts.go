@@ -0,0 +1,139 @@
+// Package rollouts Argo Rollouts 제어
+package rollouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"strconv"
+	"strings"
+)
+
+type Action string
+
+const (
+	// ActionPromote 다음 단계로 진행 (한 단계 승인)
+	ActionPromote Action = "promote"
+	// ActionPromoteFull 남은 단계와 분석을 건너뛰고 전체 배포
+	ActionPromoteFull Action = "promote-full"
+	ActionPause       Action = "pause"
+	ActionResume      Action = "resume"
+	ActionAbort       Action = "abort"
+	// ActionRetry 중단(abort)된 Rollout 재시도
+	ActionRetry Action = "retry"
+	// ActionRestart Pod 재시작
+	ActionRestart Action = "restart"
+	// ActionSetWeight Canary 가중치 설정
+	ActionSetWeight Action = "set-weight"
+)
+
+var actions = []Action{ActionPromote, ActionPromoteFull, ActionPause, ActionResume, ActionAbort, ActionRetry, ActionRestart, ActionSetWeight}
+
+var (
+	ErrUnknownAction = errors.New("unknown rollout action")
+	ErrInvalidWeight = errors.New("canary weight must be between 0 and 100")
+	// ErrWeightNotInSteps 요청 가중치의 setWeight 단계가 Canary 단계에 없음
+	ErrWeightNotInSteps = errors.New("canary weight is not defined in rollout steps")
+	// ErrSetWeightUnsupported 진행 단계(status) patch를 지원하지 않는 백엔드
+	ErrSetWeightUnsupported = errors.New("set-weight requires the kubernetes rollouts backend")
+)
+
+// Rollout 제어 대상 Rollout
+type Rollout struct {
+	// Rollout을 관리하는 ArgoCD Application
+	Application string
+	Namespace   string
+	Name        string
+}
+
+func (r Rollout) String() string {
+	return fmt.Sprintf("%s/%s", r.Namespace, r.Name)
+}
+
+// Controller Argo Rollouts 제어 백엔드
+type Controller interface {
+	Promote(ctx context.Context, r Rollout, full bool) error
+	Pause(ctx context.Context, r Rollout) error
+	Resume(ctx context.Context, r Rollout) error
+	Abort(ctx context.Context, r Rollout) error
+	Retry(ctx context.Context, r Rollout) error
+	Restart(ctx context.Context, r Rollout) error
+	SetWeight(ctx context.Context, r Rollout, weight int32) error
+	// Status Rollout 진행 상태 조회
+	Status(ctx context.Context, r Rollout) (*Status, error)
+}
+
+// ParseAction Rollout 액션 이름 확인
+func ParseAction(name string) (Action, error) {
+	for _, a := range actions {
+		if string(a) == name {
+			return a, nil
+		}
+	}
+
+	names := make([]string, 0, len(actions))
+	for _, a := range actions {
+		names = append(names, string(a))
+	}
+	return "", fmt.Errorf("%w %q (%s)", ErrUnknownAction, name, strings.Join(names, ", "))
+}
+
+// Run Rollout 액션 실행. weight는 set-weight 액션에서만 사용한다.
+func Run(ctx context.Context, c Controller, r Rollout, action Action, weight int32) error {
+	switch action {
+	case ActionPromote:
+		return c.Promote(ctx, r, false)
+	case ActionPromoteFull:
+		return c.Promote(ctx, r, true)
+	case ActionPause:
+		return c.Pause(ctx, r)
+	case ActionResume:
+		return c.Resume(ctx, r)
+	case ActionAbort:
+		return c.Abort(ctx, r)
+	case ActionRetry:
+		return c.Retry(ctx, r)
+	case ActionRestart:
+		return c.Restart(ctx, r)
+	case ActionSetWeight:
+		if weight < 0 || weight > 100 {
+			return fmt.Errorf("%w: %d", ErrInvalidWeight, weight)
+		}
+		return c.SetWeight(ctx, r, weight)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownAction, action)
+}
+
+// canaryStepPatch 요청 가중치의 setWeight 단계로 진행 단계를 옮기는 status patch
+// Canary 단계(spec)는 변경하지 않으므로 ArgoCD Application은 Synced 상태를 유지하며, 이후 배포의 단계에도 영향을 주지 않는다.
+// 진행 중인 Canary 업데이트에서만 사용할 수 있고, 요청 가중치는 spec.strategy.canary.steps의 setWeight 중 하나여야 한다.
+func canaryStepPatch(r Rollout, obj map[string]any, weight int32) (string, error) {
+	steps, found, _ := unstructured.NestedSlice(obj, "spec", "strategy", "canary", "steps")
+	if !found {
+		return "", fmt.Errorf("rollouts: %s is not a canary rollout", r)
+	}
+
+	status := parseStatus(obj)
+	switch {
+	case status.Aborted:
+		return "", fmt.Errorf("rollouts: %s is aborted, retry before setting canary weight", r)
+	case status.Completed():
+		return "", fmt.Errorf("rollouts: %s has no canary update in progress", r)
+	}
+
+	var weights []string
+	for i, step := range steps {
+		step, ok := step.(map[string]any)
+		if !ok {
+			continue
+		}
+		if w, found := nestedIntFound(step, "setWeight"); found {
+			if int32(w) == weight {
+				return fmt.Sprintf(`{"status":{"pauseConditions":null,"currentStepIndex":%d}}`, i), nil
+			}
+			weights = append(weights, strconv.FormatInt(w, 10))
+		}
+	}
+	return "", fmt.Errorf("%w: %s has no setWeight %d step (available: %s)", ErrWeightNotInSteps, r, weight, strings.Join(weights, ", "))
+}
@@ -0,0 +1,150 @@
+package rollouts
+
+import (
+	"fmt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Rollout status.phase
+const (
+	PhaseProgressing = "Progressing"
+	PhasePaused      = "Paused"
+	PhaseHealthy     = "Healthy"
+	PhaseDegraded    = "Degraded"
+)
+
+// Status Rollout 진행 상태
+type Status struct {
+	Phase   string
+	Message string
+	// Canary 단계 (BlueGreen인 경우 TotalSteps는 0)
+	CurrentStep int
+	TotalSteps  int
+	// Canary 가중치 (%)
+	Weight int32
+
+	Replicas          int64
+	UpdatedReplicas   int64
+	ReadyReplicas     int64
+	AvailableReplicas int64
+
+	Paused bool
+	// 수동 승인(promote/resume)이 필요한 일시정지 상태. duration이 지정된 pause 단계는 자동으로 진행되므로 제외한다.
+	AwaitingPromotion bool
+	Aborted           bool
+	// 진행 중인 AnalysisRun 이름 및 상태 (Running, Successful, Failed, Error, Inconclusive)
+	AnalysisRun    string
+	AnalysisStatus string
+
+	CurrentPodHash string
+	StableRS       string
+}
+
+// Completed 모든 단계가 종료되고 새 ReplicaSet이 stable로 전환된 상태
+func (s Status) Completed() bool {
+	return s.Phase == PhaseHealthy && s.CurrentPodHash != "" && s.CurrentPodHash == s.StableRS
+}
+
+// Failed 중단(abort)되었거나 Degraded 상태
+func (s Status) Failed() bool {
+	return s.Aborted || s.Phase == PhaseDegraded
+}
+
+// Step 진행 단계 표시 (예: 2/4). Canary 단계가 없는 경우 빈 문자열
+func (s Status) Step() string {
+	if s.TotalSteps == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", s.CurrentStep, s.TotalSteps)
+}
+
+// parseStatus Rollout 오브젝트(Live manifest)에서 진행 상태 추출
+func parseStatus(obj map[string]any) *Status {
+	s := &Status{}
+	// Live manifest를 JSON으로 decode한 경우 숫자가 float64이므로 nestedInt로 조회
+	s.Phase, _, _ = unstructured.NestedString(obj, "status", "phase")
+	s.Message, _, _ = unstructured.NestedString(obj, "status", "message")
+	s.Replicas = nestedInt(obj, "status", "replicas")
+	s.UpdatedReplicas = nestedInt(obj, "status", "updatedReplicas")
+	s.ReadyReplicas = nestedInt(obj, "status", "readyReplicas")
+	s.AvailableReplicas = nestedInt(obj, "status", "availableReplicas")
+	s.Aborted, _, _ = unstructured.NestedBool(obj, "status", "abort")
+	s.CurrentPodHash, _, _ = unstructured.NestedString(obj, "status", "currentPodHash")
+	s.StableRS, _, _ = unstructured.NestedString(obj, "status", "stableRS")
+
+	paused, _, _ := unstructured.NestedBool(obj, "spec", "paused")
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "pauseConditions")
+	s.Paused = paused || len(conditions) > 0
+
+	steps, _, _ := unstructured.NestedSlice(obj, "spec", "strategy", "canary", "steps")
+	index := nestedInt(obj, "status", "currentStepIndex")
+	s.TotalSteps = len(steps)
+	s.CurrentStep = int(index)
+
+	s.AwaitingPromotion = paused
+	if len(conditions) > 0 {
+		s.AwaitingPromotion = true
+		if int(index) < len(steps) {
+			if step, ok := steps[index].(map[string]any); ok {
+				if _, timed, _ := unstructured.NestedFieldNoCopy(step, "pause", "duration"); timed {
+					s.AwaitingPromotion = paused
+				}
+			}
+		}
+	}
+
+	// Traffic routing을 사용하는 경우 실제 가중치, 그렇지 않은 경우 현재 단계까지의 마지막 setWeight
+	if weight, found := nestedIntFound(obj, "status", "canary", "weights", "canary", "weight"); found {
+		s.Weight = int32(weight)
+	} else if len(steps) > 0 {
+		if int(index) >= len(steps) {
+			s.Weight = 100
+		}
+		for i := 0; i < int(index) && i < len(steps); i++ {
+			if step, ok := steps[i].(map[string]any); ok {
+				if w, found := nestedIntFound(step, "setWeight"); found {
+					s.Weight = int32(w)
+				}
+			}
+		}
+	}
+
+	for _, path := range [][]string{
+		{"status", "canary", "currentStepAnalysisRunStatus"},
+		{"status", "canary", "currentBackgroundAnalysisRunStatus"},
+		{"status", "blueGreen", "prePromotionAnalysisRunStatus"},
+		{"status", "blueGreen", "postPromotionAnalysisRunStatus"},
+	} {
+		run, found, _ := unstructured.NestedMap(obj, path...)
+		if !found {
+			continue
+		}
+		s.AnalysisRun, _ = run["name"].(string)
+		s.AnalysisStatus, _ = run["status"].(string)
+		break
+	}
+
+	return s
+}
+
+func nestedInt(obj map[string]any, fields ...string) int64 {
+	v, _ := nestedIntFound(obj, fields...)
+	return v
+}
+
+// nestedIntFound Kubernetes API(int64)와 JSON decode(float64) 숫자 모두 지원
+func nestedIntFound(obj map[string]any, fields ...string) (int64, bool) {
+	v, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if !found || err != nil {
+		return 0, false
+	}
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case float64:
+		return int64(n), true
+	}
+	return 0, false
+}
@@ -1,16 +1,56 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/antonio-kim-1994/devops-relay/server/config"
 	"github.com/antonio-kim-1994/devops-relay/server/handler"
+	"github.com/antonio-kim-1994/devops-relay/server/logging"
+	"github.com/antonio-kim-1994/devops-relay/server/metrics"
 	"github.com/antonio-kim-1994/devops-relay/server/middleware"
+	"github.com/antonio-kim-1994/devops-relay/server/policy"
+	"github.com/antonio-kim-1994/devops-relay/server/tracing"
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
+	// 정책 테스트 서브 커맨드 (devops-relay-server policy test <policy-dir>)
+	if len(os.Args) > 1 && os.Args[1] == "policy" {
+		os.Exit(policy.RunCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	// 로그 레벨, 형식, 파일 출력 및 민감 정보 마스킹 설정
+	logCfg, err := config.Logging()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load logging config.")
+	}
+	closeLog, err := logging.Setup(logging.Options{
+		Level:          logCfg.Level,
+		Format:         logCfg.Format,
+		SampleRate:     logCfg.SampleRate,
+		File:           logCfg.File,
+		FileMaxSize:    logCfg.FileMaxSize,
+		FileMaxBackups: logCfg.FileMaxBackups,
+		FileMaxAge:     logCfg.FileMaxAge,
+		RedactKeys:     logCfg.RedactKeys,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to setup logging.")
+	}
+	defer closeLog()
+
+	// ctx가 지정된 로그에 trace_id, span_id 기록
+	log.Logger = log.Hook(tracing.LogHook{})
+	// 요청/배포 로거가 없는 context의 log.Ctx(ctx)는 기본 로거 사용
+	zerolog.DefaultContextLogger = &log.Logger
+
 	if os.Getenv("APP_ENV") == "" {
 		log.Fatal().Msg("No APP_ENV environment variable served.")
 	}
@@ -18,20 +58,90 @@ func main() {
 	// config 설정
 	cfg := config.Setting()
 
+	// OpenTelemetry trace export 설정
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		Protocol:    cfg.Tracing.Protocol,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to setup tracing.")
+	}
+
+	// 핸들러 의존성 초기화
+	if err := handler.Setup(cfg); err != nil {
+		log.Fatal().Err(err).Msg("failed to setup DevOps Relay Server handlers.")
+	}
+
 	g := gin.Default()
 
 	// Route 등록
-	registerMainRoutes(g)
+	registerMainRoutes(g, cfg.Tracing.ServiceName)
 
-	err := g.Run(fmt.Sprintf(":%s", cfg.ServerPort))
-	if err != nil {
-		log.Fatal().Err(err).Msg("failed to run DevOps Relay Gateway.")
+	// METRICS_PORT 설정 시 /metrics는 전용 리스너에서만 제공
+	var metricsSrv *http.Server
+	if cfg.MetricsPort != "" {
+		m := gin.New()
+		m.Use(gin.Recovery())
+		registerMetricsRoute(m, cfg.MetricsToken)
+		metricsSrv = &http.Server{
+			Addr:    fmt.Sprintf(":%s", cfg.MetricsPort),
+			Handler: m,
+		}
+	} else {
+		registerMetricsRoute(g, cfg.MetricsToken)
+	}
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%s", cfg.ServerPort),
+		Handler: g,
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("failed to run DevOps Relay Server.")
+		}
+	}()
+
+	if metricsSrv != nil {
+		go func() {
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal().Err(err).Msg("failed to run DevOps Relay Server metrics listener.")
+			}
+		}()
+	}
+
+	// 종료 시그널 수신 시 요청 처리 종료 후 배포 파이프라인 중단 (배포 상태는 재기동 시 재개)
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Info().Msg("shutting down DevOps Relay Server.")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to shutdown http server.")
+	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("failed to shutdown metrics listener.")
+		}
+	}
+	if err := handler.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to shutdown deploy pipelines.")
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to flush traces.")
 	}
 }
 
-func registerMainRoutes(g *gin.Engine) {
+func registerMainRoutes(g *gin.Engine, service string) {
 	// 500 error 혹은 panic으로 서버 shutdown 시 재기동
 	g.Use(gin.Recovery())
+	g.Use(middleware.RequestTracing(service))
+	g.Use(middleware.RequestID())
+	g.Use(middleware.RequestMetrics())
 	common := g.Group("/healthz")
 	{
 		common.GET("/healthcheck", handler.CommonHealthCheck)
@@ -44,9 +154,44 @@ func registerMainRoutes(g *gin.Engine) {
 		update.POST("/slack", handler.HandleSlackResponse)
 	}
 
+	deployments := g.Group("/deployments")
+	{
+		deployments.Use(middleware.ValidateApiRequest())
+		deployments.GET("", handler.HandleDeploymentList)
+		deployments.GET("/:id", handler.HandleDeploymentGet)
+		deployments.POST("/:id/rollback", handler.HandleDeploymentRollback)
+	}
+
+	apps := g.Group("/apps")
+	{
+		apps.Use(middleware.ValidateApiRequest())
+		apps.POST("/:app/rollback", handler.HandleApplicationRollback)
+		apps.POST("/:app/rollouts/:action", handler.HandleRolloutAction)
+	}
+
+	// 배포 lifecycle 이벤트 외부 webhook 구독, 전송 기록 조회 및 재전송
+	webhooks := g.Group("/webhooks")
+	{
+		webhooks.Use(middleware.ValidateApiRequest())
+		webhooks.POST("", handler.HandleWebhookCreate)
+		webhooks.GET("", handler.HandleWebhookList)
+		webhooks.GET("/:id", handler.HandleWebhookGet)
+		webhooks.DELETE("/:id", handler.HandleWebhookDelete)
+		webhooks.GET("/:id/deliveries", handler.HandleWebhookDeliveries)
+		webhooks.POST("/:id/deliveries/:delivery/redeliver", handler.HandleWebhookRedeliver)
+	}
+
 	sys := g.Group("/sys")
 	{
 		sys.Use(middleware.ValidateApiRequest())
 		sys.POST("/healthcheck", handler.ServerHealthCheck)
 	}
+
+	// Prometheus /metrics와 달리 API 인증(Request-Auth)을 사용하며 METRICS_PORT 설정과 무관하게 서버 포트에서 제공
+	g.GET("/metrics/dora", middleware.ValidateApiRequest(), handler.HandleDORAMetrics)
+}
+
+// registerMetricsRoute Prometheus /metrics (METRICS_TOKEN 설정 시 Bearer 토큰 인증)
+func registerMetricsRoute(g *gin.Engine, token string) {
+	g.GET("/metrics", middleware.ValidateMetricsRequest(token), gin.WrapH(metrics.Handler()))
 }
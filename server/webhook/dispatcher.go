@@ -0,0 +1,565 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/antonio-kim-1994/devops-relay/server/logging"
+	"github.com/antonio-kim-1994/devops-relay/server/metrics"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWorkers     = 4
+	defaultMaxAttempts = 5
+	defaultBackoff     = 10 * time.Second
+	maxBackoff         = 10 * time.Minute
+	queueSize          = 1000
+	// 보관할 최대 전송 기록 수 (오래된 종료 기록부터 삭제)
+	maxDeliveries = 1000
+)
+
+// Options webhook 전송 설정
+type Options struct {
+	// 구독 및 전송 기록 상태 파일 경로 (미설정 시 메모리에만 보관)
+	StatePath string
+	// 동시 전송 수, 재시도 포함 최대 시도 횟수 및 첫 재시도 대기 시간 (이후 2배씩 증가)
+	Workers     int
+	MaxAttempts int
+	Backoff     time.Duration
+	// 루프백, 링크 로컬, 사설 및 클러스터 내부 주소 구독 허용 (기본: 거부)
+	AllowPrivateNetworks bool
+	// 미설정 시 NewTransport(AllowPrivateNetworks)를 사용한다.
+	HTTPClient *http.Client
+}
+
+// Dispatcher webhook 구독 관리 및 이벤트 전송
+type Dispatcher struct {
+	mu            sync.Mutex
+	subscriptions map[string]*Subscription
+	subOrder      []string
+	deliveries    map[string]*Delivery
+	order         []string
+	statePath     string
+
+	maxAttempts  int
+	backoff      time.Duration
+	allowPrivate bool
+	httpClient   *http.Client
+
+	queue  chan string
+	timers map[string]*time.Timer
+	// 종료 요청 및 워커 종료
+	stop chan struct{}
+	wg   sync.WaitGroup
+	// 종료 대기 시간 초과 시 진행 중인 전송 취소
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+// state 상태 파일 형식 (구독 secret 포함)
+type state struct {
+	Subscriptions []subscriptionState `json:"subscriptions"`
+	Deliveries    []*Delivery         `json:"deliveries"`
+}
+
+type subscriptionState struct {
+	Subscription
+	Secret string `json:"secret"`
+}
+
+// Open 상태 파일에서 구독 및 전송 기록을 복원한 Dispatcher 생성. 전송 중이던(pending) 기록은 다시 전송한다.
+func Open(opts Options) (*Dispatcher, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = defaultWorkers
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultBackoff
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second, Transport: NewTransport(opts.AllowPrivateNetworks)}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		subscriptions: map[string]*Subscription{},
+		deliveries:    map[string]*Delivery{},
+		statePath:     opts.StatePath,
+		maxAttempts:   opts.MaxAttempts,
+		backoff:       opts.Backoff,
+		allowPrivate:  opts.AllowPrivateNetworks,
+		httpClient:    opts.HTTPClient,
+		queue:         make(chan string, queueSize),
+		timers:        map[string]*time.Timer{},
+		stop:          make(chan struct{}),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+	if err := d.load(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	for i := 0; i < opts.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+
+	d.mu.Lock()
+	for _, id := range d.order {
+		if d.deliveries[id].Status == DeliveryPending {
+			d.schedule(id, 0)
+		}
+	}
+	d.mu.Unlock()
+	return d, nil
+}
+
+// Subscribe 구독 등록. secret 미지정 시 발급하며, 반환된 구독에만 secret이 포함된다.
+// AllowPrivateNetworks 미설정 시 내부 주소(루프백, 링크 로컬, 사설, 클러스터 DNS) 구독은 ErrPrivateNetwork로 거부한다.
+func (d *Dispatcher) Subscribe(s Subscription) (Subscription, error) {
+	if err := validateURL(s.URL, d.allowPrivate); err != nil {
+		return Subscription{}, err
+	}
+	s.ID = NewID("wh-")
+	s.CreatedAt = time.Now()
+	if s.Secret == "" {
+		s.Secret = NewSecret()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscriptions[s.ID] = &s
+	d.subOrder = append(d.subOrder, s.ID)
+	d.save()
+	return s, nil
+}
+
+// Unsubscribe 구독 삭제. 재시도 대기 중인 전송은 실패로 종료한다.
+func (d *Dispatcher) Unsubscribe(id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, exist := d.subscriptions[id]; !exist {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	delete(d.subscriptions, id)
+	d.subOrder = slices.DeleteFunc(d.subOrder, func(s string) bool { return s == id })
+
+	for _, delivery := range d.deliveries {
+		if delivery.SubscriptionID == id && delivery.Status == DeliveryPending {
+			if timer, exist := d.timers[delivery.ID]; exist {
+				timer.Stop()
+				delete(d.timers, delivery.ID)
+			}
+			delivery.Status = DeliveryFailed
+			delivery.NextAttemptAt = time.Time{}
+			delivery.UpdatedAt = time.Now()
+		}
+	}
+	d.save()
+	return nil
+}
+
+// Subscriptions 구독 목록 (등록 순, secret 제외)
+func (d *Dispatcher) Subscriptions() []Subscription {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	subs := make([]Subscription, 0, len(d.subOrder))
+	for _, id := range d.subOrder {
+		s := *d.subscriptions[id]
+		s.Secret = ""
+		subs = append(subs, s)
+	}
+	return subs
+}
+
+// Subscription 구독 조회 (secret 제외)
+func (d *Dispatcher) Subscription(id string) (Subscription, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, exist := d.subscriptions[id]
+	if !exist {
+		return Subscription{}, false
+	}
+	sub := *s
+	sub.Secret = ""
+	return sub, true
+}
+
+// Publish 필터가 일치하는 구독마다 전송 기록을 만들고 전송 대기열에 등록
+// 배포 기록 잠금 상태에서 호출되므로 전송을 기다리지 않는다.
+func (d *Dispatcher) Publish(e Event) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		log.Error().Err(err).Msgf("webhook | failed to encode event %s", e.Type)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	select {
+	case <-d.stop:
+		return
+	default:
+	}
+
+	var published bool
+	for _, id := range d.subOrder {
+		if !d.subscriptions[id].Matches(e) {
+			continue
+		}
+		d.add(&Delivery{
+			ID:             NewID("whd-"),
+			SubscriptionID: id,
+			EventID:        e.ID,
+			EventType:      e.Type,
+			Payload:        payload,
+		})
+		published = true
+	}
+	if published {
+		d.save()
+	}
+}
+
+// Deliveries 구독의 전송 기록 (최근 순)
+func (d *Dispatcher) Deliveries(subscriptionID string) []Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deliveries := []Delivery{}
+	for i := len(d.order) - 1; i >= 0; i-- {
+		if delivery := d.deliveries[d.order[i]]; delivery.SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, d.copyOf(delivery))
+		}
+	}
+	return deliveries
+}
+
+// Delivery 전송 기록 조회
+func (d *Dispatcher) Delivery(id string) (Delivery, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delivery, exist := d.deliveries[id]
+	if !exist {
+		return Delivery{}, false
+	}
+	return d.copyOf(delivery), true
+}
+
+// Redeliver 전송 기록의 이벤트를 새 전송으로 다시 전송. CloudEvents id가 같으므로 수신 측은 중복 수신을 구분할 수 있다.
+func (d *Dispatcher) Redeliver(id string) (Delivery, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	original, exist := d.deliveries[id]
+	if !exist {
+		return Delivery{}, fmt.Errorf("%w: delivery %s", ErrNotFound, id)
+	}
+	if _, exist := d.subscriptions[original.SubscriptionID]; !exist {
+		return Delivery{}, fmt.Errorf("%w: subscription %s", ErrNotFound, original.SubscriptionID)
+	}
+
+	delivery := &Delivery{
+		ID:             NewID("whd-"),
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		RedeliveryOf:   original.ID,
+		Payload:        original.Payload,
+	}
+	d.add(delivery)
+	d.save()
+	return d.copyOf(delivery), nil
+}
+
+// Close 진행 중인 전송을 기다린 뒤 종료. 재시도 대기 중인 전송은 상태 파일에 남아 재기동 시 다시 전송한다.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.closeOnce.Do(func() {
+		d.mu.Lock()
+		close(d.stop)
+		for id, timer := range d.timers {
+			timer.Stop()
+			delete(d.timers, id)
+		}
+		d.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return fmt.Errorf("webhook: in-flight deliveries canceled: %w", ctx.Err())
+	}
+}
+
+// add 전송 기록 등록 및 대기열 등록 (d.mu 잠금 상태에서 호출)
+func (d *Dispatcher) add(delivery *Delivery) {
+	now := time.Now()
+	delivery.Status = DeliveryPending
+	delivery.Attempts = []Attempt{}
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	d.deliveries[delivery.ID] = delivery
+	d.order = append(d.order, delivery.ID)
+	d.prune()
+	d.schedule(delivery.ID, 0)
+}
+
+// schedule delay 이후 전송 대기열 등록 (d.mu 잠금 상태에서 호출). 대기열이 가득 찬 경우 backoff 이후 다시 등록한다.
+func (d *Dispatcher) schedule(id string, delay time.Duration) {
+	select {
+	case <-d.stop:
+		return
+	default:
+	}
+
+	if delay <= 0 {
+		select {
+		case d.queue <- id:
+			return
+		default:
+			delay = d.backoff
+		}
+	}
+
+	d.timers[id] = time.AfterFunc(delay, func() {
+		d.mu.Lock()
+		delete(d.timers, id)
+		d.mu.Unlock()
+
+		select {
+		case d.queue <- id:
+		case <-d.stop:
+		}
+	})
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for {
+		// 종료 요청 시 대기열에 남은 전송은 재기동 이후 전송
+		select {
+		case <-d.stop:
+			return
+		default:
+		}
+
+		select {
+		case id := <-d.queue:
+			d.attempt(id)
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// attempt 전송 1회 시도 및 결과 기록. 재시도 대상 오류(연결 오류, 408, 429, 5xx)는 backoff 이후 다시 시도한다.
+// 내부 주소로 해석된 연결(ErrPrivateNetwork)은 재시도하지 않는다.
+func (d *Dispatcher) attempt(id string) {
+	d.mu.Lock()
+	delivery, exist := d.deliveries[id]
+	if !exist || delivery.Status != DeliveryPending {
+		d.mu.Unlock()
+		return
+	}
+	sub, exist := d.subscriptions[delivery.SubscriptionID]
+	if !exist {
+		delivery.Status = DeliveryFailed
+		delivery.UpdatedAt = time.Now()
+		d.save()
+		d.mu.Unlock()
+		return
+	}
+	target, secret, payload := sub.URL, sub.Secret, delivery.Payload
+	d.mu.Unlock()
+
+	result, retry := d.send(target, secret, id, payload)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if delivery.Status != DeliveryPending {
+		return
+	}
+
+	delivery.Attempts = append(delivery.Attempts, result)
+	delivery.UpdatedAt = time.Now()
+	delivery.NextAttemptAt = time.Time{}
+	switch {
+	case result.Error == "":
+		delivery.Status = DeliverySucceeded
+		metrics.ObserveWebhookDelivery("succeeded")
+	case d.ctx.Err() != nil:
+		// 종료 중 취소된 전송은 재기동 이후 다시 시도
+	case retry && len(delivery.Attempts) < d.maxAttempts:
+		backoff := min(d.backoff<<(len(delivery.Attempts)-1), maxBackoff)
+		delivery.NextAttemptAt = time.Now().Add(backoff)
+		d.schedule(id, backoff)
+		metrics.ObserveWebhookDelivery("retry")
+	default:
+		delivery.Status = DeliveryFailed
+		metrics.ObserveWebhookDelivery("failed")
+		log.Warn().Msgf("webhook | delivery %s (%s) to subscription %s failed after %d attempts: %s", id, delivery.EventType, delivery.SubscriptionID, len(delivery.Attempts), result.Error)
+	}
+	d.save()
+}
+
+// send CloudEvents 본문 전송. 두 번째 반환값은 재시도 대상 여부
+func (d *Dispatcher) send(target, secret, id string, payload []byte) (Attempt, bool) {
+	start := time.Now()
+	result := Attempt{Time: start}
+
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to create request: %s", err)
+		return result, false
+	}
+	timestamp := strconv.FormatInt(start.Unix(), 10)
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("User-Agent", "devops-relay-webhook")
+	req.Header.Set(DeliveryHeader, id)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, payload))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		result.Error = logging.Redact(err.Error())
+		result.DurationMs = time.Since(start).Milliseconds()
+		return result, !errors.Is(err, context.Canceled) && !errors.Is(err, ErrPrivateNetwork)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	result.StatusCode = resp.StatusCode
+	result.Response = logging.Redact(string(body))
+	result.DurationMs = time.Since(start).Milliseconds()
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return result, false
+	}
+
+	result.Error = resp.Status
+	retry := resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+	return result, retry
+}
+
+// copyOf 전송 기록 복사 (d.mu 잠금 상태에서 호출)
+func (d *Dispatcher) copyOf(delivery *Delivery) Delivery {
+	c := *delivery
+	c.Attempts = slices.Clone(delivery.Attempts)
+	return c
+}
+
+// prune 보관 개수를 초과한 오래된 종료 전송 기록 삭제 (d.mu 잠금 상태에서 호출)
+func (d *Dispatcher) prune() {
+	excess := len(d.order) - maxDeliveries
+	if excess <= 0 {
+		return
+	}
+	d.order = slices.DeleteFunc(d.order, func(id string) bool {
+		if excess > 0 && d.deliveries[id].Status != DeliveryPending {
+			delete(d.deliveries, id)
+			excess--
+			return true
+		}
+		return false
+	})
+}
+
+// load 상태 파일에서 구독 및 전송 기록 복원
+func (d *Dispatcher) load() error {
+	if d.statePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(d.statePath), 0o755); err != nil {
+		return fmt.Errorf("webhook: failed to create state directory: %w", err)
+	}
+
+	data, err := os.ReadFile(d.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("webhook: failed to read state file: %w", err)
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("webhook: failed to decode state file %s: %w", d.statePath, err)
+	}
+	for _, s := range st.Subscriptions {
+		sub := s.Subscription
+		sub.Secret = s.Secret
+		d.subscriptions[sub.ID] = &sub
+		d.subOrder = append(d.subOrder, sub.ID)
+	}
+	for _, delivery := range st.Deliveries {
+		delivery.NextAttemptAt = time.Time{}
+		d.deliveries[delivery.ID] = delivery
+		d.order = append(d.order, delivery.ID)
+	}
+	return nil
+}
+
+// save 상태 파일에 구독 및 전송 기록 저장 (d.mu 잠금 상태에서 호출)
+// 임시 파일에 기록한 뒤 교체하므로 저장 중 종료되어도 이전 상태 파일은 유지된다.
+func (d *Dispatcher) save() {
+	if d.statePath == "" {
+		return
+	}
+
+	st := state{
+		Subscriptions: make([]subscriptionState, 0, len(d.subOrder)),
+		Deliveries:    make([]*Delivery, 0, len(d.order)),
+	}
+	for _, id := range d.subOrder {
+		s := d.subscriptions[id]
+		st.Subscriptions = append(st.Subscriptions, subscriptionState{Subscription: *s, Secret: s.Secret})
+	}
+	for _, id := range d.order {
+		st.Deliveries = append(st.Deliveries, d.deliveries[id])
+	}
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		log.Error().Err(err).Msg("webhook | failed to encode webhook state")
+		return
+	}
+
+	tmp := d.statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		log.Error().Err(err).Msgf("webhook | failed to write webhook state: %s", tmp)
+		return
+	}
+	if err := os.Rename(tmp, d.statePath); err != nil {
+		log.Error().Err(err).Msgf("webhook | failed to replace webhook state: %s", d.statePath)
+	}
+}
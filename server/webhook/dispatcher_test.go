@@ -0,0 +1,346 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiver webhook 요청을 기록하고 statuses 순서대로 응답하는 테스트 서버 (이후 status로 응답)
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, status int, statuses ...int) *receiver {
+	t.Helper()
+	r := &receiver{status: status, statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		status := r.status
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) setStatus(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *receiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+// openDispatcher 테스트 서버(127.0.0.1)로 전송하기 위해 내부 주소를 허용한 Dispatcher
+func openDispatcher(t *testing.T, opts Options) *Dispatcher {
+	t.Helper()
+	opts.AllowPrivateNetworks = true
+	d, err := Open(opts)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close(context.Background()) })
+	return d
+}
+
+func subscribe(t *testing.T, d *Dispatcher, url string) Subscription {
+	t.Helper()
+	sub, err := d.Subscribe(Subscription{URL: url, Secret: "secret"})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	return sub
+}
+
+func testEvent(id string) Event {
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Source:          "/devops-relay/deployments",
+		Type:            TypePrefix + "finished",
+		Time:            time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+		DataContentType: "application/json",
+		Application:     "homepage-front",
+		Environment:     "prod",
+		Data:            []byte(`{"id":"dep-1"}`),
+	}
+}
+
+// publish 이벤트 전송 기록 ID
+func publish(t *testing.T, d *Dispatcher, sub Subscription, e Event) string {
+	t.Helper()
+	d.Publish(e)
+	deliveries := d.Deliveries(sub.ID)
+	if len(deliveries) == 0 || deliveries[0].EventID != e.ID {
+		t.Fatalf("deliveries = %+v, want %s published", deliveries, e.ID)
+	}
+	return deliveries[0].ID
+}
+
+// waitDelivery 전송 기록이 ready를 만족할 때까지 대기
+func waitDelivery(t *testing.T, d *Dispatcher, id string, ready func(Delivery) bool) Delivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		delivery, _ := d.Delivery(id)
+		if ready(delivery) {
+			return delivery
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("delivery = %+v, timed out", delivery)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func finished(delivery Delivery) bool { return delivery.Status != DeliveryPending }
+
+func TestDispatcherSignature(t *testing.T) {
+	r := newReceiver(t, http.StatusNoContent)
+	d := openDispatcher(t, Options{})
+	sub := subscribe(t, d, r.URL)
+
+	id := publish(t, d, sub, testEvent("evt-1"))
+	if delivery := waitDelivery(t, d, id, finished); delivery.Status != DeliverySucceeded || len(delivery.Attempts) != 1 || delivery.Attempts[0].StatusCode != http.StatusNoContent {
+		t.Fatalf("delivery = %+v, want succeeded", delivery)
+	}
+
+	r.mu.Lock()
+	req, body := r.requests[0], r.bodies[0]
+	r.mu.Unlock()
+	if req.Header.Get("Content-Type") != ContentType || req.Header.Get(DeliveryHeader) != id {
+		t.Errorf("headers = %v", req.Header)
+	}
+	timestamp := req.Header.Get(TimestampHeader)
+	if got, want := req.Header.Get(SignatureHeader), Sign("secret", timestamp, body); got != want {
+		t.Errorf("%s = %s, want %s", SignatureHeader, got, want)
+	}
+	if !strings.Contains(string(body), `"id":"evt-1"`) || !strings.Contains(string(body), `"type":"devops-relay.deployment.finished"`) {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestDispatcherRetry(t *testing.T) {
+	cases := []struct {
+		name     string
+		statuses []int
+		status   DeliveryStatus
+		attempts int
+	}{
+		{name: "retry until success", statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusRequestTimeout, http.StatusOK}, status: DeliverySucceeded, attempts: 4},
+		{name: "max attempts", statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK}, status: DeliveryFailed, attempts: 4},
+		// 그 외 응답은 재시도하지 않는다.
+		{name: "not retryable", statuses: []int{http.StatusBadRequest, http.StatusOK}, status: DeliveryFailed, attempts: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newReceiver(t, http.StatusOK, tc.statuses...)
+			backoff := 20 * time.Millisecond
+			d := openDispatcher(t, Options{MaxAttempts: 4, Backoff: backoff})
+			sub := subscribe(t, d, r.URL)
+
+			delivery := waitDelivery(t, d, publish(t, d, sub, testEvent("evt-1")), finished)
+			if delivery.Status != tc.status || len(delivery.Attempts) != tc.attempts {
+				t.Fatalf("delivery = %s after %d attempts, want %s after %d", delivery.Status, len(delivery.Attempts), tc.status, tc.attempts)
+			}
+			// backoff는 시도마다 2배씩 증가한다. (20ms, 40ms, 80ms)
+			for i := 1; i < len(delivery.Attempts); i++ {
+				want := backoff << (i - 1)
+				if gap := delivery.Attempts[i].Time.Sub(delivery.Attempts[i-1].Time); gap < want {
+					t.Errorf("attempt %d after %s, want at least %s", i+1, gap, want)
+				}
+			}
+			if !delivery.NextAttemptAt.IsZero() {
+				t.Errorf("next_attempt_at = %s, want cleared", delivery.NextAttemptAt)
+			}
+		})
+	}
+}
+
+func TestDispatcherNextAttempt(t *testing.T) {
+	r := newReceiver(t, http.StatusServiceUnavailable)
+	d := openDispatcher(t, Options{Backoff: 5 * time.Minute})
+	sub := subscribe(t, d, r.URL)
+
+	delivery := waitDelivery(t, d, publish(t, d, sub, testEvent("evt-1")), func(d Delivery) bool { return len(d.Attempts) == 1 })
+	if delivery.Status != DeliveryPending || delivery.NextAttemptAt.Before(time.Now().Add(4*time.Minute)) {
+		t.Errorf("delivery = %s (next %s), want retry scheduled after backoff", delivery.Status, delivery.NextAttemptAt)
+	}
+}
+
+func TestDispatcherUnsubscribe(t *testing.T) {
+	r := newReceiver(t, http.StatusServiceUnavailable)
+	d := openDispatcher(t, Options{Backoff: time.Hour})
+	sub := subscribe(t, d, r.URL)
+	id := publish(t, d, sub, testEvent("evt-1"))
+	waitDelivery(t, d, id, func(d Delivery) bool { return len(d.Attempts) == 1 })
+
+	if err := d.Unsubscribe(sub.ID); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	// 재시도 대기 중인 전송은 실패로 종료하고 예약된 재시도를 취소한다.
+	delivery, _ := d.Delivery(id)
+	if delivery.Status != DeliveryFailed || !delivery.NextAttemptAt.IsZero() {
+		t.Errorf("delivery = %s (next %s), want failed", delivery.Status, delivery.NextAttemptAt)
+	}
+	d.mu.Lock()
+	timers := len(d.timers)
+	d.mu.Unlock()
+	if timers != 0 {
+		t.Errorf("timers = %d, want pending retry canceled", timers)
+	}
+
+	if _, exist := d.Subscription(sub.ID); exist {
+		t.Error("subscription exists after Unsubscribe")
+	}
+	if err := d.Unsubscribe(sub.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Unsubscribe twice = %v, want ErrNotFound", err)
+	}
+	if _, err := d.Redeliver(id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Redeliver = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDispatcherResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	r := newReceiver(t, http.StatusServiceUnavailable)
+
+	d, err := Open(Options{StatePath: path, Backoff: time.Hour, AllowPrivateNetworks: true})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	sub := subscribe(t, d, r.URL)
+	id := publish(t, d, sub, testEvent("evt-1"))
+	waitDelivery(t, d, id, func(d Delivery) bool { return len(d.Attempts) == 1 })
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// 종료 이후 이벤트는 등록하지 않는다.
+	d.Publish(testEvent("evt-2"))
+	if deliveries := d.Deliveries(sub.ID); len(deliveries) != 1 {
+		t.Errorf("deliveries = %d after Close, want 1", len(deliveries))
+	}
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), `"status":"pending"`) || !strings.Contains(string(data), `"secret":"secret"`) {
+		t.Fatalf("state = %s (%v), want pending delivery and secret saved", data, err)
+	}
+
+	// 재기동 시 재시도 대기 중이던 전송을 backoff 없이 다시 전송한다.
+	r.setStatus(http.StatusOK)
+	resumed := openDispatcher(t, Options{StatePath: path, Backoff: time.Hour})
+	delivery := waitDelivery(t, resumed, id, finished)
+	if delivery.Status != DeliverySucceeded || len(delivery.Attempts) != 2 {
+		t.Fatalf("delivery = %s after %d attempts, want succeeded after 2", delivery.Status, len(delivery.Attempts))
+	}
+
+	// 복원된 secret으로 서명한다.
+	r.mu.Lock()
+	req, body := r.requests[1], r.bodies[1]
+	r.mu.Unlock()
+	if got, want := req.Header.Get(SignatureHeader), Sign("secret", req.Header.Get(TimestampHeader), body); got != want {
+		t.Errorf("%s = %s, want %s", SignatureHeader, got, want)
+	}
+	if s, _ := resumed.Subscription(sub.ID); s.URL != r.URL || s.Secret != "" {
+		t.Errorf("subscription = %+v, want restored without secret", s)
+	}
+}
+
+func TestDispatcherPrune(t *testing.T) {
+	d := &Dispatcher{deliveries: map[string]*Delivery{}}
+	status := func(i int) DeliveryStatus {
+		switch {
+		case i < 2:
+			return DeliveryPending
+		case i%2 == 0:
+			return DeliverySucceeded
+		default:
+			return DeliveryFailed
+		}
+	}
+	for i := 0; i < maxDeliveries+3; i++ {
+		id := fmt.Sprintf("whd-%d", i)
+		d.deliveries[id] = &Delivery{ID: id, Status: status(i)}
+		d.order = append(d.order, id)
+	}
+
+	d.prune()
+	// 재시도 대기 중인 전송은 유지하고 오래된 종료 기록부터 삭제한다.
+	if len(d.order) != maxDeliveries || len(d.deliveries) != maxDeliveries {
+		t.Fatalf("deliveries = %d (order %d), want %d", len(d.deliveries), len(d.order), maxDeliveries)
+	}
+	if got := strings.Join(d.order[:3], ","); got != "whd-0,whd-1,whd-5" {
+		t.Errorf("oldest = %s, want pending kept and whd-2~4 pruned", got)
+	}
+	if _, exist := d.deliveries["whd-2"]; exist {
+		t.Error("whd-2 not pruned")
+	}
+}
+
+func TestDispatcherPrivateNetwork(t *testing.T) {
+	d, err := Open(Options{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close(context.Background()) })
+
+	for _, url := range []string{"http://127.0.0.1:8080/", "http://169.254.169.254/", "http://release-notes.devops.svc.cluster.local/"} {
+		if _, err := d.Subscribe(Subscription{URL: url}); !errors.Is(err, ErrPrivateNetwork) {
+			t.Errorf("Subscribe(%s) = %v, want ErrPrivateNetwork", url, err)
+		}
+	}
+	if subs := d.Subscriptions(); len(subs) != 0 {
+		t.Errorf("subscriptions = %+v, want none", subs)
+	}
+}
+
+func TestDispatcherPrivateNetworkDial(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	r := newReceiver(t, http.StatusOK)
+
+	// 내부 주소 허용 시 등록된 구독도 허용 해제 이후에는 연결을 거부하며 재시도하지 않는다.
+	allowed := openDispatcher(t, Options{StatePath: path})
+	sub := subscribe(t, allowed, r.URL)
+	if err := allowed.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	d, err := Open(Options{StatePath: path, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close(context.Background()) })
+
+	delivery := waitDelivery(t, d, publish(t, d, sub, testEvent("evt-1")), finished)
+	if delivery.Status != DeliveryFailed || len(delivery.Attempts) != 1 || !strings.Contains(delivery.Attempts[0].Error, "private network") {
+		t.Errorf("delivery = %+v, want failed without retry", delivery)
+	}
+	if n := r.received(); n != 0 {
+		t.Errorf("received = %d, want connection refused", n)
+	}
+}
@@ -0,0 +1,209 @@
+// Package webhook 배포 lifecycle 이벤트 외부 webhook 전송
+// 이벤트는 CloudEvents 1.0 JSON(structured mode)으로 전송하며, 구독별 secret으로 HMAC 서명하고 실패 시 backoff 후 재시도한다.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// SpecVersion CloudEvents 규격 버전
+	SpecVersion = "1.0"
+	// ContentType CloudEvents structured mode JSON
+	ContentType = "application/cloudevents+json"
+	// TypePrefix 이벤트 type 접두사 (예: devops-relay.deployment.started)
+	TypePrefix = "devops-relay.deployment."
+
+	// SignatureHeader v1=hex(HMAC-SHA256(secret, "v1:<timestamp>:<body>"))
+	SignatureHeader = "X-Relay-Signature"
+	// TimestampHeader 서명 시각 (Unix seconds). 수신 측은 재전송 공격 방지를 위해 시각 차이를 확인한다.
+	TimestampHeader = "X-Relay-Timestamp"
+	// DeliveryHeader 전송 ID (재전송 시 새로 발급, CloudEvents id는 유지)
+	DeliveryHeader = "X-Relay-Delivery"
+)
+
+var (
+	ErrNotFound   = errors.New("webhook: not found")
+	ErrInvalidURL = errors.New("webhook: invalid url")
+	// ErrPrivateNetwork 루프백, 링크 로컬, 사설 및 클러스터 내부 주소 (Options.AllowPrivateNetworks 미설정 시 거부)
+	ErrPrivateNetwork = errors.New("webhook: private network address not allowed")
+)
+
+// carrierGradeNAT 100.64.0.0/10 (일부 클러스터 Pod, Service 대역)
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// internalSuffixes 클러스터 및 사내 DNS 이름 (예: relay.devops.svc.cluster.local)
+var internalSuffixes = []string{".localhost", ".local", ".svc", ".internal"}
+
+// Event CloudEvents 1.0 이벤트
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	// 확장 속성: 배포 대상 애플리케이션, 환경 및 W3C traceparent (Distributed Tracing extension)
+	Application string          `json:"application,omitempty"`
+	Environment string          `json:"environment,omitempty"`
+	TraceParent string          `json:"traceparent,omitempty"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// Name 접두사를 제외한 이벤트 이름 (구독 필터 기준)
+func (e Event) Name() string {
+	return strings.TrimPrefix(e.Type, TypePrefix)
+}
+
+// Subscription webhook 구독. Events, Applications, Environments가 비어 있으면 전체를 수신한다.
+type Subscription struct {
+	ID           string    `json:"id"`
+	URL          string    `json:"url"`
+	Events       []string  `json:"events,omitempty"`
+	Applications []string  `json:"applications,omitempty"`
+	Environments []string  `json:"environments,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// HMAC 서명 secret. API 응답에는 포함하지 않고 상태 파일에만 저장한다.
+	Secret string `json:"-"`
+}
+
+// Matches 구독 필터(이벤트, 애플리케이션, 환경) 일치 여부
+func (s Subscription) Matches(e Event) bool {
+	return matches(s.Events, e.Name()) && matches(s.Applications, e.Application) && matches(s.Environments, e.Environment)
+}
+
+func matches(filter []string, value string) bool {
+	return len(filter) == 0 || slices.Contains(filter, "*") || slices.Contains(filter, value)
+}
+
+// validateURL http(s) 주소만 허용. allowPrivate 미설정 시 내부 주소를 거부한다.
+func validateURL(raw string, allowPrivate bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidURL, raw)
+	}
+	if !allowPrivate && privateHost(u.Hostname()) {
+		return fmt.Errorf("%w: %q", ErrPrivateNetwork, u.Hostname())
+	}
+	return nil
+}
+
+// privateHost 내부 주소 여부. IP가 아닌 경우 localhost, 단일 레이블(클러스터 search domain) 및 내부 DNS 이름을 거부한다.
+func privateHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if ip := net.ParseIP(host); ip != nil {
+		return privateIP(ip)
+	}
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return true
+	}
+	for _, suffix := range internalSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func privateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || carrierGradeNAT.Contains(ip)
+}
+
+// dialControl 연결할 IP가 내부 주소인 경우 거부 (공개 도메인이 내부 주소로 해석되는 경우 및 DNS rebinding 방지)
+func dialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || privateIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateNetwork, host)
+	}
+	return nil
+}
+
+// NewTransport webhook 전송용 Transport. allowPrivate 미설정 시 내부 주소로의 연결을 거부하며,
+// 프록시(HTTP_PROXY)를 거치면 실제 연결 대상을 확인할 수 없으므로 사용하지 않는다.
+func NewTransport(allowPrivate bool) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if allowPrivate {
+		return t
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: dialControl}
+	t.Proxy = nil
+	t.DialContext = dialer.DialContext
+	return t
+}
+
+// Sign 전송 본문 HMAC-SHA256 서명 (SignatureHeader 값)
+func Sign(secret, timestamp string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte("v1:" + timestamp + ":"))
+	h.Write(body)
+	return "v1=" + hex.EncodeToString(h.Sum(nil))
+}
+
+// NewID 구독, 전송 및 이벤트 ID 생성
+func NewID(prefix string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+// NewSecret 구독 생성 시 secret 미지정인 경우 발급하는 서명 secret
+func NewSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// DeliveryStatus 전송 상태
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery 구독별 이벤트 전송 기록
+type Delivery struct {
+	ID             string         `json:"id"`
+	SubscriptionID string         `json:"subscription_id"`
+	EventID        string         `json:"event_id"`
+	EventType      string         `json:"event_type"`
+	Status         DeliveryStatus `json:"status"`
+	// 재전송 요청한 원본 전송 ID
+	RedeliveryOf  string    `json:"redelivery_of,omitempty"`
+	Attempts      []Attempt `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at,omitzero"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// 전송한 CloudEvents 본문 (재전송 시 그대로 사용)
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Attempt 전송 시도 결과. 응답 본문은 최대 1KB까지 민감 정보를 마스킹해 기록한다.
+type Attempt struct {
+	Time       time.Time `json:"time"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Response   string    `json:"response,omitempty"`
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestValidateURL(t *testing.T) {
+	cases := []struct {
+		url     string
+		want    error
+		private bool
+	}{
+		{url: "https://hooks.example.com/relay"},
+		{url: "http://203.0.113.10:8080/relay"},
+		{url: "https://[2001:db8::1]/relay"},
+		{url: "ftp://hooks.example.com/relay", want: ErrInvalidURL},
+		{url: "https:///relay", want: ErrInvalidURL},
+		{url: "hooks.example.com/relay", want: ErrInvalidURL},
+		// 내부 주소는 AllowPrivateNetworks 설정 시에만 허용한다.
+		{url: "http://127.0.0.1:8080/", private: true},
+		{url: "http://[::1]/", private: true},
+		{url: "http://localhost:3000/", private: true},
+		{url: "http://app.localhost/", private: true},
+		{url: "http://10.0.0.12/", private: true},
+		{url: "http://172.16.4.1/", private: true},
+		{url: "http://192.168.0.10/", private: true},
+		{url: "http://100.64.1.1/", private: true},
+		{url: "http://169.254.169.254/latest/meta-data/", private: true},
+		{url: "http://[fe80::1]/", private: true},
+		{url: "http://0.0.0.0/", private: true},
+		{url: "http://[::ffff:127.0.0.1]/", private: true},
+		{url: "http://release-notes/", private: true},
+		{url: "http://release-notes.devops.svc.cluster.local/", private: true},
+		{url: "http://release-notes.devops.svc/", private: true},
+		{url: "http://RELEASE-NOTES.DEVOPS.SVC.CLUSTER.LOCAL./", private: true},
+		{url: "http://metadata.google.internal/", private: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.url, func(t *testing.T) {
+			want := tc.want
+			if tc.private {
+				want = ErrPrivateNetwork
+			}
+			if err := validateURL(tc.url, false); !errors.Is(err, want) || (want == nil && err != nil) {
+				t.Errorf("validateURL(%q) = %v, want %v", tc.url, err, want)
+			}
+			if tc.private {
+				if err := validateURL(tc.url, true); err != nil {
+					t.Errorf("validateURL(%q, allowPrivate) = %v, want nil", tc.url, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	cases := []struct {
+		address string
+		private bool
+	}{
+		{address: "203.0.113.10:443"},
+		{address: "[2001:db8::1]:443"},
+		{address: "127.0.0.1:8080", private: true},
+		{address: "10.100.0.1:80", private: true},
+		{address: "169.254.169.254:80", private: true},
+		{address: "[::1]:443", private: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.address, func(t *testing.T) {
+			if err := dialControl("tcp", tc.address, nil); errors.Is(err, ErrPrivateNetwork) != tc.private {
+				t.Errorf("dialControl(%q) = %v, want private %t", tc.address, err, tc.private)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"specversion":"1.0","id":"evt-1"}`)
+	h := hmac.New(sha256.New, []byte("secret"))
+	h.Write([]byte("v1:1760000000:"))
+	h.Write(body)
+
+	if got, want := Sign("secret", "1760000000", body), "v1="+hex.EncodeToString(h.Sum(nil)); got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+	if Sign("secret", "1760000001", body) == Sign("secret", "1760000000", body) {
+		t.Error("signature does not cover timestamp")
+	}
+}